@@ -2,10 +2,13 @@ package main
 
 import (
 	"bufio"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/DragonEmperor9480/aws_cli_manager/cli"
 	"github.com/DragonEmperor9480/aws_cli_manager/controllers"
 	"github.com/DragonEmperor9480/aws_cli_manager/db_service"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
@@ -19,6 +22,20 @@ func main() {
 		return
 	}
 
+	// Global flags (--profile, --region, --output) apply to both the menu and subcommands
+	args, err := cli.ParseGlobalFlags(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(cli.Run([]string{"help"}))
+		}
+		os.Exit(cli.ExitUsage)
+	}
+
+	// Run a non-interactive subcommand when one is given
+	if len(args) > 0 {
+		os.Exit(cli.Run(args))
+	}
+
 	// Initialize database
 	if err := db_service.InitDB(); err != nil {
 		fmt.Println(utils.Red + "Error initializing database: " + err.Error() + utils.Reset)
@@ -50,7 +67,7 @@ func main() {
 			controllers.IAM_mgr()
 		case "2":
 			fmt.Println()
-			fmt.Println("EC2 MANAGEMENT(WIP)")
+			fmt.Println("EC2 MANAGEMENT")
 			fmt.Println("────────────────────────────────────")
 			controllers.EC2_mgr()
 		case "3":
@@ -156,15 +156,18 @@ class _IAMScreenState extends State<IAMScreen>
         
         setState(() => _operationInProgress = false);
         
-        // Prepare credentials for successful users with passwords
+        // Prepare credentials for successful users with passwords, typed or generated
         final credentials = <Map<String, String>>[];
         for (int i = 0; i < results.length; i++) {
           final apiResult = results[i];
           final inputData = result[i];
-          if (apiResult['Success'] == true && inputData['password'] != null) {
+          final password = apiResult['PasswordGenerated'] == true
+              ? apiResult['Password']
+              : inputData['password'];
+          if (apiResult['Success'] == true && password != null) {
             credentials.add({
               'username': apiResult['Username'],
-              'password': inputData['password'],
+              'password': password,
             });
           }
         }
@@ -1625,6 +1628,8 @@ class _UserEntry {
       'username': usernameController.text,
       if (setPassword) 'password': passwordController.text,
       if (setPassword) 'require_reset': requireReset,
+      // The API generates a password when none is sent, so opt out explicitly
+      if (!setPassword) 'skip_password': true,
     };
   }
 
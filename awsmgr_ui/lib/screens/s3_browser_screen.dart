@@ -33,10 +33,10 @@ class S3Item {
 
   factory S3Item.fromJson(Map<String, dynamic> json) {
     return S3Item(
-      key: json['Key'] ?? '',
-      size: json['Size'] ?? 0,
-      lastModified: json['LastModified'] ?? '',
-      isFolder: json['IsFolder'] ?? false,
+      key: json['key'] ?? '',
+      size: json['size'] ?? 0,
+      lastModified: json['last_modified'] ?? '',
+      isFolder: json['is_folder'] ?? false,
     );
   }
 
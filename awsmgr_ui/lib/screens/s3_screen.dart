@@ -37,25 +37,12 @@ class _S3ScreenState extends State<S3Screen> {
   Future<void> _loadBuckets() async {
     setState(() => _loading = true);
     try {
-      final bucketsStr = await ApiService.listS3Buckets();
-      // Parse bucket info from the string
-      // Format: "2024-11-14 10:30:00 bucket-name"
-      final bucketList = bucketsStr
-          .split('\n')
-          .where((line) => line.trim().isNotEmpty)
-          .map((line) {
-            final parts = line.trim().split(' ');
-            if (parts.length >= 3) {
-              final date = parts[0];
-              final time = parts[1];
-              final name = parts.sublist(2).join(' ');
-              return BucketInfo(
-                name: name,
-                creationDate: '$date $time',
-              );
-            }
-            return BucketInfo(name: line.trim(), creationDate: 'Unknown');
-          })
+      final buckets = await ApiService.listS3Buckets();
+      final bucketList = buckets
+          .map((bucket) => BucketInfo(
+                name: bucket['name'] ?? '',
+                creationDate: bucket['creation_date'] ?? 'Unknown',
+              ))
           .toList();
       
       setState(() => _buckets = bucketList);
@@ -557,28 +544,13 @@ class _BucketObjectsScreenState extends State<BucketObjectsScreen> {
   Future<void> _loadObjects() async {
     setState(() => _loading = true);
     try {
-      final objectsStr = await ApiService.listS3Objects(widget.bucketName);
-      // Parse objects from string
-      // Format: "2024-11-14 10:30:00       1234 file.txt"
-      final objectList = objectsStr
-          .split('\n')
-          .where((line) => line.trim().isNotEmpty)
-          .map((line) {
-            final parts = line.trim().split(RegExp(r'\s+'));
-            if (parts.length >= 4) {
-              final date = parts[0];
-              final time = parts[1];
-              final size = int.tryParse(parts[2]) ?? 0;
-              final key = parts.sublist(3).join(' ');
-              return S3Object(
-                key: key,
-                lastModified: '$date $time',
-                size: size,
-              );
-            }
-            return null;
-          })
-          .whereType<S3Object>()
+      final objects = await ApiService.listS3Objects(widget.bucketName);
+      final objectList = objects
+          .map((object) => S3Object(
+                key: object['key'] ?? '',
+                lastModified: object['last_modified'] ?? '',
+                size: (object['size'] as num?)?.toInt() ?? 0,
+              ))
           .toList();
       
       setState(() => _objects = objectList);
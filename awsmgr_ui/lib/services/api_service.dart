@@ -365,11 +365,11 @@ class ApiService {
   }
 
   // S3 Buckets
-  static Future<String> listS3Buckets() async {
+  static Future<List<dynamic>> listS3Buckets() async {
     final response = await http.get(Uri.parse('$baseUrl/s3/buckets'));
     if (response.statusCode == 200) {
       final data = json.decode(response.body);
-      return data['buckets'] ?? '';
+      return data['buckets'] ?? [];
     }
     throw Exception('Failed to load buckets');
   }
@@ -394,13 +394,13 @@ class ApiService {
     }
   }
 
-  static Future<String> listS3Objects(String bucketname) async {
+  static Future<List<dynamic>> listS3Objects(String bucketname) async {
     final response = await http.get(
       Uri.parse('$baseUrl/s3/buckets/$bucketname/objects'),
     );
     if (response.statusCode == 200) {
       final data = json.decode(response.body);
-      return data['objects'] ?? '';
+      return data['objects'] ?? [];
     }
     throw Exception('Failed to load objects');
   }
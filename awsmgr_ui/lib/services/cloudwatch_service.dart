@@ -33,7 +33,11 @@ class CloudWatchService {
     
     if (response.statusCode == 200) {
       final data = json.decode(response.body);
-      return List<String>.from(data['functions'] ?? []);
+      final functions = data['functions'] as List? ?? [];
+      return functions
+          .map((fn) => fn['function_name']?.toString() ?? '')
+          .where((name) => name.isNotEmpty)
+          .toList();
     }
     throw Exception('Failed to load Lambda functions');
   }
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/db_service"
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 )
 
 // ConfigureAWS configures AWS credentials
@@ -121,3 +123,121 @@ func GetAWSConfig(w http.ResponseWriter, r *http.Request) {
 		"message":    "AWS credentials configured",
 	})
 }
+
+// ListAWSProfiles lists the profiles found in the shared AWS config files
+func ListAWSProfiles(w http.ResponseWriter, r *http.Request) {
+	profiles, err := utils.ListAWSProfiles()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"profiles":        profiles,
+		"current_profile": utils.GetCurrentProfile(),
+		"current_region":  utils.GetCurrentRegion(),
+	})
+}
+
+// SwitchAWSProfile re-creates all AWS clients for another profile and/or region
+func SwitchAWSProfile(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Profile string `json:"profile"`
+		Region  string `json:"region"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Profile == "" && req.Region == "" {
+		respondError(w, http.StatusBadRequest, "profile or region is required")
+		return
+	}
+
+	var err error
+	if req.Profile == "" {
+		err = utils.SwitchAWSRegion(req.Region)
+	} else {
+		err = utils.SwitchAWSProfile(req.Profile, req.Region)
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "AWS profile switched",
+		"profile": utils.GetCurrentProfile(),
+		"region":  utils.GetCurrentRegion(),
+	})
+}
+
+// GetAWSSession returns the active STS session, if any
+func GetAWSSession(w http.ResponseWriter, r *http.Request) {
+	session := utils.GetAWSSession()
+	if session == nil {
+		respondJSON(w, http.StatusOK, map[string]interface{}{"active": false})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"active":  !session.Expired(),
+		"session": session,
+	})
+}
+
+// StartAWSSession obtains STS session credentials with GetSessionToken or AssumeRole
+func StartAWSSession(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RoleARN         string `json:"role_arn"`
+		ExternalID      string `json:"external_id"`
+		MFACode         string `json:"mfa_code"`
+		UseMFA          bool   `json:"use_mfa"`
+		DurationSeconds int32  `json:"duration_seconds"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opts := utils.SessionOptions{
+		RoleARN:         req.RoleARN,
+		ExternalID:      req.ExternalID,
+		TokenCode:       req.MFACode,
+		DurationSeconds: req.DurationSeconds,
+	}
+
+	// GetSessionToken always needs the MFA device; AssumeRole only when requested
+	if req.RoleARN == "" || req.UseMFA || req.MFACode != "" {
+		device, err := service.LoadMFADevice()
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts.MFASerial = device.DeviceARN
+	}
+
+	session, err := utils.StartAWSSession(opts)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Session started",
+		"session": session,
+	})
+}
+
+// EndAWSSession drops the STS session and returns to the profile's own credentials
+func EndAWSSession(w http.ResponseWriter, r *http.Request) {
+	if err := utils.EndAWSSession(); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Session ended"})
+}
@@ -8,28 +8,36 @@ import (
 	"time"
 
 	cloudwatch_model "github.com/DragonEmperor9480/aws_cli_manager/models/cloudwatch"
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 	"github.com/gorilla/mux"
 )
 
-// ListLambdaFunctions lists all Lambda functions
+// ListLambdaFunctions lists all Lambda functions, optionally across the accounts in ?accounts=
 func ListLambdaFunctions(w http.ResponseWriter, r *http.Request) {
-	output, err := cloudwatch_model.FetchLambdaFunctions()
+	selected, err := service.ResolveAccounts(r.URL.Query().Get("accounts"))
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if selected != nil {
+		functions, failures := cloudwatch_model.FetchLambdaFunctionsAcrossAccounts(selected)
+		respondAccountList(w, r, "functions", functions, failures, views.TableConfig{
+			Headers: cloudwatch_model.AccountLambdaFunctionTableHeaders,
+			Rows:    views.RowsOf(functions),
+		})
 		return
 	}
 
-	// Parse function names
-	functions := []string{}
-	lines := string(output)
-	for _, line := range splitLines(lines) {
-		if line != "" {
-			functions = append(functions, line)
-		}
+	functions, err := cloudwatch_model.FetchLambdaFunctions()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"functions": functions,
+	respondList(w, r, "functions", functions, views.TableConfig{
+		Headers: cloudwatch_model.LambdaFunctionTableHeaders,
+		Rows:    views.RowsOf(functions),
 	})
 }
 
@@ -102,20 +110,3 @@ func StreamLambdaLogs(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
-
-func splitLines(s string) []string {
-	result := []string{}
-	current := ""
-	for _, ch := range s {
-		if ch == '\n' {
-			result = append(result, current)
-			current = ""
-		} else {
-			current += string(ch)
-		}
-	}
-	if current != "" {
-		result = append(result, current)
-	}
-	return result
-}
@@ -0,0 +1,640 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+	"github.com/gorilla/mux"
+)
+
+// ListEC2Instances returns all EC2 instances in the current region
+func ListEC2Instances(w http.ResponseWriter, r *http.Request) {
+	instances, err := ec2.FetchEC2Instances()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "instances", instances, views.TableConfig{
+		Headers: ec2.EC2InstanceTableHeaders,
+		Rows:    views.RowsOf(instances),
+	})
+}
+
+// GetEC2Instance returns a single EC2 instance
+func GetEC2Instance(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["id"]
+
+	instance, err := ec2.GetEC2Instance(instanceID)
+	if errors.Is(err, ec2.ErrInstanceNotFound) {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, instance)
+}
+
+// LaunchEC2Instances launches instances from an AMI
+func LaunchEC2Instances(w http.ResponseWriter, r *http.Request) {
+	var req ec2.LaunchInstanceOptions
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.ImageID == "" {
+		respondError(w, http.StatusBadRequest, "image_id is required")
+		return
+	}
+
+	instances, err := ec2.LaunchEC2Instances(req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message":   "Instances launched successfully",
+		"instances": instances,
+	})
+}
+
+// StartEC2Instance starts a stopped instance
+func StartEC2Instance(w http.ResponseWriter, r *http.Request) {
+	changes, err := ec2.StartEC2Instances([]string{mux.Vars(r)["id"]})
+	respondStateChanges(w, changes, err)
+}
+
+// StopEC2Instance stops a running instance
+func StopEC2Instance(w http.ResponseWriter, r *http.Request) {
+	changes, err := ec2.StopEC2Instances([]string{mux.Vars(r)["id"]})
+	respondStateChanges(w, changes, err)
+}
+
+// RebootEC2Instance requests a reboot of a running instance
+func RebootEC2Instance(w http.ResponseWriter, r *http.Request) {
+	instanceID := mux.Vars(r)["id"]
+
+	if err := ec2.RebootEC2Instances([]string{instanceID}); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Reboot requested for " + instanceID})
+}
+
+// TerminateEC2Instance terminates an instance
+func TerminateEC2Instance(w http.ResponseWriter, r *http.Request) {
+	changes, err := ec2.TerminateEC2Instances([]string{mux.Vars(r)["id"]})
+	respondStateChanges(w, changes, err)
+}
+
+func respondStateChanges(w http.ResponseWriter, changes []ec2.InstanceStateChange, err error) {
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"instances": changes})
+}
+
+// ListEC2KeyPairs returns the key pairs available for launching instances
+func ListEC2KeyPairs(w http.ResponseWriter, r *http.Request) {
+	keyPairs, err := ec2.FetchKeyPairs()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "key_pairs", keyPairs, views.TableConfig{
+		Headers: ec2.KeyPairTableHeaders,
+		Rows:    views.RowsOf(keyPairs),
+	})
+}
+
+// ListEC2Subnets returns the subnets available for launching instances
+func ListEC2Subnets(w http.ResponseWriter, r *http.Request) {
+	subnets, err := ec2.FetchSubnets()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "subnets", subnets, views.TableConfig{
+		Headers: ec2.SubnetTableHeaders,
+		Rows:    views.RowsOf(subnets),
+	})
+}
+
+// ListEBSVolumes returns all EBS volumes in the current region
+func ListEBSVolumes(w http.ResponseWriter, r *http.Request) {
+	volumes, err := ec2.FetchEBSVolumes()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "volumes", volumes, views.TableConfig{
+		Headers: ec2.EBSVolumeTableHeaders,
+		Rows:    views.RowsOf(volumes),
+	})
+}
+
+// CreateEBSVolume creates a new volume, empty or from a snapshot
+func CreateEBSVolume(w http.ResponseWriter, r *http.Request) {
+	var req ec2.CreateVolumeOptions
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.AvailabilityZone == "" {
+		respondError(w, http.StatusBadRequest, "availability_zone is required")
+		return
+	}
+
+	volume, err := ec2.CreateEBSVolume(req)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, volume)
+}
+
+// AttachEBSVolume attaches a volume to an instance
+func AttachEBSVolume(w http.ResponseWriter, r *http.Request) {
+	volumeID := mux.Vars(r)["id"]
+
+	var req struct {
+		InstanceID string `json:"instance_id"`
+		Device     string `json:"device"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.InstanceID == "" || req.Device == "" {
+		respondError(w, http.StatusBadRequest, "instance_id and device are required")
+		return
+	}
+
+	if err := ec2.AttachEBSVolume(volumeID, req.InstanceID, req.Device); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Volume " + volumeID + " attaching to " + req.InstanceID})
+}
+
+// DetachEBSVolume detaches a volume from its instance
+func DetachEBSVolume(w http.ResponseWriter, r *http.Request) {
+	volumeID := mux.Vars(r)["id"]
+
+	var req struct {
+		Force bool `json:"force"`
+	}
+
+	// The body is optional
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if err := ec2.DetachEBSVolume(volumeID, req.Force); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Volume " + volumeID + " detaching"})
+}
+
+// DeleteEBSVolume deletes an unattached volume
+func DeleteEBSVolume(w http.ResponseWriter, r *http.Request) {
+	volumeID := mux.Vars(r)["id"]
+
+	if err := ec2.DeleteEBSVolume(volumeID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Volume " + volumeID + " deleted"})
+}
+
+// ListEBSSnapshots returns the account's EBS snapshots in the current region
+func ListEBSSnapshots(w http.ResponseWriter, r *http.Request) {
+	snapshots, err := ec2.FetchEBSSnapshots()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "snapshots", snapshots, views.TableConfig{
+		Headers: ec2.EBSSnapshotTableHeaders,
+		Rows:    views.RowsOf(snapshots),
+	})
+}
+
+// CreateEBSSnapshot snapshots a volume with a description and tags
+func CreateEBSSnapshot(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		VolumeID    string            `json:"volume_id"`
+		Description string            `json:"description"`
+		Tags        map[string]string `json:"tags"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.VolumeID == "" {
+		respondError(w, http.StatusBadRequest, "volume_id is required")
+		return
+	}
+
+	snapshot, err := ec2.CreateEBSSnapshot(req.VolumeID, req.Description, req.Tags)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, snapshot)
+}
+
+// CopyEBSSnapshot copies a snapshot to another region
+func CopyEBSSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshotID := mux.Vars(r)["id"]
+
+	var req struct {
+		Region      string `json:"region"`
+		Description string `json:"description"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Region == "" {
+		respondError(w, http.StatusBadRequest, "region is required")
+		return
+	}
+
+	newSnapshotID, err := ec2.CopyEBSSnapshot(snapshotID, req.Region, req.Description)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message":     "Snapshot copy started",
+		"snapshot_id": newSnapshotID,
+		"region":      req.Region,
+	})
+}
+
+// DeleteEBSSnapshot deletes a snapshot
+func DeleteEBSSnapshot(w http.ResponseWriter, r *http.Request) {
+	snapshotID := mux.Vars(r)["id"]
+
+	if err := ec2.DeleteEBSSnapshot(snapshotID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Snapshot " + snapshotID + " deleted"})
+}
+
+// ListAMIImages returns owned and/or shared AMIs.
+// Query parameters: ownership (owned|shared|all), name, min_age_days, max_age_days
+func ListAMIImages(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := ec2.ImageFilter{
+		Ownership:   query.Get("ownership"),
+		NamePattern: query.Get("name"),
+	}
+
+	switch filter.Ownership {
+	case "", ec2.ImageOwnershipOwned, ec2.ImageOwnershipShared, ec2.ImageOwnershipAll:
+	default:
+		respondError(w, http.StatusBadRequest, "ownership must be owned, shared or all")
+		return
+	}
+
+	var err error
+	if filter.MinAgeDays, err = queryInt(r, "min_age_days"); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if filter.MaxAgeDays, err = queryInt(r, "max_age_days"); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	images, err := ec2.FetchAMIImages(filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "images", images, views.TableConfig{
+		Headers: ec2.AMIImageTableHeaders,
+		Rows:    views.RowsOf(images),
+	})
+}
+
+// CheckAMIImageDependencies checks what still refers to an image before deregistration
+func CheckAMIImageDependencies(w http.ResponseWriter, r *http.Request) {
+	imageID := mux.Vars(r)["id"]
+
+	deps, err := ec2.CheckImageDependencies(imageID)
+	if errors.Is(err, ec2.ErrImageNotFound) {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"image_id":         imageID,
+		"dependencies":     deps,
+		"has_dependencies": deps.HasDependencies(),
+	})
+}
+
+// DeregisterAMIImage deregisters an image and deletes its snapshots.
+// Query parameters: force=true to ignore dependencies, keep_snapshots=true to keep the snapshots
+func DeregisterAMIImage(w http.ResponseWriter, r *http.Request) {
+	imageID := mux.Vars(r)["id"]
+	force := r.URL.Query().Get("force") == "true"
+	keepSnapshots := r.URL.Query().Get("keep_snapshots") == "true"
+
+	result := ec2.DeregisterImageWithSnapshots(imageID, force, keepSnapshots)
+	if !result.Success {
+		respondJSON(w, http.StatusConflict, result)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// DeregisterMultipleAMIImages deregisters several images in parallel
+func DeregisterMultipleAMIImages(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ImageIDs      []string `json:"image_ids"`
+		Force         bool     `json:"force"`
+		KeepSnapshots bool     `json:"keep_snapshots"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.ImageIDs) == 0 {
+		respondError(w, http.StatusBadRequest, "image_ids is required")
+		return
+	}
+
+	results := ec2.DeregisterMultipleImages(req.ImageIDs, req.Force, req.KeepSnapshots)
+
+	successCount := 0
+	for _, result := range results {
+		if result.Success {
+			successCount++
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"results":       results,
+		"success_count": successCount,
+		"failure_count": len(results) - successCount,
+	})
+}
+
+// ListVPCs returns the VPCs in the current region
+func ListVPCs(w http.ResponseWriter, r *http.Request) {
+	vpcs, err := ec2.FetchVPCs()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "vpcs", vpcs, views.TableConfig{
+		Headers: ec2.VPCTableHeaders,
+		Rows:    views.RowsOf(vpcs),
+	})
+}
+
+// ListSecurityGroups returns the security groups, optionally filtered with ?vpc_id=
+func ListSecurityGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := ec2.FetchSecurityGroups(r.URL.Query().Get("vpc_id"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "security_groups", groups, views.TableConfig{
+		Headers: ec2.SecurityGroupTableHeaders,
+		Rows:    views.RowsOf(groups),
+	})
+}
+
+// ListSecurityGroupRules returns the inbound and outbound rules of a security group
+func ListSecurityGroupRules(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["id"]
+
+	rules, err := ec2.FetchSecurityGroupRules(groupID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "rules", rules, views.TableConfig{
+		Headers: ec2.SecurityGroupRuleTableHeaders,
+		Rows:    views.RowsOf(rules),
+	})
+}
+
+// ListRiskyRules returns the rules that expose SSH, RDP or all traffic to the internet
+func ListRiskyRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := ec2.FetchRiskyRules()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "rules", rules, views.TableConfig{
+		Headers: ec2.SecurityGroupRuleTableHeaders,
+		Rows:    views.RowsOf(rules),
+	})
+}
+
+// AddSecurityGroupRule adds an inbound or outbound rule to a security group
+func AddSecurityGroupRule(w http.ResponseWriter, r *http.Request) {
+	groupID := mux.Vars(r)["id"]
+
+	var req ec2.RuleInput
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := ec2.AddSecurityGroupRule(groupID, req); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]string{"message": "Rule added to " + groupID})
+}
+
+// RevokeSecurityGroupRule removes a rule from a security group
+func RevokeSecurityGroupRule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupID := vars["id"]
+	ruleID := vars["rule_id"]
+
+	if err := ec2.RevokeSecurityGroupRule(groupID, ruleID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Rule " + ruleID + " revoked from " + groupID})
+}
+
+// ListManagedInstances returns the instances registered with Systems Manager
+func ListManagedInstances(w http.ResponseWriter, r *http.Request) {
+	instances, err := ec2.FetchManagedInstances()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "instances", instances, views.TableConfig{
+		Headers: ec2.ManagedInstanceTableHeaders,
+		Rows:    views.RowsOf(instances),
+	})
+}
+
+// RunShellCommand sends AWS-RunShellScript to one or more instances.
+// The output is streamed from /api/ec2/commands/{id}/output.
+func RunShellCommand(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		InstanceIDs    []string `json:"instance_ids"`
+		Commands       []string `json:"commands"`
+		TimeoutSeconds int      `json:"timeout_seconds"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.InstanceIDs) == 0 || len(req.Commands) == 0 {
+		respondError(w, http.StatusBadRequest, "instance_ids and commands are required")
+		return
+	}
+
+	commandID, err := ec2.RunShellCommand(req.InstanceIDs, req.Commands, req.TimeoutSeconds)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]string{"command_id": commandID})
+}
+
+// StreamCommandOutput streams the output of a command using Server-Sent Events (SSE)
+func StreamCommandOutput(w http.ResponseWriter, r *http.Request) {
+	commandID := mux.Vars(r)["id"]
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	outputChan := make(chan ec2.CommandOutput, 100)
+	errChan := make(chan error, 1)
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	go ec2.StreamCommandOutput(ctx, commandID, outputChan, errChan)
+
+	writeEvent := func(event interface{}) {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	writeEvent(map[string]string{"type": "connected", "command_id": commandID})
+
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fmt.Fprintf(w, ": keepalive\n\n")
+			flusher.Flush()
+		case output := <-outputChan:
+			writeEvent(map[string]interface{}{"type": "output", "output": output})
+		case err := <-errChan:
+			// Output sent before the final error may still be buffered
+			for len(outputChan) > 0 {
+				writeEvent(map[string]interface{}{"type": "output", "output": <-outputChan})
+			}
+
+			if err != nil {
+				writeEvent(map[string]string{"type": "error", "error": err.Error()})
+			} else {
+				writeEvent(map[string]string{"type": "done"})
+			}
+			return
+		}
+	}
+}
+
+// queryInt reads an optional non-negative integer query parameter
+func queryInt(r *http.Request, name string) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return number, nil
+}
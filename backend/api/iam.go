@@ -1,28 +1,65 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/group"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/hygiene"
 	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/role"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/tag"
 	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
 	"github.com/DragonEmperor9480/aws_cli_manager/service"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 	"github.com/gorilla/mux"
 )
 
-// ListIAMUsers returns all IAM users
+// ListIAMUsers returns all IAM users with their tags, optionally across the accounts in
+// ?accounts= and only those matching ?tag=key=value (or ?tag=key for any value)
 func ListIAMUsers(w http.ResponseWriter, r *http.Request) {
-	output, err := user.FetchIAMUsers()
+	selected, err := service.ResolveAccounts(r.URL.Query().Get("accounts"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var filter *tag.Filter
+	if value := r.URL.Query().Get("tag"); value != "" {
+		parsed, err := tag.ParseFilter(value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter = &parsed
+	}
+
+	if selected != nil {
+		users, warnings, failures := user.FetchIAMUsersAcrossAccounts(selected, filter)
+		respondListWith(w, r, "users", users, map[string]interface{}{"account_errors": failures, "warnings": warnings}, views.TableConfig{
+			Headers: user.AccountIAMUserTableHeaders,
+			Rows:    views.RowsOf(users),
+		})
+		return
+	}
+
+	users, warnings, err := user.FetchIAMUsersWithTags(filter)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	users := parseTabSeparated(output, []string{"username", "user_id", "create_date"})
-	respondJSON(w, http.StatusOK, map[string]interface{}{"users": users})
+	respondListWith(w, r, "users", users, map[string]interface{}{"warnings": warnings}, views.TableConfig{
+		Headers: user.IAMUserTableHeaders,
+		Rows:    views.RowsOf(users),
+	})
 }
 
 // CreateMultipleIAMUsers creates multiple IAM users in parallel
@@ -30,8 +67,10 @@ func CreateMultipleIAMUsers(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Users []struct {
 			Username     string `json:"username"`
-			Password     string `json:"password"`
+			Password     string `json:"password"` // Generated when omitted
 			RequireReset bool   `json:"require_reset"`
+			SkipPassword bool   `json:"skip_password"` // Create the user without console access
+			Email        string `json:"email"`         // Where to send a generated password
 		} `json:"users"`
 	}
 
@@ -52,6 +91,8 @@ func CreateMultipleIAMUsers(w http.ResponseWriter, r *http.Request) {
 			Username:     u.Username,
 			Password:     u.Password,
 			RequireReset: u.RequireReset,
+			SkipPassword: u.SkipPassword,
+			Email:        u.Email,
 		}
 	}
 
@@ -78,6 +119,52 @@ func CreateMultipleIAMUsers(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ImportIAMUsers onboards users from a JSON body ({"users": [...]}) or, with a text/csv
+// content type, from CSV with a header row. Each user is created with its tags, password,
+// groups and policies, and generated credentials are emailed when requested.
+func ImportIAMUsers(w http.ResponseWriter, r *http.Request) {
+	var rows []user.UserImportRow
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		rows, err = user.ParseUserImportCSV(r.Body)
+	} else {
+		var body []byte
+		if body, err = io.ReadAll(r.Body); err == nil {
+			rows, err = user.ParseUserImportJSON(body)
+		}
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Rows that fail validation or name unknown policies reject the whole import
+	results, err := user.ImportUsers(rows)
+	if errors.Is(err, user.ErrInvalidImport) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	successCount := 0
+	for _, result := range results {
+		if result.Success {
+			successCount++
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":       "User import completed",
+		"total":         len(results),
+		"success_count": successCount,
+		"failure_count": len(results) - successCount,
+		"results":       results,
+	})
+}
+
 // CreateIAMUser creates a new IAM user with optional password
 func CreateIAMUser(w http.ResponseWriter, r *http.Request) {
 	var req struct {
@@ -98,6 +185,10 @@ func CreateIAMUser(w http.ResponseWriter, r *http.Request) {
 
 	// If password is provided, use combined function
 	if req.Password != "" {
+		if rejectPolicyViolations(w, req.Password) {
+			return
+		}
+
 		userStatus, passwordStatus, err := user.CreateIAMUserWithPassword(req.Username, req.Password, req.RequireReset)
 
 		// Check user creation status first
@@ -265,12 +356,16 @@ func DeleteIAMUser(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	err := user.DeleteIAMUserAPI(username)
+	steps, err := user.DeleteIAMUserAPI(username)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{
+			"error":    err.Error(),
+			"username": username,
+			"steps":    steps,
+		})
 		return
 	}
-	respondJSON(w, http.StatusOK, map[string]string{"message": "User deleted", "username": username})
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "User deleted", "username": username, "steps": steps})
 }
 
 // SetUserPassword sets initial password for a user
@@ -293,6 +388,10 @@ func SetUserPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if rejectPolicyViolations(w, req.Password) {
+		return
+	}
+
 	status, err := user.SetInitialUserPasswordModel(username, req.Password, req.RequireReset)
 
 	switch status {
@@ -331,46 +430,57 @@ func UpdateUserPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Password == "" {
+		respondError(w, http.StatusBadRequest, "password is required")
+		return
+	}
+	if rejectPolicyViolations(w, req.Password) {
+		return
+	}
+
 	user.UpdateUserPasswordModel(username, req.Password)
 	respondJSON(w, http.StatusOK, map[string]string{"message": "Password updated", "username": username})
 }
 
-// CreateAccessKey creates access key for user
+// CreateAccessKey creates access key for user.
+// The secret is only returned by this call.
 func CreateAccessKey(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	username := vars["username"]
 
-	user.CreateAccessKeyForUserModel(username)
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Access key created", "username": username})
+	key, err := user.CreateAccessKey(username)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]interface{}{"message": "Access key created", "access_key": key})
 }
 
-// ListAccessKeys lists access keys for user
+// ListAccessKeys lists access keys for user, with when each was last used
 func ListAccessKeys(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	username := vars["username"]
 
-	user.ListAccessKeysForUserModel(username)
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Access keys listed", "username": username})
-}
-
-// ============ IAM GROUPS ============
-
-// ListIAMGroups returns all IAM groups
-func ListIAMGroups(w http.ResponseWriter, r *http.Request) {
-	output, err := group.FetchIAMGroups()
+	keys, err := user.FetchAccessKeys(username)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	groups := parseTabSeparated(output, []string{"groupname", "group_id", "create_date"})
-	respondJSON(w, http.StatusOK, map[string]interface{}{"groups": groups})
+	respondList(w, r, "access_keys", keys, views.TableConfig{
+		Headers: user.AccessKeyTableHeaders,
+		Rows:    views.RowsOf(keys),
+	})
 }
 
-// CreateIAMGroup creates a new IAM group
-func CreateIAMGroup(w http.ResponseWriter, r *http.Request) {
+// UpdateAccessKey activates or deactivates an access key
+func UpdateAccessKey(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	username := vars["username"]
+	accessKeyID := vars["id"]
+
 	var req struct {
-		GroupName string `json:"groupname"`
+		Status string `json:"status"` // Active or Inactive
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -378,230 +488,1437 @@ func CreateIAMGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.GroupName == "" {
-		respondError(w, http.StatusBadRequest, "groupname is required")
+	if req.Status != "Active" && req.Status != "Inactive" {
+		respondError(w, http.StatusBadRequest, "status must be Active or Inactive")
 		return
 	}
 
-	group.CreateIAMGroup(req.GroupName)
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Group created", "groupname": req.GroupName})
-}
-
-// DeleteIAMGroup deletes an IAM group
-func DeleteIAMGroup(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	groupname := vars["groupname"]
-
-	// Check if force delete is requested
-	force := r.URL.Query().Get("force") == "true"
-
-	if force {
-		err := group.ForceDeleteGroup(groupname)
-		if err != nil {
-			respondError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-	} else {
-		group.DeleteGroupModel(groupname)
-	}
-
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Group deleted", "groupname": groupname})
-}
-
-// CheckGroupDependencies checks if a group has dependencies
-func CheckGroupDependencies(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	groupname := vars["groupname"]
-
-	deps, err := group.CheckGroupDependencies(groupname)
-	if err != nil {
+	if err := user.SetAccessKeyStatus(username, accessKeyID, req.Status == "Active"); err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-
-	respondJSON(w, http.StatusOK, deps)
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Access key updated", "access_key_id": accessKeyID, "status": req.Status})
 }
 
-// AddUserToGroup adds a user to a group
-func AddUserToGroup(w http.ResponseWriter, r *http.Request) {
+// DeleteAccessKey deletes an access key
+func DeleteAccessKey(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	groupname := vars["groupname"]
-
-	var req struct {
-		Username string `json:"username"`
-	}
+	username := vars["username"]
+	accessKeyID := vars["id"]
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if err := user.DeleteAccessKey(username, accessKeyID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-
-	group.AddUserToGroupModel(req.Username, groupname)
-	respondJSON(w, http.StatusOK, map[string]string{"message": "User added to group", "username": req.Username, "groupname": groupname})
-}
-
-// RemoveUserFromGroup removes a user from a group
-func RemoveUserFromGroup(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	groupname := vars["groupname"]
-	username := vars["username"]
-
-	group.RemoveUserFromGroupModel(username, groupname)
-	respondJSON(w, http.StatusOK, map[string]string{"message": "User removed from group", "username": username, "groupname": groupname})
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Access key deleted", "access_key_id": accessKeyID})
 }
 
-// ListUsersInGroup lists all users in a group
-func ListUsersInGroup(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	groupname := vars["groupname"]
-
-	group.ListUsersInGroupModel(groupname)
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Users listed", "groupname": groupname})
-}
+// maxRotationWait caps how long a rotation request waits for the new key to be used
+const maxRotationWait = 10 * time.Minute
 
-// ListUserGroups lists all groups for a user
-func ListUserGroups(w http.ResponseWriter, r *http.Request) {
+// RotateAccessKey replaces an access key: it creates a new key and optionally emails it.
+// The old key stays active unless retire_old is set, or wait_for_use_seconds is set and the
+// new key is used within that window; it is then deactivated and, unless keep_old is set,
+// deleted. Otherwise the caller retires it later through
+// PUT/DELETE /api/iam/users/{username}/access-keys/{id}.
+func RotateAccessKey(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	username := vars["username"]
-
-	groups := group.ListUserGroupsModel(username)
-	respondJSON(w, http.StatusOK, map[string]interface{}{"username": username, "groups": groups})
-}
-
-// AttachGroupPolicy attaches a policy to a group
-func AttachGroupPolicy(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	groupname := vars["groupname"]
+	oldKeyID := vars["id"]
 
 	var req struct {
-		PolicyArn string `json:"policy_arn"`
+		Email             string `json:"email"`
+		WaitForUseSeconds int    `json:"wait_for_use_seconds"`
+		RetireOld         bool   `json:"retire_old"`
+		KeepOld           bool   `json:"keep_old"` // deactivate the old key without deleting it
 	}
 
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if req.PolicyArn == "" {
-		respondError(w, http.StatusBadRequest, "policy_arn is required")
+	newKey, err := user.StartAccessKeyRotation(username, oldKeyID)
+	if errors.Is(err, user.ErrAccessKeyNotOwned) || errors.Is(err, user.ErrAccessKeyLimit) {
+		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	err := group.AttachGroupPolicy(groupname, req.PolicyArn)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy attached", "groupname": groupname, "policy_arn": req.PolicyArn})
-}
+	steps := []user.UserStepResult{{Action: "create access key", Resource: newKey.AccessKeyID, Success: true}}
 
-// DetachGroupPolicy detaches a policy from a group
-func DetachGroupPolicy(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	groupname := vars["groupname"]
-	policyArn := vars["policy_arn"]
+	if req.Email != "" {
+		step := user.UserStepResult{Action: "email access key", Resource: req.Email, Success: true}
+		emailConfig, err := service.LoadEmailConfig()
+		if err == nil {
+			err = service.SendAccessKeyEmail(emailConfig, username, req.Email, newKey.AccessKeyID, newKey.SecretAccessKey)
+		}
+		if err != nil {
+			step.Success = false
+			step.Error = err.Error()
+		}
+		steps = append(steps, step)
+	}
 
-	err := group.DetachGroupPolicy(groupname, policyArn)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
-		return
+	retire := req.RetireOld
+	if req.WaitForUseSeconds > 0 {
+		wait := time.Duration(req.WaitForUseSeconds) * time.Second
+		if wait > maxRotationWait {
+			wait = maxRotationWait
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), wait)
+		err := user.WaitForAccessKeyUse(ctx, newKey.AccessKeyID, 5*time.Second)
+		cancel()
+
+		step := user.UserStepResult{Action: "wait for new key use", Resource: newKey.AccessKeyID, Success: err == nil}
+		if err != nil {
+			step.Error = err.Error()
+		}
+		retire = err == nil
+		steps = append(steps, step)
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy detached", "groupname": groupname, "policy_arn": policyArn})
+	if retire {
+		steps = append(steps, user.FinishAccessKeyRotation(username, oldKeyID, req.KeepOld)...)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"username":        username,
+		"access_key":      newKey,
+		"old_key_retired": retire && len(user.FailedSteps(steps)) == 0,
+		"steps":           steps,
+	})
 }
 
-// ListGroupPolicies lists all policies attached to a group
-func ListGroupPolicies(w http.ResponseWriter, r *http.Request) {
+// ListUserMFADevices lists the MFA devices assigned to a user
+func ListUserMFADevices(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
-	groupname := vars["groupname"]
+	username := vars["username"]
 
-	policies, err := group.ListGroupPolicies(groupname)
+	devices, err := user.FetchMFADevices(username)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{"groupname": groupname, "policies": policies})
+	respondList(w, r, "mfa_devices", devices, views.TableConfig{
+		Headers: user.MFADeviceTableHeaders,
+		Rows:    views.RowsOf(devices),
+	})
 }
 
-// ListIAMPolicies lists all IAM policies
-func ListIAMPolicies(w http.ResponseWriter, r *http.Request) {
-	// Get scope from query parameter (All, AWS, or Local)
-	scope := r.URL.Query().Get("scope")
-	if scope == "" {
-		scope = "All"
+// CreateVirtualMFADevice creates a virtual MFA device for a user. The response carries the
+// seed and QR code, or with ?format=png only the QR code image, with the serial number
+// in the X-MFA-Serial-Number header. The device must then be enabled with two codes.
+func CreateVirtualMFADevice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	var req struct {
+		DeviceName string `json:"device_name"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 	}
 
-	policies, err := policy.ListPoliciesModel(scope)
+	enrollment, err := user.CreateVirtualMFADevice(username, req.DeviceName)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"policies": policies,
-		"count":    len(policies),
-	})
+	if r.URL.Query().Get("format") == "png" {
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("X-MFA-Serial-Number", enrollment.SerialNumber)
+		w.WriteHeader(http.StatusCreated)
+		w.Write(enrollment.QRCodePNG)
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]interface{}{"message": "Virtual MFA device created", "mfa_device": enrollment})
 }
 
-// AttachUserPolicy attaches a single policy to a user
-func AttachUserPolicy(w http.ResponseWriter, r *http.Request) {
+// EnableUserMFADevice assigns an MFA device to a user with two consecutive codes
+func EnableUserMFADevice(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	username := vars["username"]
 
 	var req struct {
-		PolicyArn string `json:"policy_arn"`
+		SerialNumber string `json:"serial_number"`
+		Code1        string `json:"code1"`
+		Code2        string `json:"code2"`
 	}
-
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	if req.PolicyArn == "" {
-		respondError(w, http.StatusBadRequest, "policy_arn is required")
+	if req.SerialNumber == "" {
+		respondError(w, http.StatusBadRequest, "serial_number is required")
 		return
 	}
 
-	if err := policy.AttachUserPolicy(username, req.PolicyArn); err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+	if err := user.EnableMFADevice(username, req.SerialNumber, req.Code1, req.Code2); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	respondJSON(w, http.StatusOK, map[string]interface{}{
-		"message":    "Policy attached successfully",
-		"username":   username,
-		"policy_arn": req.PolicyArn,
-	})
+	respondJSON(w, http.StatusOK, map[string]string{"message": "MFA device enabled", "serial_number": req.SerialNumber})
 }
 
-// AttachMultipleUserPolicies attaches multiple policies to users in parallel
-func AttachMultipleUserPolicies(w http.ResponseWriter, r *http.Request) {
+// DeactivateUserMFADevice removes an MFA device from a user without deleting it
+func DeactivateUserMFADevice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	username := vars["username"]
+
 	var req struct {
-		Attachments []policy.AttachPolicyRequest `json:"attachments"`
+		SerialNumber string `json:"serial_number"`
 	}
-
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	if req.SerialNumber == "" {
+		respondError(w, http.StatusBadRequest, "serial_number is required")
+		return
+	}
 
-	if len(req.Attachments) == 0 {
-		respondError(w, http.StatusBadRequest, "at least one attachment is required")
+	if err := user.DeactivateMFADevice(username, req.SerialNumber); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
+	respondJSON(w, http.StatusOK, map[string]string{"message": "MFA device deactivated", "serial_number": req.SerialNumber})
+}
 
-	results := policy.AttachMultiplePolicies(req.Attachments)
+// DeleteUserMFADevice deactivates and deletes a user's virtual MFA device given in ?serial_number=
+func DeleteUserMFADevice(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	username := vars["username"]
 
-	successCount := 0
-	failureCount := 0
-	for _, result := range results {
-		if result.Success {
-			successCount++
-		} else {
-			failureCount++
-		}
+	serialNumber := r.URL.Query().Get("serial_number")
+	if serialNumber == "" {
+		respondError(w, http.StatusBadRequest, "serial_number is required")
+		return
+	}
+
+	steps := user.DeleteMFADevice(username, serialNumber)
+	if failed := user.FailedSteps(steps); len(failed) > 0 {
+		respondJSON(w, http.StatusInternalServerError, map[string]interface{}{"error": failed[0].Error, "steps": steps})
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "MFA device deleted", "steps": steps})
+}
+
+// AccessKeyReport returns every access key in the account with its age and last use.
+// Query parameters: max_age_days (default 90), max_unused_days, flagged=true to return
+// only keys that crossed a threshold, output=csv|json|yaml|table
+func AccessKeyReport(w http.ResponseWriter, r *http.Request) {
+	maxAge, err := queryInt(r, "max_age_days")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	maxUnused, err := queryInt(r, "max_unused_days")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rows, err := user.GenerateAccessKeyReport(user.AccessKeyReportOptions{MaxAgeDays: maxAge, MaxUnusedDays: maxUnused})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if r.URL.Query().Get("flagged") == "true" {
+		rows = user.FilterFlaggedAccessKeys(rows)
+	}
+
+	respondList(w, r, "access_keys", rows, views.TableConfig{
+		Headers: user.AccessKeyReportTableHeaders,
+		Rows:    views.RowsOf(rows),
+	})
+}
+
+// CredentialReport generates the IAM credential report and returns it as typed rows.
+// ?sort= orders the rows and ?filter=console-without-mfa keeps console users without MFA.
+func CredentialReport(w http.ResponseWriter, r *http.Request) {
+	rows, _, err := user.FetchCredentialReport()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	switch filter := r.URL.Query().Get("filter"); filter {
+	case "":
+	case "console-without-mfa":
+		rows = user.FilterConsoleWithoutMFA(rows)
+	default:
+		respondError(w, http.StatusBadRequest, "unknown filter '"+filter+"'")
+		return
+	}
+
+	if sortKey := r.URL.Query().Get("sort"); sortKey != "" {
+		if err := user.SortCredentialReport(rows, sortKey); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	respondList(w, r, "users", rows, views.TableConfig{
+		Headers: user.CredentialReportTableHeaders,
+		Rows:    views.RowsOf(rows),
+	})
+}
+
+// ============ IAM HYGIENE ============
+
+// HygieneReport lists users inactive for ?days= (default 90), groups without members and
+// customer policies attached to nothing; ?services=true adds services granted but never used
+func HygieneReport(w http.ResponseWriter, r *http.Request) {
+	days, err := queryInt(r, "days")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report := hygiene.Generate(hygiene.Options{
+		InactiveDays:    days,
+		IncludeServices: r.URL.Query().Get("services") == "true",
+	})
+
+	if output := r.URL.Query().Get("output"); output != "" && output != views.OutputJSON {
+		respondList(w, r, "findings", report.Findings, views.TableConfig{
+			Headers: hygiene.FindingTableHeaders,
+			Rows:    views.RowsOf(report.Findings),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"generated_at":  report.GeneratedAt,
+		"inactive_days": report.InactiveDays,
+		"counts":        report.Count(),
+		"findings":      report.Findings,
+		"errors":        report.Errors,
+	})
+}
+
+// ApplyHygieneFindings removes the targets of confirmed hygiene findings and returns the
+// steps taken for each. Each target is re-checked first; inactive users against
+// inactive_days (default 90), which should match the report the findings came from.
+func ApplyHygieneFindings(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Findings     []hygiene.Finding `json:"findings"`
+		InactiveDays int               `json:"inactive_days"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Findings) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one finding is required")
+		return
+	}
+
+	type findingResult struct {
+		Category string                `json:"category"`
+		Target   string                `json:"target"`
+		Success  bool                  `json:"success"`
+		Steps    []user.UserStepResult `json:"steps"`
+	}
+
+	results := make([]findingResult, 0, len(req.Findings))
+	failureCount := 0
+	for _, finding := range req.Findings {
+		steps := hygiene.Apply(finding, req.InactiveDays)
+		success := len(user.FailedSteps(steps)) == 0
+		if !success {
+			failureCount++
+		}
+		results = append(results, findingResult{Category: finding.Category, Target: finding.Target, Success: success, Steps: steps})
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":       "Hygiene findings applied",
+		"total":         len(results),
+		"success_count": len(results) - failureCount,
+		"failure_count": failureCount,
+		"results":       results,
+	})
+}
+
+// ============ PASSWORD POLICY ============
+
+// GetPasswordPolicy returns the account password policy and the rules it implies
+func GetPasswordPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := user.FetchPasswordPolicy()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"password_policy": policy, "requirements": policy.Requirements()})
+}
+
+// UpdatePasswordPolicy replaces the account password policy
+func UpdatePasswordPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy user.PasswordPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := policy.Validate(); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := user.UpdatePasswordPolicy(policy); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "Password policy updated", "password_policy": policy})
+}
+
+// ResetPasswordPolicy removes the custom password policy so the AWS default applies
+func ResetPasswordPolicy(w http.ResponseWriter, r *http.Request) {
+	if err := user.ResetPasswordPolicy(); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Password policy reset to the AWS default"})
+}
+
+// CheckPassword validates a candidate password against the account password policy
+// without calling AWS with it, for live feedback while typing
+func CheckPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	policy, violations, err := user.CheckPasswordAgainstPolicy(req.Password)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"valid":        len(violations) == 0,
+		"violations":   violations,
+		"requirements": policy.Requirements(),
+	})
+}
+
+// GeneratePassword returns a random password that meets the account password policy
+func GeneratePassword(w http.ResponseWriter, r *http.Request) {
+	password, err := user.GenerateCompliantPassword()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]string{"password": password})
+}
+
+// ============ IAM GROUPS ============
+
+// ListIAMGroups returns all IAM groups
+func ListIAMGroups(w http.ResponseWriter, r *http.Request) {
+	groups, err := group.FetchIAMGroups()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "groups", groups, views.TableConfig{
+		Headers: group.IAMGroupTableHeaders,
+		Rows:    views.RowsOf(groups),
+	})
+}
+
+// CreateIAMGroup creates a new IAM group
+func CreateIAMGroup(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		GroupName string `json:"groupname"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.GroupName == "" {
+		respondError(w, http.StatusBadRequest, "groupname is required")
+		return
+	}
+
+	group.CreateIAMGroup(req.GroupName)
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Group created", "groupname": req.GroupName})
+}
+
+// DeleteIAMGroup deletes an IAM group
+func DeleteIAMGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+
+	// Check if force delete is requested
+	force := r.URL.Query().Get("force") == "true"
+
+	if force {
+		err := group.ForceDeleteGroup(groupname)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		group.DeleteGroupModel(groupname)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Group deleted", "groupname": groupname})
+}
+
+// CheckGroupDependencies checks if a group has dependencies
+func CheckGroupDependencies(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+
+	deps, err := group.CheckGroupDependencies(groupname)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, deps)
+}
+
+// AddUserToGroup adds a user to a group
+func AddUserToGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+
+	var req struct {
+		Username string `json:"username"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	group.AddUserToGroupModel(req.Username, groupname)
+	respondJSON(w, http.StatusOK, map[string]string{"message": "User added to group", "username": req.Username, "groupname": groupname})
+}
+
+// RemoveUserFromGroup removes a user from a group
+func RemoveUserFromGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+	username := vars["username"]
+
+	group.RemoveUserFromGroupModel(username, groupname)
+	respondJSON(w, http.StatusOK, map[string]string{"message": "User removed from group", "username": username, "groupname": groupname})
+}
+
+// ListUsersInGroup lists all users in a group
+func ListUsersInGroup(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+
+	group.ListUsersInGroupModel(groupname)
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Users listed", "groupname": groupname})
+}
+
+// ListUserGroups lists all groups for a user
+func ListUserGroups(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	groups := group.ListUserGroupsModel(username)
+	respondJSON(w, http.StatusOK, map[string]interface{}{"username": username, "groups": groups})
+}
+
+// AttachGroupPolicy attaches a policy to a group
+func AttachGroupPolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+
+	var req struct {
+		PolicyArn string `json:"policy_arn"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.PolicyArn == "" {
+		respondError(w, http.StatusBadRequest, "policy_arn is required")
+		return
+	}
+
+	err := group.AttachGroupPolicy(groupname, req.PolicyArn)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy attached", "groupname": groupname, "policy_arn": req.PolicyArn})
+}
+
+// DetachGroupPolicy detaches a policy from a group
+func DetachGroupPolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+	policyArn := vars["policy_arn"]
+
+	err := group.DetachGroupPolicy(groupname, policyArn)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy detached", "groupname": groupname, "policy_arn": policyArn})
+}
+
+// ListGroupPolicies lists all policies attached to a group
+func ListGroupPolicies(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	groupname := vars["groupname"]
+
+	policies, err := group.ListGroupPolicies(groupname)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"groupname": groupname, "policies": policies})
+}
+
+// ListIAMRoles returns all IAM roles
+func ListIAMRoles(w http.ResponseWriter, r *http.Request) {
+	roles, warnings, err := role.FetchIAMRolesWithTags()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondListWith(w, r, "roles", roles, map[string]interface{}{"warnings": warnings}, views.TableConfig{
+		Headers: role.IAMRoleTableHeaders,
+		Rows:    views.RowsOf(roles),
+	})
+}
+
+// CreateIAMRole creates a role from a trust policy document, or trusted by the service in
+// trusted_service when no document is given
+func CreateIAMRole(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RoleName       string `json:"role_name"`
+		Path           string `json:"path"`
+		Description    string `json:"description"`
+		TrustPolicy    string `json:"trust_policy"`
+		TrustedService string `json:"trusted_service"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.RoleName == "" {
+		respondError(w, http.StatusBadRequest, "role_name is required")
+		return
+	}
+	trustPolicy := req.TrustPolicy
+	if trustPolicy == "" {
+		if req.TrustedService == "" {
+			respondError(w, http.StatusBadRequest, "trust_policy or trusted_service is required")
+			return
+		}
+		trustPolicy = role.ServiceTrustPolicy(req.TrustedService)
+	}
+	if err := policy.ValidatePolicyDocument(trustPolicy); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	created, err := role.CreateIAMRole(req.RoleName, req.Path, req.Description, trustPolicy)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{"message": "Role created", "role": created})
+}
+
+// DeleteIAMRole deletes an IAM role; ?force=true removes its dependencies first
+func DeleteIAMRole(w http.ResponseWriter, r *http.Request) {
+	rolename := mux.Vars(r)["rolename"]
+	force := r.URL.Query().Get("force") == "true"
+
+	err := role.DeleteIAMRole(rolename, force)
+	if errors.Is(err, role.ErrRoleHasDependencies) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Role deleted", "role_name": rolename})
+}
+
+// CheckRoleDependencies lists what has to be removed before a role can be deleted
+func CheckRoleDependencies(w http.ResponseWriter, r *http.Request) {
+	deps, err := role.CheckRoleDependencies(mux.Vars(r)["rolename"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, deps)
+}
+
+// GetRoleTrustPolicy returns the trust policy of a role
+func GetRoleTrustPolicy(w http.ResponseWriter, r *http.Request) {
+	rolename := mux.Vars(r)["rolename"]
+
+	document, err := role.FetchTrustPolicy(rolename)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"role_name": rolename, "trust_policy": document})
+}
+
+// UpdateRoleTrustPolicy replaces the trust policy of a role
+func UpdateRoleTrustPolicy(w http.ResponseWriter, r *http.Request) {
+	rolename := mux.Vars(r)["rolename"]
+
+	var req struct {
+		TrustPolicy string `json:"trust_policy"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := policy.ValidatePolicyDocument(req.TrustPolicy); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := role.UpdateTrustPolicy(rolename, req.TrustPolicy); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Trust policy updated", "role_name": rolename})
+}
+
+// ListRolePolicies lists the managed policies attached to a role
+func ListRolePolicies(w http.ResponseWriter, r *http.Request) {
+	rolename := mux.Vars(r)["rolename"]
+
+	policies, err := role.ListRolePolicies(rolename)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"role_name": rolename, "policies": policies})
+}
+
+// AttachRolePolicy attaches a managed policy to a role
+func AttachRolePolicy(w http.ResponseWriter, r *http.Request) {
+	rolename := mux.Vars(r)["rolename"]
+
+	var req struct {
+		PolicyArn string `json:"policy_arn"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.PolicyArn == "" {
+		respondError(w, http.StatusBadRequest, "policy_arn is required")
+		return
+	}
+
+	if err := role.AttachRolePolicy(rolename, req.PolicyArn); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy attached", "role_name": rolename, "policy_arn": req.PolicyArn})
+}
+
+// DetachRolePolicy detaches a managed policy from a role
+func DetachRolePolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	rolename := vars["rolename"]
+	policyArn := vars["policy_arn"]
+
+	if err := role.DetachRolePolicy(rolename, policyArn); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy detached", "role_name": rolename, "policy_arn": policyArn})
+}
+
+// ListInstanceProfiles returns all instance profiles, or those of ?role= when given
+func ListInstanceProfiles(w http.ResponseWriter, r *http.Request) {
+	var profiles []role.InstanceProfile
+	var err error
+	if rolename := r.URL.Query().Get("role"); rolename != "" {
+		profiles, err = role.FetchInstanceProfilesForRole(rolename)
+	} else {
+		profiles, err = role.FetchInstanceProfiles()
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "instance_profiles", profiles, views.TableConfig{
+		Headers: role.InstanceProfileTableHeaders,
+		Rows:    views.RowsOf(profiles),
+	})
+}
+
+// CreateInstanceProfile creates an instance profile, linking role_name to it when given
+func CreateInstanceProfile(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name     string `json:"instance_profile_name"`
+		Path     string `json:"path"`
+		RoleName string `json:"role_name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "instance_profile_name is required")
+		return
+	}
+
+	profile, err := role.CreateInstanceProfile(req.Name, req.Path, req.RoleName)
+	if profile == nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err != nil {
+		respondJSON(w, http.StatusMultiStatus, map[string]interface{}{
+			"message":          "Instance profile created but the role could not be linked",
+			"instance_profile": profile,
+			"error":            err.Error(),
+		})
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{"message": "Instance profile created", "instance_profile": profile})
+}
+
+// DeleteInstanceProfile unlinks any role from an instance profile and deletes it
+func DeleteInstanceProfile(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	if err := role.DeleteInstanceProfile(name); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Instance profile deleted", "instance_profile_name": name})
+}
+
+// AddRoleToInstanceProfile links a role to an instance profile
+func AddRoleToInstanceProfile(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+
+	var req struct {
+		RoleName string `json:"role_name"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.RoleName == "" {
+		respondError(w, http.StatusBadRequest, "role_name is required")
+		return
+	}
+
+	if err := role.AddRoleToInstanceProfile(name, req.RoleName); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Role linked", "instance_profile_name": name, "role_name": req.RoleName})
+}
+
+// RemoveRoleFromInstanceProfile unlinks a role from an instance profile
+func RemoveRoleFromInstanceProfile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name := vars["name"]
+	rolename := vars["rolename"]
+
+	if err := role.RemoveRoleFromInstanceProfile(name, rolename); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Role unlinked", "instance_profile_name": name, "role_name": rolename})
+}
+
+// ListIAMPolicies lists all IAM policies
+func ListIAMPolicies(w http.ResponseWriter, r *http.Request) {
+	// Get scope from query parameter (All, AWS, or Local)
+	scope := r.URL.Query().Get("scope")
+	if scope == "" {
+		scope = "All"
+	}
+
+	policies, err := policy.ListPoliciesModel(scope)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"policies": policies,
+		"count":    len(policies),
+	})
+}
+
+// CreateManagedPolicy creates a customer-managed policy from a JSON document
+func CreateManagedPolicy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name        string `json:"name"`
+		Path        string `json:"path"`
+		Description string `json:"description"`
+		Document    string `json:"document"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if err := policy.ValidatePolicyDocument(req.Document); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	created, err := policy.CreateManagedPolicy(req.Name, req.Path, req.Description, req.Document)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{"message": "Policy created", "policy": created})
+}
+
+// DeleteManagedPolicy deletes a customer-managed policy; ?force=true detaches it first.
+// AWS managed policies are rejected with 400 before anything is detached.
+func DeleteManagedPolicy(w http.ResponseWriter, r *http.Request) {
+	policyArn := mux.Vars(r)["arn"]
+	force := r.URL.Query().Get("force") == "true"
+
+	err := policy.DeleteManagedPolicy(policyArn, force)
+	if errors.Is(err, policy.ErrAWSManagedPolicy) {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, policy.ErrPolicyAttached) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy deleted", "policy_arn": policyArn})
+}
+
+// ListPolicyVersions lists the stored versions of a managed policy, newest first
+func ListPolicyVersions(w http.ResponseWriter, r *http.Request) {
+	versions, err := policy.FetchPolicyVersions(mux.Vars(r)["arn"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "versions", versions, views.TableConfig{
+		Headers: policy.PolicyVersionTableHeaders,
+		Rows:    views.RowsOf(versions),
+	})
+}
+
+// CreatePolicyVersion stores a new document as the default version of a managed policy.
+// With prune_oldest the oldest non-default version is deleted when the limit is reached.
+func CreatePolicyVersion(w http.ResponseWriter, r *http.Request) {
+	policyArn := mux.Vars(r)["arn"]
+
+	var req struct {
+		Document    string `json:"document"`
+		PruneOldest bool   `json:"prune_oldest"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := policy.ValidatePolicyDocument(req.Document); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	version, pruned, err := policy.UpdateManagedPolicy(policyArn, req.Document, req.PruneOldest)
+	if errors.Is(err, policy.ErrPolicyVersionLimit) {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"message":        "Policy version created",
+		"policy_arn":     policyArn,
+		"version":        version,
+		"pruned_version": pruned,
+	})
+}
+
+// GetPolicyVersion returns the document of one version of a managed policy
+func GetPolicyVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	document, err := policy.FetchPolicyDocument(vars["arn"], vars["version_id"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"policy_arn": vars["arn"],
+		"version_id": vars["version_id"],
+		"document":   document,
+	})
+}
+
+// DeletePolicyVersion deletes a non-default version of a managed policy
+func DeletePolicyVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	if err := policy.DeletePolicyVersion(vars["arn"], vars["version_id"]); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Policy version deleted", "policy_arn": vars["arn"], "version_id": vars["version_id"]})
+}
+
+// SetDefaultPolicyVersion makes a stored version the default, rolling the policy back or forward
+func SetDefaultPolicyVersion(w http.ResponseWriter, r *http.Request) {
+	policyArn := mux.Vars(r)["arn"]
+
+	var req struct {
+		VersionID string `json:"version_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.VersionID == "" {
+		respondError(w, http.StatusBadRequest, "version_id is required")
+		return
+	}
+
+	if err := policy.SetDefaultPolicyVersion(policyArn, req.VersionID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Default version updated", "policy_arn": policyArn, "version_id": req.VersionID})
+}
+
+// DiffPolicyVersions returns a unified diff between the versions in ?from= and ?to=.
+// ?output=text returns the diff as plain text.
+func DiffPolicyVersions(w http.ResponseWriter, r *http.Request) {
+	policyArn := mux.Vars(r)["arn"]
+	from := r.URL.Query().Get("from")
+	to := r.URL.Query().Get("to")
+
+	if from == "" || to == "" {
+		respondError(w, http.StatusBadRequest, "from and to are required")
+		return
+	}
+
+	diff, err := policy.DiffPolicyVersions(policyArn, from, to)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if r.URL.Query().Get("output") == "text" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(diff))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"policy_arn": policyArn,
+		"from":       from,
+		"to":         to,
+		"identical":  diff == "",
+		"diff":       diff,
+	})
+}
+
+// ListInlinePolicies lists the inline policy names of a user, group or role
+func ListInlinePolicies(w http.ResponseWriter, r *http.Request) {
+	identityType, identityName := identityFromPath(r)
+
+	names, err := policy.ListInlinePolicies(identityType, identityName)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"identity_type": identityType,
+		"identity_name": identityName,
+		"policy_names":  names,
+	})
+}
+
+// GetInlinePolicy returns an inline policy with its pretty-printed document
+func GetInlinePolicy(w http.ResponseWriter, r *http.Request) {
+	identityType, identityName := identityFromPath(r)
+
+	inline, err := policy.GetInlinePolicy(identityType, identityName, mux.Vars(r)["policy_name"])
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, inline)
+}
+
+// PutInlinePolicy creates or replaces an inline policy; the document is validated first
+func PutInlinePolicy(w http.ResponseWriter, r *http.Request) {
+	identityType, identityName := identityFromPath(r)
+	policyName := mux.Vars(r)["policy_name"]
+
+	var req struct {
+		Document string `json:"document"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := policy.ValidatePolicyDocument(req.Document); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := policy.PutInlinePolicy(identityType, identityName, policyName, req.Document); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message":       "Inline policy saved",
+		"identity_type": identityType,
+		"identity_name": identityName,
+		"policy_name":   policyName,
+	})
+}
+
+// DeleteInlinePolicy deletes an inline policy from a user, group or role
+func DeleteInlinePolicy(w http.ResponseWriter, r *http.Request) {
+	identityType, identityName := identityFromPath(r)
+	policyName := mux.Vars(r)["policy_name"]
+
+	if err := policy.DeleteInlinePolicy(identityType, identityName, policyName); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message":       "Inline policy deleted",
+		"identity_type": identityType,
+		"identity_name": identityName,
+		"policy_name":   policyName,
+	})
+}
+
+// identityFromPath reads the user, group or role an inline policy or tag route is mounted under
+func identityFromPath(r *http.Request) (string, string) {
+	vars := mux.Vars(r)
+	if name, ok := vars["groupname"]; ok {
+		return policy.IdentityGroup, name
+	}
+	if name, ok := vars["rolename"]; ok {
+		return policy.IdentityRole, name
+	}
+	return policy.IdentityUser, vars["username"]
+}
+
+// SimulatePolicy evaluates the policies of a user, group or role for a list of actions and
+// resources and reports allowed/denied with the statements that decided each result
+func SimulatePolicy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		IdentityType string   `json:"identity_type"` // user, group or role; defaults to user
+		IdentityName string   `json:"identity_name"`
+		PrincipalArn string   `json:"principal_arn"` // Used instead of identity_type/identity_name when set
+		Actions      []string `json:"actions"`
+		Resources    []string `json:"resources"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Actions) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one action is required")
+		return
+	}
+
+	principalArn := req.PrincipalArn
+	if principalArn == "" {
+		if req.IdentityName == "" {
+			respondError(w, http.StatusBadRequest, "identity_name or principal_arn is required")
+			return
+		}
+		if req.IdentityType == "" {
+			req.IdentityType = policy.IdentityUser
+		}
+		if err := policy.ValidateIdentityType(req.IdentityType); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		arn, err := policy.ResolvePrincipalArn(req.IdentityType, req.IdentityName)
+		if err != nil {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		principalArn = arn
+	}
+
+	results, err := policy.SimulatePrincipalPolicy(principalArn, req.Actions, req.Resources)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	allowed := 0
+	for _, result := range results {
+		if result.Allowed {
+			allowed++
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"principal_arn": principalArn,
+		"allowed_count": allowed,
+		"denied_count":  len(results) - allowed,
+		"results":       results,
+	})
+}
+
+// GetUserPermissionsBoundary returns the permissions boundary of a user
+func GetUserPermissionsBoundary(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	boundary, err := policy.FetchUserPermissionsBoundary(username)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"username": username, "permissions_boundary": boundary})
+}
+
+// SetUserPermissionsBoundary sets a managed policy as the permissions boundary of a user
+func SetUserPermissionsBoundary(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	var req struct {
+		PolicyArn string `json:"policy_arn"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.PolicyArn == "" {
+		respondError(w, http.StatusBadRequest, "policy_arn is required")
+		return
+	}
+
+	if err := policy.SetUserPermissionsBoundary(username, req.PolicyArn); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Permissions boundary set", "username": username, "permissions_boundary": req.PolicyArn})
+}
+
+// RemoveUserPermissionsBoundary removes the permissions boundary of a user
+func RemoveUserPermissionsBoundary(w http.ResponseWriter, r *http.Request) {
+	username := mux.Vars(r)["username"]
+
+	if err := policy.RemoveUserPermissionsBoundary(username); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Permissions boundary removed", "username": username})
+}
+
+// ListIdentityTags returns the tags of the user or role in the path
+func ListIdentityTags(w http.ResponseWriter, r *http.Request) {
+	identityType, name := identityFromPath(r)
+
+	tags, err := tag.ListTags(identityType, name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "tags", tags, views.TableConfig{
+		Headers: tag.TagTableHeaders,
+		Rows:    views.RowsOf(tags),
+	})
+}
+
+// AddIdentityTags adds tags to the user or role in the path, replacing existing values
+func AddIdentityTags(w http.ResponseWriter, r *http.Request) {
+	identityType, name := identityFromPath(r)
+
+	var req struct {
+		Tags map[string]string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Tags) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one tag is required")
+		return
+	}
+
+	if err := tag.AddTags(identityType, name, req.Tags); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "Tags added", identityType: name, "tags": req.Tags})
+}
+
+// RemoveIdentityTags removes the tags named by the repeatable ?key= parameter from the user
+// or role in the path
+func RemoveIdentityTags(w http.ResponseWriter, r *http.Request) {
+	identityType, name := identityFromPath(r)
+
+	keys := r.URL.Query()["key"]
+	if len(keys) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one key parameter is required")
+		return
+	}
+
+	if err := tag.RemoveTags(identityType, name, keys); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"message": "Tags removed", identityType: name, "keys": keys})
+}
+
+// AttachUserPolicy attaches a single policy to a user
+func AttachUserPolicy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	username := vars["username"]
+
+	var req struct {
+		PolicyArn string `json:"policy_arn"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.PolicyArn == "" {
+		respondError(w, http.StatusBadRequest, "policy_arn is required")
+		return
+	}
+
+	if err := policy.AttachUserPolicy(username, req.PolicyArn); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"message":    "Policy attached successfully",
+		"username":   username,
+		"policy_arn": req.PolicyArn,
+	})
+}
+
+// AttachMultipleUserPolicies attaches multiple policies to users in parallel
+func AttachMultipleUserPolicies(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Attachments []policy.AttachPolicyRequest `json:"attachments"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if len(req.Attachments) == 0 {
+		respondError(w, http.StatusBadRequest, "at least one attachment is required")
+		return
+	}
+
+	results := policy.AttachMultiplePolicies(req.Attachments)
+
+	successCount := 0
+	failureCount := 0
+	for _, result := range results {
+		if result.Success {
+			successCount++
+		} else {
+			failureCount++
+		}
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -685,25 +2002,81 @@ func SendUserCredentialsEmail(w http.ResponseWriter, r *http.Request) {
 
 // ============ HELPER FUNCTIONS ============
 
-func parseTabSeparated(output string, fields []string) []map[string]string {
-	result := []map[string]string{}
-	lines := strings.Split(strings.TrimSpace(output), "\n")
+// respondList writes a listing as JSON under key, or in the format given by the
+// "output" query parameter (csv, yaml or table)
+func respondList(w http.ResponseWriter, r *http.Request, key string, data interface{}, config views.TableConfig) {
+	requested := r.URL.Query().Get("output")
+	if requested == "" {
+		respondJSON(w, http.StatusOK, map[string]interface{}{key: data})
+		return
+	}
 
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		parts := strings.Split(line, "\t")
-		if len(parts) >= len(fields) {
-			item := make(map[string]string)
-			for i, field := range fields {
-				item[field] = parts[i]
-			}
-			result = append(result, item)
+	format, err := views.ParseOutputFormat(requested)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var contentType string
+	switch format {
+	case views.OutputCSV:
+		contentType = "text/csv"
+	case views.OutputYAML:
+		contentType = "application/yaml"
+	case views.OutputTable:
+		contentType = "text/plain"
+	default:
+		respondJSON(w, http.StatusOK, map[string]interface{}{key: data})
+		return
+	}
+
+	// Render first so a failure can still be reported with an error status
+	var body bytes.Buffer
+	if err := views.RenderOutput(&body, format, data, config); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// respondAccountList is respondList for multi-account listings; JSON responses
+// also carry the accounts that failed under "account_errors"
+func respondAccountList(w http.ResponseWriter, r *http.Request, key string, data interface{}, failures []utils.AccountError, config views.TableConfig) {
+	respondListWith(w, r, key, data, map[string]interface{}{"account_errors": failures}, config)
+}
+
+// respondListWith is respondList for listings that report more than their rows; JSON
+// responses also carry the fields in extra, the other formats only the rows
+func respondListWith(w http.ResponseWriter, r *http.Request, key string, data interface{}, extra map[string]interface{}, config views.TableConfig) {
+	requested := r.URL.Query().Get("output")
+	if requested == "" || requested == views.OutputJSON {
+		response := map[string]interface{}{key: data}
+		for field, value := range extra {
+			response[field] = value
 		}
+		respondJSON(w, http.StatusOK, response)
+		return
+	}
+
+	respondList(w, r, key, data, config)
+}
+
+// rejectPolicyViolations checks a password against the account password policy and, when it
+// breaks a rule, responds 400 with the violations. If the policy cannot be read, AWS decides.
+func rejectPolicyViolations(w http.ResponseWriter, password string) bool {
+	policy, violations, err := user.CheckPasswordAgainstPolicy(password)
+	if err != nil || len(violations) == 0 {
+		return false
 	}
 
-	return result
+	respondJSON(w, http.StatusBadRequest, map[string]interface{}{
+		"error":        "Password does not meet the account password policy",
+		"violations":   violations,
+		"requirements": policy.Requirements(),
+	})
+	return true
 }
 
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
@@ -13,14 +13,37 @@ import (
 	"github.com/DragonEmperor9480/aws_cli_manager/models/s3"
 	"github.com/DragonEmperor9480/aws_cli_manager/service"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 	s3sdk "github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/gorilla/mux"
 )
 
-// ListS3Buckets returns all S3 buckets
+// ListS3Buckets returns all S3 buckets, optionally across the accounts in ?accounts=
 func ListS3Buckets(w http.ResponseWriter, r *http.Request) {
-	output := s3.ListS3BucketsModel()
-	respondJSON(w, http.StatusOK, map[string]string{"buckets": output})
+	selected, err := service.ResolveAccounts(r.URL.Query().Get("accounts"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if selected != nil {
+		buckets, failures := s3.FetchS3BucketsAcrossAccounts(selected)
+		respondAccountList(w, r, "buckets", buckets, failures, views.TableConfig{
+			Headers: s3.AccountS3BucketTableHeaders,
+			Rows:    views.RowsOf(buckets),
+		})
+		return
+	}
+
+	buckets, err := s3.FetchS3Buckets()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondList(w, r, "buckets", buckets, views.TableConfig{
+		Headers: s3.S3BucketTableHeaders,
+		Rows:    views.RowsOf(buckets),
+	})
 }
 
 // CreateS3Bucket creates a new S3 bucket
@@ -68,7 +91,10 @@ func ListS3Objects(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"objects": objects, "bucketname": bucketname})
+	respondList(w, r, "objects", objects, views.TableConfig{
+		Headers: s3.S3ObjectTableHeaders,
+		Rows:    views.RowsOf(objects),
+	})
 }
 
 // GetBucketVersioning gets bucket versioning status
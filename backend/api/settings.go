@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
 	"github.com/DragonEmperor9480/aws_cli_manager/service"
 )
 
@@ -51,6 +52,51 @@ func SaveMFADevice(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, map[string]string{"message": "MFA device saved successfully"})
 }
 
+// DetectMFADevice saves an MFA device of the calling identity as the configured device.
+// The body may name a serial_number; without one the identity must have exactly one device.
+func DetectMFADevice(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SerialNumber string `json:"serial_number"`
+	}
+	if r.ContentLength > 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	devices, err := user.FetchMFADevices("")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	var selected *user.MFADevice
+	for i, device := range devices {
+		if req.SerialNumber == "" && len(devices) == 1 || device.SerialNumber == req.SerialNumber {
+			selected = &devices[i]
+		}
+	}
+	if selected == nil {
+		respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":       "choose one of the devices with serial_number",
+			"mfa_devices": devices,
+		})
+		return
+	}
+
+	if err := service.SaveMFADevice(selected.Name(), selected.SerialNumber); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"configured":  true,
+		"device_name": selected.Name(),
+		"device_arn":  selected.SerialNumber,
+	})
+}
+
 // DeleteMFADevice deletes the MFA device configuration
 func DeleteMFADevice(w http.ResponseWriter, r *http.Request) {
 	err := service.DeleteMFADevice()
@@ -104,6 +104,7 @@ func StartBackend() int {
 	r.HandleFunc("/api/iam/users", api.ListIAMUsers).Methods("GET")
 	r.HandleFunc("/api/iam/users", api.CreateIAMUser).Methods("POST")
 	r.HandleFunc("/api/iam/users/batch", api.CreateMultipleIAMUsers).Methods("POST")
+	r.HandleFunc("/api/iam/users/import", api.ImportIAMUsers).Methods("POST")
 	r.HandleFunc("/api/iam/users/batch/dependencies", api.CheckMultipleUserDependencies).Methods("POST")
 	r.HandleFunc("/api/iam/users/batch/delete", api.DeleteMultipleIAMUsers).Methods("POST")
 	r.HandleFunc("/api/iam/users/{username}/dependencies", api.CheckUserDependencies).Methods("GET")
@@ -112,12 +113,43 @@ func StartBackend() int {
 	r.HandleFunc("/api/iam/users/{username}/password", api.UpdateUserPassword).Methods("PUT")
 	r.HandleFunc("/api/iam/users/{username}/access-keys", api.CreateAccessKey).Methods("POST")
 	r.HandleFunc("/api/iam/users/{username}/access-keys", api.ListAccessKeys).Methods("GET")
+	r.HandleFunc("/api/iam/users/{username}/access-keys/{id}", api.UpdateAccessKey).Methods("PUT")
+	r.HandleFunc("/api/iam/users/{username}/access-keys/{id}", api.DeleteAccessKey).Methods("DELETE")
+	r.HandleFunc("/api/iam/users/{username}/access-keys/{id}/rotate", api.RotateAccessKey).Methods("POST")
+	r.HandleFunc("/api/iam/users/{username}/mfa-devices", api.ListUserMFADevices).Methods("GET")
+	r.HandleFunc("/api/iam/users/{username}/mfa-devices", api.CreateVirtualMFADevice).Methods("POST")
+	r.HandleFunc("/api/iam/users/{username}/mfa-devices", api.DeleteUserMFADevice).Methods("DELETE")
+	r.HandleFunc("/api/iam/users/{username}/mfa-devices/enable", api.EnableUserMFADevice).Methods("POST")
+	r.HandleFunc("/api/iam/users/{username}/mfa-devices/deactivate", api.DeactivateUserMFADevice).Methods("POST")
 	r.HandleFunc("/api/iam/users/{username}/groups", api.ListUserGroups).Methods("GET")
 	r.HandleFunc("/api/iam/users/{username}/policies", api.AttachUserPolicy).Methods("POST")
 	r.HandleFunc("/api/iam/users/{username}/policies/sync", api.SyncUserPolicies).Methods("POST")
 	r.HandleFunc("/api/iam/users/policies/batch", api.AttachMultipleUserPolicies).Methods("POST")
+	r.HandleFunc("/api/iam/users/{username}/permissions-boundary", api.GetUserPermissionsBoundary).Methods("GET")
+	r.HandleFunc("/api/iam/users/{username}/permissions-boundary", api.SetUserPermissionsBoundary).Methods("PUT")
+	r.HandleFunc("/api/iam/users/{username}/permissions-boundary", api.RemoveUserPermissionsBoundary).Methods("DELETE")
+	r.HandleFunc("/api/iam/users/{username}/tags", api.ListIdentityTags).Methods("GET")
+	r.HandleFunc("/api/iam/users/{username}/tags", api.AddIdentityTags).Methods("PUT")
+	r.HandleFunc("/api/iam/users/{username}/tags", api.RemoveIdentityTags).Methods("DELETE")
+	r.HandleFunc("/api/iam/users/{username}/inline-policies", api.ListInlinePolicies).Methods("GET")
+	r.HandleFunc("/api/iam/users/{username}/inline-policies/{policy_name}", api.GetInlinePolicy).Methods("GET")
+	r.HandleFunc("/api/iam/users/{username}/inline-policies/{policy_name}", api.PutInlinePolicy).Methods("PUT")
+	r.HandleFunc("/api/iam/users/{username}/inline-policies/{policy_name}", api.DeleteInlinePolicy).Methods("DELETE")
 	r.HandleFunc("/api/iam/users/send-credentials", api.SendUserCredentialsEmail).Methods("POST")
 
+	// IAM Password Policy
+	r.HandleFunc("/api/iam/password-policy", api.GetPasswordPolicy).Methods("GET")
+	r.HandleFunc("/api/iam/password-policy", api.UpdatePasswordPolicy).Methods("PUT")
+	r.HandleFunc("/api/iam/password-policy", api.ResetPasswordPolicy).Methods("DELETE")
+	r.HandleFunc("/api/iam/password-policy/check", api.CheckPassword).Methods("POST")
+	r.HandleFunc("/api/iam/password-policy/generate", api.GeneratePassword).Methods("POST")
+
+	// IAM Reports
+	r.HandleFunc("/api/iam/reports/access-keys", api.AccessKeyReport).Methods("GET")
+	r.HandleFunc("/api/iam/reports/credentials", api.CredentialReport).Methods("GET")
+	r.HandleFunc("/api/iam/reports/hygiene", api.HygieneReport).Methods("GET")
+	r.HandleFunc("/api/iam/reports/hygiene/apply", api.ApplyHygieneFindings).Methods("POST")
+
 	// IAM Groups
 	r.HandleFunc("/api/iam/groups", api.ListIAMGroups).Methods("GET")
 	r.HandleFunc("/api/iam/groups", api.CreateIAMGroup).Methods("POST")
@@ -129,9 +161,45 @@ func StartBackend() int {
 	r.HandleFunc("/api/iam/groups/{groupname}/policies", api.ListGroupPolicies).Methods("GET")
 	r.HandleFunc("/api/iam/groups/{groupname}/policies", api.AttachGroupPolicy).Methods("POST")
 	r.HandleFunc("/api/iam/groups/{groupname}/policies/{policy_arn:.*}", api.DetachGroupPolicy).Methods("DELETE")
+	r.HandleFunc("/api/iam/groups/{groupname}/inline-policies", api.ListInlinePolicies).Methods("GET")
+	r.HandleFunc("/api/iam/groups/{groupname}/inline-policies/{policy_name}", api.GetInlinePolicy).Methods("GET")
+	r.HandleFunc("/api/iam/groups/{groupname}/inline-policies/{policy_name}", api.PutInlinePolicy).Methods("PUT")
+	r.HandleFunc("/api/iam/groups/{groupname}/inline-policies/{policy_name}", api.DeleteInlinePolicy).Methods("DELETE")
+
+	// IAM Roles
+	r.HandleFunc("/api/iam/roles", api.ListIAMRoles).Methods("GET")
+	r.HandleFunc("/api/iam/roles", api.CreateIAMRole).Methods("POST")
+	r.HandleFunc("/api/iam/roles/{rolename}", api.DeleteIAMRole).Methods("DELETE")
+	r.HandleFunc("/api/iam/roles/{rolename}/dependencies", api.CheckRoleDependencies).Methods("GET")
+	r.HandleFunc("/api/iam/roles/{rolename}/trust-policy", api.GetRoleTrustPolicy).Methods("GET")
+	r.HandleFunc("/api/iam/roles/{rolename}/trust-policy", api.UpdateRoleTrustPolicy).Methods("PUT")
+	r.HandleFunc("/api/iam/roles/{rolename}/policies", api.ListRolePolicies).Methods("GET")
+	r.HandleFunc("/api/iam/roles/{rolename}/policies", api.AttachRolePolicy).Methods("POST")
+	r.HandleFunc("/api/iam/roles/{rolename}/policies/{policy_arn:.*}", api.DetachRolePolicy).Methods("DELETE")
+	r.HandleFunc("/api/iam/roles/{rolename}/inline-policies", api.ListInlinePolicies).Methods("GET")
+	r.HandleFunc("/api/iam/roles/{rolename}/inline-policies/{policy_name}", api.GetInlinePolicy).Methods("GET")
+	r.HandleFunc("/api/iam/roles/{rolename}/inline-policies/{policy_name}", api.PutInlinePolicy).Methods("PUT")
+	r.HandleFunc("/api/iam/roles/{rolename}/inline-policies/{policy_name}", api.DeleteInlinePolicy).Methods("DELETE")
+	r.HandleFunc("/api/iam/roles/{rolename}/tags", api.ListIdentityTags).Methods("GET")
+	r.HandleFunc("/api/iam/roles/{rolename}/tags", api.AddIdentityTags).Methods("PUT")
+	r.HandleFunc("/api/iam/roles/{rolename}/tags", api.RemoveIdentityTags).Methods("DELETE")
+	r.HandleFunc("/api/iam/instance-profiles", api.ListInstanceProfiles).Methods("GET")
+	r.HandleFunc("/api/iam/instance-profiles", api.CreateInstanceProfile).Methods("POST")
+	r.HandleFunc("/api/iam/instance-profiles/{name}", api.DeleteInstanceProfile).Methods("DELETE")
+	r.HandleFunc("/api/iam/instance-profiles/{name}/roles", api.AddRoleToInstanceProfile).Methods("POST")
+	r.HandleFunc("/api/iam/instance-profiles/{name}/roles/{rolename}", api.RemoveRoleFromInstanceProfile).Methods("DELETE")
 
 	// IAM Policies
 	r.HandleFunc("/api/iam/policies", api.ListIAMPolicies).Methods("GET")
+	r.HandleFunc("/api/iam/simulate", api.SimulatePolicy).Methods("POST")
+	r.HandleFunc("/api/iam/policies", api.CreateManagedPolicy).Methods("POST")
+	r.HandleFunc("/api/iam/policies/{arn:.+}/versions/default", api.SetDefaultPolicyVersion).Methods("PUT")
+	r.HandleFunc("/api/iam/policies/{arn:.+}/versions/{version_id}", api.GetPolicyVersion).Methods("GET")
+	r.HandleFunc("/api/iam/policies/{arn:.+}/versions/{version_id}", api.DeletePolicyVersion).Methods("DELETE")
+	r.HandleFunc("/api/iam/policies/{arn:.+}/versions", api.ListPolicyVersions).Methods("GET")
+	r.HandleFunc("/api/iam/policies/{arn:.+}/versions", api.CreatePolicyVersion).Methods("POST")
+	r.HandleFunc("/api/iam/policies/{arn:.+}/diff", api.DiffPolicyVersions).Methods("GET")
+	r.HandleFunc("/api/iam/policies/{arn:.+}", api.DeleteManagedPolicy).Methods("DELETE")
 
 	// S3 Buckets
 	r.HandleFunc("/api/s3/buckets", api.ListS3Buckets).Methods("GET")
@@ -154,14 +222,61 @@ func StartBackend() int {
 	r.HandleFunc("/api/cloudwatch/lambda/functions", api.ListLambdaFunctions).Methods("GET")
 	r.HandleFunc("/api/cloudwatch/lambda/{function}/logs", api.StreamLambdaLogs).Methods("GET")
 
+	// EC2 Instances
+	r.HandleFunc("/api/ec2/instances", api.ListEC2Instances).Methods("GET")
+	r.HandleFunc("/api/ec2/instances", api.LaunchEC2Instances).Methods("POST")
+	r.HandleFunc("/api/ec2/instances/{id}", api.GetEC2Instance).Methods("GET")
+	r.HandleFunc("/api/ec2/instances/{id}", api.TerminateEC2Instance).Methods("DELETE")
+	r.HandleFunc("/api/ec2/instances/{id}/start", api.StartEC2Instance).Methods("POST")
+	r.HandleFunc("/api/ec2/instances/{id}/stop", api.StopEC2Instance).Methods("POST")
+	r.HandleFunc("/api/ec2/instances/{id}/reboot", api.RebootEC2Instance).Methods("POST")
+	r.HandleFunc("/api/ec2/key-pairs", api.ListEC2KeyPairs).Methods("GET")
+	r.HandleFunc("/api/ec2/subnets", api.ListEC2Subnets).Methods("GET")
+
+	// EBS Volumes and Snapshots
+	r.HandleFunc("/api/ec2/volumes", api.ListEBSVolumes).Methods("GET")
+	r.HandleFunc("/api/ec2/volumes", api.CreateEBSVolume).Methods("POST")
+	r.HandleFunc("/api/ec2/volumes/{id}", api.DeleteEBSVolume).Methods("DELETE")
+	r.HandleFunc("/api/ec2/volumes/{id}/attach", api.AttachEBSVolume).Methods("POST")
+	r.HandleFunc("/api/ec2/volumes/{id}/detach", api.DetachEBSVolume).Methods("POST")
+	r.HandleFunc("/api/ec2/snapshots", api.ListEBSSnapshots).Methods("GET")
+	r.HandleFunc("/api/ec2/snapshots", api.CreateEBSSnapshot).Methods("POST")
+	r.HandleFunc("/api/ec2/snapshots/{id}", api.DeleteEBSSnapshot).Methods("DELETE")
+	r.HandleFunc("/api/ec2/snapshots/{id}/copy", api.CopyEBSSnapshot).Methods("POST")
+
+	// AMIs
+	r.HandleFunc("/api/ec2/images", api.ListAMIImages).Methods("GET")
+	r.HandleFunc("/api/ec2/images/deregister", api.DeregisterMultipleAMIImages).Methods("POST")
+	r.HandleFunc("/api/ec2/images/{id}/dependencies", api.CheckAMIImageDependencies).Methods("GET")
+	r.HandleFunc("/api/ec2/images/{id}", api.DeregisterAMIImage).Methods("DELETE")
+
+	// VPCs and Security Groups
+	r.HandleFunc("/api/ec2/vpcs", api.ListVPCs).Methods("GET")
+	r.HandleFunc("/api/ec2/security-groups", api.ListSecurityGroups).Methods("GET")
+	r.HandleFunc("/api/ec2/security-groups/risky-rules", api.ListRiskyRules).Methods("GET")
+	r.HandleFunc("/api/ec2/security-groups/{id}/rules", api.ListSecurityGroupRules).Methods("GET")
+	r.HandleFunc("/api/ec2/security-groups/{id}/rules", api.AddSecurityGroupRule).Methods("POST")
+	r.HandleFunc("/api/ec2/security-groups/{id}/rules/{rule_id}", api.RevokeSecurityGroupRule).Methods("DELETE")
+
+	// Systems Manager
+	r.HandleFunc("/api/ec2/managed-instances", api.ListManagedInstances).Methods("GET")
+	r.HandleFunc("/api/ec2/commands", api.RunShellCommand).Methods("POST")
+	r.HandleFunc("/api/ec2/commands/{id}/output", api.StreamCommandOutput).Methods("GET")
+
 	// Settings
 	r.HandleFunc("/api/settings/mfa", api.GetMFADevice).Methods("GET")
 	r.HandleFunc("/api/settings/mfa", api.SaveMFADevice).Methods("POST")
 	r.HandleFunc("/api/settings/mfa", api.DeleteMFADevice).Methods("DELETE")
+	r.HandleFunc("/api/settings/mfa/detect", api.DetectMFADevice).Methods("POST")
 
 	// AWS Configuration
 	r.HandleFunc("/api/aws/config", api.GetAWSConfig).Methods("GET")
 	r.HandleFunc("/api/aws/config", api.ConfigureAWS).Methods("POST")
+	r.HandleFunc("/api/aws/profiles", api.ListAWSProfiles).Methods("GET")
+	r.HandleFunc("/api/aws/profiles", api.SwitchAWSProfile).Methods("POST")
+	r.HandleFunc("/api/aws/session", api.GetAWSSession).Methods("GET")
+	r.HandleFunc("/api/aws/session", api.StartAWSSession).Methods("POST")
+	r.HandleFunc("/api/aws/session", api.EndAWSSession).Methods("DELETE")
 
 	// Email Configuration
 	r.HandleFunc("/api/email/config", api.GetEmailConfig).Methods("GET")
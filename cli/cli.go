@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// outputFormat is the global --output value used as default by listing commands
+var outputFormat = views.OutputTable
+
+// sessionOptions holds the global --role-arn/--external-id/--mfa-code values
+var sessionOptions utils.SessionOptions
+
+// ParseGlobalFlags parses the flags shared by the menu and every subcommand
+// (--output, --profile, --region) and returns the remaining arguments
+func ParseGlobalFlags(args []string) ([]string, error) {
+	var profile, region string
+
+	global := newFlagSet("awsmgr")
+	global.Usage = func() {} // help is printed by Run
+	global.StringVar(&outputFormat, "output", views.OutputTable, "output format: table, json, yaml or csv")
+	global.StringVar(&outputFormat, "o", views.OutputTable, "shorthand for --output")
+	global.StringVar(&profile, "profile", "", "named AWS profile to use")
+	global.StringVar(&region, "region", "", "AWS region to use")
+	global.StringVar(&sessionOptions.RoleARN, "role-arn", "", "role to assume with STS")
+	global.StringVar(&sessionOptions.ExternalID, "external-id", "", "external ID for --role-arn")
+	global.StringVar(&sessionOptions.TokenCode, "mfa-code", "", "code from the MFA device configured in Settings")
+	if err := global.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if _, err := views.ParseOutputFormat(outputFormat); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return nil, err
+	}
+
+	utils.SetAWSTarget(profile, region)
+	return global.Args(), nil
+}
+
+// Run executes a non-interactive subcommand and returns the process exit code.
+// Global flags must already have been removed with ParseGlobalFlags.
+func Run(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "iam", "s3", "logs":
+		if err := utils.InitAWSClients(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error initializing AWS clients: "+err.Error())
+			return ExitError
+		}
+		if err := startSession(); err != nil {
+			fmt.Fprintln(os.Stderr, "Error starting STS session: "+err.Error())
+			return ExitError
+		}
+	}
+
+	switch args[0] {
+	case "iam":
+		return runIAM(args[1:])
+	case "s3":
+		return runS3(args[1:])
+	case "logs":
+		return runLogs(args[1:])
+	case "profiles":
+		return listProfiles(args[1:])
+	case "help", "-h", "--help":
+		printUsage(os.Stdout)
+		return ExitOK
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n\n", args[0])
+		printUsage(os.Stderr)
+		return ExitUsage
+	}
+}
+
+// startSession switches to STS session credentials when --role-arn or --mfa-code was given
+func startSession() error {
+	if sessionOptions.RoleARN == "" && sessionOptions.TokenCode == "" {
+		return nil
+	}
+
+	if sessionOptions.TokenCode != "" {
+		device, err := service.LoadMFADevice()
+		if err != nil {
+			return err
+		}
+		sessionOptions.MFASerial = device.DeviceARN
+	}
+
+	_, err := utils.StartAWSSession(sessionOptions)
+	return err
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: awsmgr [--profile <name>] [--region <region>] [--role-arn <arn>] [--mfa-code <code>] [--output <format>] [command] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run without a command to start the interactive menu.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  iam user list [--accounts <all|a,b>] [--tag <key=value>]")
+	fmt.Fprintln(w, "  iam user create <username> [--password <pw>] [--require-reset]")
+	fmt.Fprintln(w, "  iam user delete <username> [--force]")
+	fmt.Fprintln(w, "  iam user groups <username>")
+	fmt.Fprintln(w, "  iam group list")
+	fmt.Fprintln(w, "  iam group add-user <groupname> <username>")
+	fmt.Fprintln(w, "  iam group remove-user <groupname> <username>")
+	fmt.Fprintln(w, "  iam report access-keys [--max-age-days <n>] [--max-unused-days <n>] [--flagged]")
+	fmt.Fprintln(w, "  iam report credentials [--sort <key>] [--console-without-mfa]")
+	fmt.Fprintln(w, "  iam report hygiene [--days <n>] [--services]")
+	fmt.Fprintln(w, "  iam plan <state-file>")
+	fmt.Fprintln(w, "  iam apply <state-file> [--auto-approve]")
+	fmt.Fprintln(w, "  s3 ls [bucket[/prefix]] [--accounts <all|a,b>]")
+	fmt.Fprintln(w, "  logs ls [--accounts <all|a,b>]")
+	fmt.Fprintln(w, "  logs tail <function>")
+	fmt.Fprintln(w, "  profiles")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fmt.Fprintln(w, "  --profile       Named AWS profile from ~/.aws/config or ~/.aws/credentials")
+	fmt.Fprintln(w, "  --region        AWS region, overrides the profile's region")
+	fmt.Fprintln(w, "  --role-arn      Assume this role with STS before running the command")
+	fmt.Fprintln(w, "  --external-id   External ID required by the role's trust policy")
+	fmt.Fprintln(w, "  --mfa-code      MFA code for the device configured in Settings (GetSessionToken,")
+	fmt.Fprintln(w, "                  or AssumeRole with MFA when --role-arn is set)")
+	fmt.Fprintln(w, "  --output, -o    Output format for listings: table, json, yaml or csv (default table)")
+	fmt.Fprintln(w, "  --accounts      List across accounts: \"all\" configured in Settings, or")
+	fmt.Fprintln(w, "                  comma-separated profile names and role ARNs")
+	fmt.Fprintln(w, "  --version, -v   Show version information")
+}
+
+// parseArgs parses flags that may appear before, after or between positional
+// arguments and returns the positional arguments in order
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// newFlagSet creates a flag set that reports errors instead of exiting
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+// listProfiles prints the profiles found in the shared AWS config files
+func listProfiles(args []string) int {
+	fs := newFlagSet("profiles")
+	format := addOutputFlag(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	profiles, err := utils.ListAWSProfiles()
+	if err != nil {
+		return fail(err)
+	}
+
+	return render(*format, profiles, views.TableConfig{
+		Headers: utils.AWSProfileTableHeaders,
+		Rows:    views.RowsOf(profiles),
+	})
+}
+
+// addOutputFlag registers --output/-o on a listing command, defaulting to the global value
+func addOutputFlag(fs *flag.FlagSet) *string {
+	format := fs.String("output", outputFormat, "output format: table, json, yaml or csv")
+	fs.StringVar(format, "o", outputFormat, "shorthand for --output")
+	return format
+}
+
+// addAccountsFlag registers --accounts on a listing command that can fan out over accounts
+func addAccountsFlag(fs *flag.FlagSet) *string {
+	return fs.String("accounts", "", "\"all\" or comma-separated profile names and role ARNs")
+}
+
+// renderAccounts prints a multi-account listing and reports failed accounts on stderr
+func renderAccounts(format string, data interface{}, failures []utils.AccountError, config views.TableConfig) int {
+	code := render(format, data, config)
+	for _, failure := range failures {
+		fmt.Fprintln(os.Stderr, "Error: account '"+failure.Account+"': "+failure.Error)
+	}
+	if code == ExitOK && len(failures) > 0 {
+		return ExitError
+	}
+	return code
+}
+
+// printWarnings reports problems that left a listing incomplete without failing it
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "Warning: "+warning)
+	}
+}
+
+// render prints data to stdout in the requested output format
+func render(format string, data interface{}, config views.TableConfig) int {
+	parsed, err := views.ParseOutputFormat(format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return ExitUsage
+	}
+
+	if err := views.RenderOutput(os.Stdout, parsed, data, config); err != nil {
+		return fail(err)
+	}
+	return ExitOK
+}
+
+func usageError(usage string) int {
+	fmt.Fprintln(os.Stderr, "Usage: awsmgr "+usage)
+	return ExitUsage
+}
+
+func fail(err error) int {
+	fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+	return ExitError
+}
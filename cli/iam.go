@@ -0,0 +1,488 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/group"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/hygiene"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/state"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/tag"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+func runIAM(args []string) int {
+	if len(args) == 0 {
+		return usageError("iam <user|group|report|plan|apply> [arguments]")
+	}
+
+	switch args[0] {
+	case "user":
+		return runIAMUser(args[1:])
+	case "group":
+		return runIAMGroup(args[1:])
+	case "report":
+		return runIAMReport(args[1:])
+	case "plan":
+		return iamPlan(args[1:])
+	case "apply":
+		return iamApply(args[1:])
+	default:
+		return usageError("iam <user|group|report|plan|apply> [arguments]")
+	}
+}
+
+func runIAMUser(args []string) int {
+	if len(args) == 0 {
+		return usageError("iam user <list|create|delete|groups> [arguments]")
+	}
+
+	switch args[0] {
+	case "list":
+		return iamUserList(args[1:])
+	case "create":
+		return iamUserCreate(args[1:])
+	case "delete":
+		return iamUserDelete(args[1:])
+	case "groups":
+		return iamUserGroups(args[1:])
+	default:
+		return usageError("iam user <list|create|delete|groups> [arguments]")
+	}
+}
+
+func runIAMGroup(args []string) int {
+	if len(args) == 0 {
+		return usageError("iam group <list|add-user|remove-user> [arguments]")
+	}
+
+	switch args[0] {
+	case "list":
+		return iamGroupList(args[1:])
+	case "add-user":
+		return iamGroupAddUser(args[1:])
+	case "remove-user":
+		return iamGroupRemoveUser(args[1:])
+	default:
+		return usageError("iam group <list|add-user|remove-user> [arguments]")
+	}
+}
+
+func runIAMReport(args []string) int {
+	if len(args) == 0 {
+		return usageError("iam report <access-keys|credentials|hygiene> [arguments]")
+	}
+
+	switch args[0] {
+	case "access-keys":
+		return iamReportAccessKeys(args[1:])
+	case "credentials":
+		return iamReportCredentials(args[1:])
+	case "hygiene":
+		return iamReportHygiene(args[1:])
+	default:
+		return usageError("iam report <access-keys|credentials|hygiene> [arguments]")
+	}
+}
+
+func iamUserList(args []string) int {
+	fs := newFlagSet("iam user list")
+	format := addOutputFlag(fs)
+	accountsFlag := addAccountsFlag(fs)
+	tagFlag := fs.String("tag", "", "only list users with this tag, as key=value or key")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	var filter *tag.Filter
+	if *tagFlag != "" {
+		parsed, err := tag.ParseFilter(*tagFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+			return ExitUsage
+		}
+		filter = &parsed
+	}
+
+	selected, err := service.ResolveAccounts(*accountsFlag)
+	if err != nil {
+		return fail(err)
+	}
+	if selected != nil {
+		users, warnings, failures := user.FetchIAMUsersAcrossAccounts(selected, filter)
+		printWarnings(warnings)
+		return renderAccounts(*format, users, failures, views.TableConfig{
+			Headers: user.AccountIAMUserTableHeaders,
+			Rows:    views.RowsOf(users),
+		})
+	}
+
+	users, warnings, err := user.FetchIAMUsersWithTags(filter)
+	if err != nil {
+		return fail(err)
+	}
+	printWarnings(warnings)
+
+	return render(*format, users, views.TableConfig{
+		Headers: user.IAMUserTableHeaders,
+		Rows:    views.RowsOf(users),
+	})
+}
+
+func iamUserCreate(args []string) int {
+	fs := newFlagSet("iam user create")
+	password := fs.String("password", "", "initial console password")
+	requireReset := fs.Bool("require-reset", false, "require a password reset on first sign-in")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		return usageError("iam user create <username> [--password <pw>] [--require-reset]")
+	}
+	username := positional[0]
+
+	if *password == "" {
+		status, err := user.CreateIAMUser(username)
+		switch status {
+		case user.UserAlreadyExists:
+			return fail(errors.New("user '" + username + "' already exists"))
+		case user.UserCreationError:
+			return fail(err)
+		}
+		fmt.Println("User '" + username + "' created")
+		return ExitOK
+	}
+
+	// Check the password before creating the user, so a weak one does not leave a user behind
+	if _, violations, err := user.CheckPasswordAgainstPolicy(*password); err == nil && len(violations) > 0 {
+		return fail(errors.New("password does not meet the account password policy: " + strings.Join(violations, "; ")))
+	}
+
+	userStatus, passwordStatus, err := user.CreateIAMUserWithPassword(username, *password, *requireReset)
+	switch userStatus {
+	case user.UserAlreadyExists:
+		return fail(errors.New("user '" + username + "' already exists"))
+	case user.UserCreationError:
+		return fail(err)
+	}
+
+	switch passwordStatus {
+	case user.PasswordUserNotFound:
+		return fail(errors.New("user '" + username + "' not found after creation"))
+	case user.PasswordPolicyViolation:
+		return fail(errors.New("user '" + username + "' created but password does not meet the account password policy"))
+	case user.PasswordAlreadyExists:
+		return fail(errors.New("user '" + username + "' created but a password already exists"))
+	case user.PasswordCreationError:
+		return fail(err)
+	}
+
+	fmt.Println("User '" + username + "' created with password")
+	return ExitOK
+}
+
+func iamUserDelete(args []string) int {
+	fs := newFlagSet("iam user delete")
+	force := fs.Bool("force", false, "remove groups, policies, credentials, MFA devices and login profile before deleting")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		return usageError("iam user delete <username> [--force]")
+	}
+	username := positional[0]
+
+	if !*force {
+		deps, err := user.CheckUserDependencies(username)
+		if err != nil {
+			return fail(err)
+		}
+		if deps.HasDependencies() {
+			return fail(errors.New("user '" + username + "' has dependencies; use --force to remove them"))
+		}
+	}
+
+	steps, err := user.DeleteIAMUserAPI(username)
+	for _, step := range user.FailedSteps(steps) {
+		fmt.Fprintln(os.Stderr, "Failed to "+step.Action+" '"+step.Resource+"': "+step.Error)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Println("User '" + username + "' deleted")
+	return ExitOK
+}
+
+func iamUserGroups(args []string) int {
+	fs := newFlagSet("iam user groups")
+	format := addOutputFlag(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		return usageError("iam user groups <username>")
+	}
+
+	groups := group.ListUserGroupsModel(positional[0])
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		rows = append(rows, []string{g})
+	}
+
+	return render(*format, groups, views.TableConfig{
+		Headers: []string{"Group Name"},
+		Rows:    rows,
+	})
+}
+
+func iamGroupList(args []string) int {
+	fs := newFlagSet("iam group list")
+	format := addOutputFlag(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	groups, err := group.FetchIAMGroups()
+	if err != nil {
+		return fail(err)
+	}
+
+	return render(*format, groups, views.TableConfig{
+		Headers: group.IAMGroupTableHeaders,
+		Rows:    views.RowsOf(groups),
+	})
+}
+
+func iamGroupAddUser(args []string) int {
+	fs := newFlagSet("iam group add-user")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 2 {
+		return usageError("iam group add-user <groupname> <username>")
+	}
+
+	if err := group.AddUserToGroup(positional[1], positional[0]); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println("User '" + positional[1] + "' added to group '" + positional[0] + "'")
+	return ExitOK
+}
+
+func iamGroupRemoveUser(args []string) int {
+	fs := newFlagSet("iam group remove-user")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 2 {
+		return usageError("iam group remove-user <groupname> <username>")
+	}
+
+	if err := group.RemoveUserFromGroup(positional[1], positional[0]); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println("User '" + positional[1] + "' removed from group '" + positional[0] + "'")
+	return ExitOK
+}
+
+func iamReportAccessKeys(args []string) int {
+	fs := newFlagSet("iam report access-keys")
+	format := addOutputFlag(fs)
+	maxAge := fs.Int("max-age-days", user.DefaultMaxAccessKeyAgeDays, "flag keys older than this many days")
+	maxUnused := fs.Int("max-unused-days", 0, "flag keys not used for this many days (0 disables)")
+	flagged := fs.Bool("flagged", false, "only list keys that crossed a threshold")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	rows, err := user.GenerateAccessKeyReport(user.AccessKeyReportOptions{MaxAgeDays: *maxAge, MaxUnusedDays: *maxUnused})
+	if err != nil {
+		return fail(err)
+	}
+	if *flagged {
+		rows = user.FilterFlaggedAccessKeys(rows)
+	}
+
+	return render(*format, rows, views.TableConfig{
+		Headers: user.AccessKeyReportTableHeaders,
+		Rows:    views.RowsOf(rows),
+	})
+}
+
+func iamReportCredentials(args []string) int {
+	fs := newFlagSet("iam report credentials")
+	format := addOutputFlag(fs)
+	sortKey := fs.String("sort", user.CredentialSortUser, "sort by "+strings.Join(user.CredentialSortKeys, ", "))
+	noMFA := fs.Bool("console-without-mfa", false, "only list users with console access and no MFA")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	// Reject an unknown sort key before waiting for the report
+	if err := user.SortCredentialReport(nil, *sortKey); err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return ExitUsage
+	}
+
+	rows, _, err := user.FetchCredentialReport()
+	if err != nil {
+		return fail(err)
+	}
+	if *noMFA {
+		rows = user.FilterConsoleWithoutMFA(rows)
+	}
+	user.SortCredentialReport(rows, *sortKey)
+
+	return render(*format, rows, views.TableConfig{
+		Headers: user.CredentialReportTableHeaders,
+		Rows:    views.RowsOf(rows),
+	})
+}
+
+func iamReportHygiene(args []string) int {
+	fs := newFlagSet("iam report hygiene")
+	format := addOutputFlag(fs)
+	days := fs.Int("days", hygiene.DefaultInactiveDays, "flag users with no sign-in or key use for this many days")
+	services := fs.Bool("services", false, "also list services granted but never accessed (slow)")
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	report := hygiene.Generate(hygiene.Options{InactiveDays: *days, IncludeServices: *services})
+
+	code := render(*format, report.Findings, views.TableConfig{
+		Headers: hygiene.FindingTableHeaders,
+		Rows:    views.RowsOf(report.Findings),
+	})
+	for _, failure := range report.Errors {
+		fmt.Fprintln(os.Stderr, "Error: "+failure)
+	}
+	if code == ExitOK && len(report.Errors) > 0 {
+		return ExitError
+	}
+	return code
+}
+
+func iamPlan(args []string) int {
+	fs := newFlagSet("iam plan")
+	format := addOutputFlag(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		return usageError("iam plan <state-file>")
+	}
+
+	desired, err := state.LoadStateFile(positional[0])
+	if err != nil {
+		return fail(err)
+	}
+	changes, err := state.Plan(desired)
+	if err != nil {
+		return fail(err)
+	}
+
+	parsed, err := views.ParseOutputFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return ExitUsage
+	}
+	if parsed != views.OutputTable {
+		return render(parsed, changes, views.TableConfig{
+			Headers: state.ChangeTableHeaders,
+			Rows:    views.RowsOf(changes),
+		})
+	}
+
+	// The default output reads like a diff so it can be pasted into a pull request
+	printPlan(os.Stdout, changes, positional[0])
+	return ExitOK
+}
+
+// printPlan writes the changes as diff lines followed by a summary
+func printPlan(w io.Writer, changes []state.Change, file string) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes. IAM matches "+file+".")
+		return
+	}
+	for _, change := range changes {
+		fmt.Fprintln(w, change.String())
+	}
+	additions, removals := state.CountChanges(changes)
+	fmt.Fprintf(w, "\nPlan: %d to add, %d to remove.\n", additions, removals)
+}
+
+func iamApply(args []string) int {
+	fs := newFlagSet("iam apply")
+	format := addOutputFlag(fs)
+	autoApprove := fs.Bool("auto-approve", false, "apply the plan without asking for confirmation")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		return usageError("iam apply <state-file> [--auto-approve]")
+	}
+
+	// Reject a bad format before changing anything
+	parsed, err := views.ParseOutputFormat(*format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error: "+err.Error())
+		return ExitUsage
+	}
+
+	desired, err := state.LoadStateFile(positional[0])
+	if err != nil {
+		return fail(err)
+	}
+	changes, err := state.Plan(desired)
+	if err != nil {
+		return fail(err)
+	}
+
+	// The plan goes to stderr when stdout carries JSON, YAML or CSV results
+	planOutput := io.Writer(os.Stdout)
+	if parsed != views.OutputTable {
+		planOutput = os.Stderr
+	}
+	printPlan(planOutput, changes, positional[0])
+	if len(changes) == 0 {
+		return ExitOK
+	}
+
+	if !*autoApprove {
+		fmt.Fprint(os.Stderr, "\nApply these changes? Only 'yes' will be accepted: ")
+		input, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(input) != "yes" {
+			fmt.Fprintln(os.Stderr, "Apply cancelled.")
+			return ExitError
+		}
+	}
+
+	results := state.Apply(changes)
+	code := render(*format, results, views.TableConfig{
+		Headers: state.ResultTableHeaders,
+		Rows:    views.RowsOf(results),
+	})
+
+	if failed := state.FailedResults(results); len(failed) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d of %d change(s) failed\n", len(failed), len(results))
+		return ExitError
+	}
+	return code
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	cloudwatch_model "github.com/DragonEmperor9480/aws_cli_manager/models/cloudwatch"
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+func runLogs(args []string) int {
+	if len(args) == 0 {
+		return usageError("logs <ls|tail> [arguments]")
+	}
+
+	switch args[0] {
+	case "ls":
+		return logsList(args[1:])
+	case "tail":
+		return logsTail(args[1:])
+	default:
+		return usageError("logs <ls|tail> [arguments]")
+	}
+}
+
+func logsList(args []string) int {
+	fs := newFlagSet("logs ls")
+	format := addOutputFlag(fs)
+	accountsFlag := addAccountsFlag(fs)
+	if _, err := parseArgs(fs, args); err != nil {
+		return ExitUsage
+	}
+
+	selected, err := service.ResolveAccounts(*accountsFlag)
+	if err != nil {
+		return fail(err)
+	}
+	if selected != nil {
+		functions, failures := cloudwatch_model.FetchLambdaFunctionsAcrossAccounts(selected)
+		return renderAccounts(*format, functions, failures, views.TableConfig{
+			Headers: cloudwatch_model.AccountLambdaFunctionTableHeaders,
+			Rows:    views.RowsOf(functions),
+		})
+	}
+
+	functions, err := cloudwatch_model.FetchLambdaFunctions()
+	if err != nil {
+		return fail(err)
+	}
+
+	return render(*format, functions, views.TableConfig{
+		Headers: cloudwatch_model.LambdaFunctionTableHeaders,
+		Rows:    views.RowsOf(functions),
+	})
+}
+
+// logsTail streams a Lambda function's logs to stdout until interrupted
+func logsTail(args []string) int {
+	fs := newFlagSet("logs tail")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) != 1 {
+		return usageError("logs tail <function>")
+	}
+
+	logGroupName := positional[0]
+	if !strings.HasPrefix(logGroupName, "/") {
+		logGroupName = "/aws/lambda/" + logGroupName
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logChan := make(chan cloudwatch_model.LogEntry, 100)
+	errChan := make(chan error, 1)
+
+	go cloudwatch_model.StreamLambdaLogs(ctx, logGroupName, logChan, errChan)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ExitOK
+		case logEntry := <-logChan:
+			fmt.Println(strings.TrimRight(logEntry.Message, "\n"))
+		case err := <-errChan:
+			if err != nil {
+				return fail(err)
+			}
+		}
+	}
+}
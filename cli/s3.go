@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/s3"
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+func runS3(args []string) int {
+	if len(args) == 0 {
+		return usageError("s3 ls [bucket[/prefix]]")
+	}
+
+	switch args[0] {
+	case "ls":
+		return s3List(args[1:])
+	default:
+		return usageError("s3 ls [bucket[/prefix]]")
+	}
+}
+
+func s3List(args []string) int {
+	fs := newFlagSet("s3 ls")
+	format := addOutputFlag(fs)
+	accountsFlag := addAccountsFlag(fs)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return ExitUsage
+	}
+	if len(positional) > 1 {
+		return usageError("s3 ls [bucket[/prefix]]")
+	}
+
+	// No bucket given: list buckets
+	if len(positional) == 0 {
+		selected, err := service.ResolveAccounts(*accountsFlag)
+		if err != nil {
+			return fail(err)
+		}
+		if selected != nil {
+			buckets, failures := s3.FetchS3BucketsAcrossAccounts(selected)
+			return renderAccounts(*format, buckets, failures, views.TableConfig{
+				Headers: s3.AccountS3BucketTableHeaders,
+				Rows:    views.RowsOf(buckets),
+			})
+		}
+
+		buckets, err := s3.FetchS3Buckets()
+		if err != nil {
+			return fail(err)
+		}
+		return render(*format, buckets, views.TableConfig{
+			Headers: s3.S3BucketTableHeaders,
+			Rows:    views.RowsOf(buckets),
+		})
+	}
+
+	if *accountsFlag != "" {
+		return usageError("s3 ls --accounts only applies to the bucket listing")
+	}
+
+	bucket, prefix := splitS3Path(positional[0])
+	items, err := s3.ListS3ItemsWithPrefix(bucket, prefix)
+	if err != nil {
+		return fail(err)
+	}
+
+	return render(*format, items, views.TableConfig{
+		Headers: s3.S3ItemTableHeaders,
+		Rows:    views.RowsOf(items),
+	})
+}
+
+// splitS3Path splits "s3://bucket/prefix" or "bucket/prefix" into bucket and prefix
+func splitS3Path(path string) (string, string) {
+	path = strings.TrimPrefix(path, "s3://")
+	bucket, prefix, _ := strings.Cut(path, "/")
+	return bucket, prefix
+}
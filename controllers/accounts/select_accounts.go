@@ -0,0 +1,36 @@
+package accounts
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+)
+
+// SelectAccounts asks whether a listing should run across the configured accounts.
+// It returns nil when no accounts are configured or only the current account is wanted.
+func SelectAccounts() []string {
+	accounts, err := service.LoadAccounts()
+	if err != nil || len(accounts) == 0 {
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Printf("List across all %d configured accounts? (y/n): ", len(accounts))
+	input, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(input)) != "y" {
+		return nil
+	}
+
+	return accounts
+}
+
+// PrintAccountErrors reports the accounts a multi-account listing failed for
+func PrintAccountErrors(failures []utils.AccountError) {
+	for _, failure := range failures {
+		fmt.Println(utils.Red + "Account '" + failure.Account + "' failed: " + failure.Error + utils.Reset)
+	}
+}
@@ -0,0 +1,44 @@
+package cloudwatch
+
+import (
+	"fmt"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/controllers/accounts"
+	cloudwatch_model "github.com/DragonEmperor9480/aws_cli_manager/models/cloudwatch"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// ListLambdaFunctionsController lists Lambda functions, across the configured accounts if requested
+func ListLambdaFunctionsController() {
+	if selected := accounts.SelectAccounts(); selected != nil {
+		utils.ShowProcessingAnimation(fmt.Sprintf("Fetching Lambda functions from %d accounts", len(selected)))
+		functions, failures := cloudwatch_model.FetchLambdaFunctionsAcrossAccounts(selected)
+		utils.StopAnimation()
+
+		views.RenderTable(views.TableConfig{
+			Headers: cloudwatch_model.AccountLambdaFunctionTableHeaders,
+			Rows:    views.RowsOf(functions),
+		})
+		accounts.PrintAccountErrors(failures)
+		return
+	}
+
+	utils.ShowProcessingAnimation("Fetching Lambda functions")
+	functions, err := cloudwatch_model.FetchLambdaFunctions()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error fetching Lambda functions: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(functions) == 0 {
+		fmt.Println(utils.Yellow + "No Lambda functions found in your account." + utils.Reset)
+		return
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: cloudwatch_model.LambdaFunctionTableHeaders,
+		Rows:    views.RowsOf(functions),
+	})
+}
@@ -19,7 +19,7 @@ func LiveTailLambdaLogs() {
 
 	// Fetch Lambda functions using model
 	utils.ShowProcessingAnimation("Fetching Lambda functions")
-	output, err := cloudwatch_model.FetchLambdaFunctions()
+	lambdaFunctions, err := cloudwatch_model.FetchLambdaFunctions()
 	utils.StopAnimation()
 
 	if err != nil {
@@ -27,14 +27,12 @@ func LiveTailLambdaLogs() {
 		return
 	}
 
-	functions := strings.Split(strings.TrimSpace(string(output)), "\n")
-
-	// Clean up function names
-	for i := range functions {
-		functions[i] = strings.TrimSpace(functions[i])
+	functions := make([]string, 0, len(lambdaFunctions))
+	for _, fn := range lambdaFunctions {
+		functions = append(functions, fn.FunctionName)
 	}
 
-	if len(functions) == 0 || functions[0] == "" {
+	if len(functions) == 0 {
 		fmt.Println(utils.Yellow + "No Lambda functions found in your account." + utils.Reset)
 		return
 	}
@@ -113,80 +111,7 @@ func startInteractiveLogViewer(logGroupName, functionName string) {
 	// Start auto-scroll
 	viewer.AutoScroll()
 
-	// Initial render
+	// Initial render and event loop
 	viewer.Render()
-
-	// Event loop
-	searchMode := false
-	for {
-		ev := viewer.GetScreen().PollEvent()
-		switch ev := ev.(type) {
-		case *tcell.EventResize:
-			viewer.GetScreen().Sync()
-			viewer.Render()
-		case *tcell.EventKey:
-			if searchMode {
-				// Handle search mode keys
-				switch ev.Key() {
-				case tcell.KeyEscape:
-					viewer.ToggleSearchMode()
-					searchMode = false
-					viewer.Render()
-				case tcell.KeyBackspace, tcell.KeyBackspace2:
-					viewer.RemoveFromSearchQuery()
-					viewer.Render()
-				case tcell.KeyEnter:
-					viewer.NextMatch()
-					viewer.Render()
-				case tcell.KeyRune:
-					switch ev.Rune() {
-					case 'w', 'W':
-						viewer.PrevMatch()
-						viewer.Render()
-					case 's', 'S':
-						viewer.NextMatch()
-						viewer.Render()
-					default:
-						viewer.AddToSearchQuery(ev.Rune())
-						viewer.Render()
-					}
-				}
-			} else {
-				// Handle normal mode keys
-				switch ev.Key() {
-				case tcell.KeyEscape, tcell.KeyCtrlC:
-					return
-				case tcell.KeyRune:
-					switch ev.Rune() {
-					case 'q', 'Q':
-						return
-					case ' ':
-						viewer.TogglePause()
-						viewer.Render()
-					case 'r', 'R':
-						viewer.ResetLogs()
-						viewer.Render()
-					case 'f', 'F', '/':
-						viewer.ToggleSearchMode()
-						searchMode = true
-						viewer.Render()
-					}
-				case tcell.KeyUp:
-					viewer.ScrollUp()
-					viewer.Render()
-				case tcell.KeyDown:
-					viewer.ScrollDown()
-					viewer.Render()
-				case tcell.KeyPgUp:
-					viewer.PageUp()
-					viewer.Render()
-				case tcell.KeyPgDn:
-					viewer.PageDown()
-					viewer.Render()
-				}
-			}
-		case *tcell.EventInterrupt:
-			viewer.Render()
-		}
-	}
+	viewer.Run()
 }
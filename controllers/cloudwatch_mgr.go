@@ -26,6 +26,9 @@ func CloudWatch_mgr() {
 			cloudwatch.LiveTailLambdaLogs()
 			utils.Bk()
 		case "2":
+			cloudwatch.ListLambdaFunctionsController()
+			utils.Bk()
+		case "3":
 			// Back to main menu
 			fmt.Println("Returning to Main Menu...")
 			return
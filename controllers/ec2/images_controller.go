@@ -0,0 +1,180 @@
+package ec2
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+func ListImagesController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Show (o)wned, (s)hared or (a)ll images? [o]: ")
+	input, _ := reader.ReadString('\n')
+
+	filter := ec2model.ImageFilter{Ownership: ec2model.ImageOwnershipOwned}
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "s":
+		filter.Ownership = ec2model.ImageOwnershipShared
+	case "a":
+		filter.Ownership = ec2model.ImageOwnershipAll
+	}
+
+	if !readImageFilter(reader, &filter) {
+		return
+	}
+	listImages(filter)
+}
+
+// readImageFilter asks for the name pattern and age limits
+func readImageFilter(reader *bufio.Reader, filter *ec2model.ImageFilter) bool {
+	fmt.Print("Name pattern (e.g., build-*, Enter for any): ")
+	input, _ := reader.ReadString('\n')
+	filter.NamePattern = strings.TrimSpace(input)
+
+	fmt.Print("Only images older than how many days? (Enter for any): ")
+	input, _ = reader.ReadString('\n')
+	if input = strings.TrimSpace(input); input != "" {
+		days, err := strconv.Atoi(input)
+		if err != nil || days < 0 {
+			fmt.Println(utils.Red + "Invalid number of days." + utils.Reset)
+			return false
+		}
+		filter.MinAgeDays = days
+	}
+	return true
+}
+
+func listImages(filter ec2model.ImageFilter) []ec2model.AMIImage {
+	utils.ShowProcessingAnimation("Loading AMIs")
+	images, err := ec2model.FetchAMIImages(filter)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch AMIs: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(images) == 0 {
+		fmt.Println(utils.Yellow + "No AMIs match the filter." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.AMIImageTableHeaders,
+		Rows:    views.RowsOf(images),
+	})
+	fmt.Printf("%d image(s)\n", len(images))
+	return images
+}
+
+func DeregisterImagesController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	filter := ec2model.ImageFilter{Ownership: ec2model.ImageOwnershipOwned}
+	if !readImageFilter(reader, &filter) {
+		return
+	}
+	images := listImages(filter)
+	if images == nil {
+		return
+	}
+
+	fmt.Print("Enter image ID(s) to deregister (comma-separated, or 'all' for every listed image): ")
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	var imageIDs []string
+	if input == "all" {
+		for _, image := range images {
+			imageIDs = append(imageIDs, image.ImageID)
+		}
+	} else {
+		for _, id := range strings.Split(input, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				imageIDs = append(imageIDs, id)
+			}
+		}
+	}
+	if len(imageIDs) == 0 {
+		fmt.Println(utils.Red + "Please enter a valid image ID." + utils.Reset)
+		return
+	}
+
+	// Dependency check before anything is deleted
+	utils.ShowProcessingAnimation("Checking image dependencies")
+	blocked := 0
+	var report []string
+	for _, imageID := range imageIDs {
+		deps, err := ec2model.CheckImageDependencies(imageID)
+		if err != nil {
+			report = append(report, utils.Red+"- "+imageID+": "+err.Error()+utils.Reset)
+			continue
+		}
+		if deps.HasDependencies() {
+			blocked++
+			line := "- " + imageID + ":"
+			if len(deps.Instances) > 0 {
+				line += " instances " + strings.Join(deps.Instances, ", ") + ";"
+			}
+			if len(deps.SharedWith) > 0 {
+				line += " shared with " + strings.Join(deps.SharedWith, ", ") + ";"
+			}
+			if deps.Public {
+				line += " public;"
+			}
+			report = append(report, utils.Yellow+line+utils.Reset)
+		}
+		if len(deps.SnapshotsInUse) > 0 {
+			report = append(report, utils.Cyan+"- "+imageID+": snapshots "+strings.Join(deps.SnapshotsInUse, ", ")+" are used by other images and will be kept"+utils.Reset)
+		}
+	}
+	utils.StopAnimation()
+
+	for _, line := range report {
+		fmt.Println(line)
+	}
+
+	force := false
+	if blocked > 0 {
+		fmt.Printf(utils.Yellow+"%d image(s) have dependencies.\n"+utils.Reset, blocked)
+		fmt.Print("Deregister them anyway? Running instances keep running (y/n): ")
+		input, _ = reader.ReadString('\n')
+		force = strings.ToLower(strings.TrimSpace(input)) == "y"
+	}
+
+	fmt.Print("Keep the backing snapshots? (y/n): ")
+	input, _ = reader.ReadString('\n')
+	keepSnapshots := strings.ToLower(strings.TrimSpace(input)) == "y"
+
+	if !confirm(fmt.Sprintf("Deregister %d image(s)?", len(imageIDs))) {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deregistering images")
+	results := ec2model.DeregisterMultipleImages(imageIDs, force, keepSnapshots)
+	utils.StopAnimation()
+
+	for _, result := range results {
+		if !result.Success {
+			fmt.Println(utils.Red + result.ImageID + ": " + result.Message + utils.Reset)
+			continue
+		}
+
+		fmt.Println(utils.Green + result.ImageID + ": " + result.Message + utils.Reset)
+		if len(result.DeletedSnapshots) > 0 {
+			fmt.Println("  deleted snapshots: " + strings.Join(result.DeletedSnapshots, ", "))
+		}
+		if len(result.KeptSnapshots) > 0 {
+			fmt.Println("  kept snapshots:    " + strings.Join(result.KeptSnapshots, ", "))
+		}
+		for _, e := range result.Errors {
+			fmt.Println(utils.Red + "  " + e + utils.Reset)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package ec2
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+)
+
+func StartInstanceController() {
+	instanceIDs := selectInstances("start")
+	if instanceIDs == nil || !confirm("Start "+strings.Join(instanceIDs, ", ")+"?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Starting instances")
+	changes, err := ec2model.StartEC2Instances(instanceIDs)
+	utils.StopAnimation()
+
+	printStateChanges(changes, err)
+}
+
+func StopInstanceController() {
+	instanceIDs := selectInstances("stop")
+	if instanceIDs == nil || !confirm("Stop "+strings.Join(instanceIDs, ", ")+"?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Stopping instances")
+	changes, err := ec2model.StopEC2Instances(instanceIDs)
+	utils.StopAnimation()
+
+	printStateChanges(changes, err)
+}
+
+func RebootInstanceController() {
+	instanceIDs := selectInstances("reboot")
+	if instanceIDs == nil || !confirm("Reboot "+strings.Join(instanceIDs, ", ")+"?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Rebooting instances")
+	err := ec2model.RebootEC2Instances(instanceIDs)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error rebooting instances: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + "Reboot requested for " + strings.Join(instanceIDs, ", ") + "." + utils.Reset)
+}
+
+func TerminateInstanceController() {
+	instanceIDs := selectInstances("terminate")
+	if instanceIDs == nil {
+		return
+	}
+
+	fmt.Println(utils.Red + utils.Bold + "Warning: terminated instances cannot be recovered." + utils.Reset)
+	if !confirm("Terminate " + strings.Join(instanceIDs, ", ") + "?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Terminating instances")
+	changes, err := ec2model.TerminateEC2Instances(instanceIDs)
+	utils.StopAnimation()
+
+	printStateChanges(changes, err)
+}
+
+// selectInstances lists the instances and asks for one or more instance IDs
+func selectInstances(action string) []string {
+	if ListInstancesController() == nil {
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter instance ID(s) to " + action + " (comma-separated): ")
+	input, _ := reader.ReadString('\n')
+
+	var instanceIDs []string
+	for _, id := range strings.Split(input, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			instanceIDs = append(instanceIDs, id)
+		}
+	}
+
+	if len(instanceIDs) == 0 {
+		fmt.Println(utils.Red + "Please enter a valid instance ID." + utils.Reset)
+		return nil
+	}
+	return instanceIDs
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes
+func confirm(question string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(question + " (y/n): ")
+	input, _ := reader.ReadString('\n')
+
+	if strings.ToLower(strings.TrimSpace(input)) != "y" {
+		fmt.Println(utils.Yellow + "Operation cancelled." + utils.Reset)
+		return false
+	}
+	return true
+}
+
+func printStateChanges(changes []ec2model.InstanceStateChange, err error) {
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+
+	for _, change := range changes {
+		fmt.Println(utils.Green + change.InstanceID + ": " + change.PreviousState + " -> " + change.CurrentState + utils.Reset)
+	}
+}
@@ -0,0 +1,156 @@
+package ec2
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+func LaunchInstanceController() {
+	reader := bufio.NewReader(os.Stdin)
+	opts := ec2model.LaunchInstanceOptions{}
+
+	fmt.Print("Enter AMI ID (e.g., ami-0abcdef1234567890): ")
+	input, _ := reader.ReadString('\n')
+	opts.ImageID = strings.TrimSpace(input)
+	if opts.ImageID == "" {
+		fmt.Println(utils.Red + "Please enter a valid AMI ID." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Instance type [t3.micro]: ")
+	input, _ = reader.ReadString('\n')
+	opts.InstanceType = strings.TrimSpace(input)
+
+	fmt.Print("Instance name (Name tag, optional): ")
+	input, _ = reader.ReadString('\n')
+	opts.Name = strings.TrimSpace(input)
+
+	// Key pair
+	utils.ShowProcessingAnimation("Loading key pairs")
+	keyPairs, err := ec2model.FetchKeyPairs()
+	utils.StopAnimation()
+	if err != nil {
+		fmt.Println(utils.Red + "Error fetching key pairs: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(keyPairs) > 0 {
+		views.RenderTable(views.TableConfig{Headers: ec2model.KeyPairTableHeaders, Rows: views.RowsOf(keyPairs)})
+		if selected, ok := selectIndexes(reader, "Select key pair number (Enter for none): ", len(keyPairs), false); !ok {
+			return
+		} else if len(selected) == 1 {
+			opts.KeyName = keyPairs[selected[0]].KeyName
+		}
+	}
+
+	// Subnet
+	utils.ShowProcessingAnimation("Loading subnets")
+	subnets, err := ec2model.FetchSubnets()
+	utils.StopAnimation()
+	if err != nil {
+		fmt.Println(utils.Red + "Error fetching subnets: " + err.Error() + utils.Reset)
+		return
+	}
+	vpcID := ""
+	if len(subnets) > 0 {
+		views.RenderTable(views.TableConfig{Headers: ec2model.SubnetTableHeaders, Rows: views.RowsOf(subnets)})
+		if selected, ok := selectIndexes(reader, "Select subnet number (Enter for default): ", len(subnets), false); !ok {
+			return
+		} else if len(selected) == 1 {
+			opts.SubnetID = subnets[selected[0]].SubnetID
+			vpcID = subnets[selected[0]].VpcID
+		}
+	}
+
+	// Security groups, limited to the subnet's VPC
+	utils.ShowProcessingAnimation("Loading security groups")
+	groups, err := ec2model.FetchSecurityGroups(vpcID)
+	utils.StopAnimation()
+	if err != nil {
+		fmt.Println(utils.Red + "Error fetching security groups: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(groups) > 0 {
+		views.RenderTable(views.TableConfig{Headers: ec2model.SecurityGroupTableHeaders, Rows: views.RowsOf(groups)})
+		selected, ok := selectIndexes(reader, "Select security group number(s), comma-separated (Enter for default): ", len(groups), true)
+		if !ok {
+			return
+		}
+		for _, index := range selected {
+			opts.SecurityGroupIDs = append(opts.SecurityGroupIDs, groups[index].GroupID)
+		}
+	}
+
+	fmt.Print("Number of instances [1]: ")
+	input, _ = reader.ReadString('\n')
+	if input = strings.TrimSpace(input); input != "" {
+		count, err := strconv.Atoi(input)
+		if err != nil || count < 1 {
+			fmt.Println(utils.Red + "Invalid number of instances." + utils.Reset)
+			return
+		}
+		opts.Count = int32(count)
+	}
+
+	fmt.Println()
+	fmt.Printf("%sAMI:%s             %s\n", utils.Bold, utils.Reset, opts.ImageID)
+	fmt.Printf("%sInstance Type:%s   %s\n", utils.Bold, utils.Reset, valueOrDefault(opts.InstanceType, "t3.micro"))
+	fmt.Printf("%sKey Pair:%s        %s\n", utils.Bold, utils.Reset, valueOrDefault(opts.KeyName, "none"))
+	fmt.Printf("%sSubnet:%s          %s\n", utils.Bold, utils.Reset, valueOrDefault(opts.SubnetID, "default"))
+	fmt.Printf("%sSecurity Groups:%s %s\n", utils.Bold, utils.Reset, valueOrDefault(strings.Join(opts.SecurityGroupIDs, ", "), "default"))
+	if !confirm("Launch instance(s)?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Launching instances")
+	instances, err := ec2model.LaunchEC2Instances(opts)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error launching instances: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Launched:" + utils.Reset)
+	views.RenderTable(views.TableConfig{Headers: ec2model.EC2InstanceTableHeaders, Rows: views.RowsOf(instances)})
+}
+
+// selectIndexes reads 1-based selections and returns them as 0-based indexes.
+// An empty answer selects nothing; ok is false when the input is invalid.
+func selectIndexes(reader *bufio.Reader, prompt string, count int, multiple bool) ([]int, bool) {
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, true
+	}
+
+	parts := []string{input}
+	if multiple {
+		parts = strings.Split(input, ",")
+	}
+
+	var indexes []int
+	for _, part := range parts {
+		selection, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || selection < 1 || selection > count {
+			fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+			return nil, false
+		}
+		indexes = append(indexes, selection-1)
+	}
+	return indexes, true
+}
+
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
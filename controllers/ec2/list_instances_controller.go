@@ -0,0 +1,31 @@
+package ec2
+
+import (
+	"fmt"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// ListInstancesController renders every instance in the current region and returns them
+func ListInstancesController() []ec2model.EC2Instance {
+	utils.ShowProcessingAnimation("Loading EC2 Instances")
+	instances, err := ec2model.FetchEC2Instances()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch EC2 instances: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(instances) == 0 {
+		fmt.Println(utils.Yellow + "No EC2 instances found in " + utils.GetCurrentRegion() + "." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.EC2InstanceTableHeaders,
+		Rows:    views.RowsOf(instances),
+	})
+	return instances
+}
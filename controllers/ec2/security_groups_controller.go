@@ -0,0 +1,308 @@
+package ec2
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+	ec2view "github.com/DragonEmperor9480/aws_cli_manager/views/ec2"
+)
+
+func SecurityGroupsMenuController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		ec2view.ShowSecurityGroupMenu()
+
+		fmt.Print("Enter your choice: ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		switch input {
+		case "1":
+			ListVPCsController()
+			utils.Bk()
+		case "2":
+			ListSecurityGroupsController()
+			utils.Bk()
+		case "3":
+			ViewSecurityGroupRulesController()
+			utils.Bk()
+		case "4":
+			AddSecurityGroupRuleController()
+			utils.Bk()
+		case "5":
+			RevokeSecurityGroupRuleController()
+			utils.Bk()
+		case "6":
+			FindRiskyRulesController()
+			utils.Bk()
+		case "7":
+			return
+		default:
+			fmt.Println(utils.Red + "Invalid Input. Please try again." + utils.Reset)
+			utils.Bk()
+		}
+	}
+}
+
+// ListVPCsController renders the VPCs in the current region and returns them
+func ListVPCsController() []ec2model.VPC {
+	utils.ShowProcessingAnimation("Loading VPCs")
+	vpcs, err := ec2model.FetchVPCs()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch VPCs: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(vpcs) == 0 {
+		fmt.Println(utils.Yellow + "No VPCs found in " + utils.GetCurrentRegion() + "." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.VPCTableHeaders,
+		Rows:    views.RowsOf(vpcs),
+	})
+	return vpcs
+}
+
+func ListSecurityGroupsController() []ec2model.SecurityGroup {
+	vpcs := ListVPCsController()
+	if vpcs == nil {
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	selected, ok := selectIndexes(reader, "Select VPC number (Enter for all VPCs): ", len(vpcs), false)
+	if !ok {
+		return nil
+	}
+
+	vpcID := ""
+	if len(selected) == 1 {
+		vpcID = vpcs[selected[0]].VpcID
+	}
+
+	utils.ShowProcessingAnimation("Loading security groups")
+	groups, err := ec2model.FetchSecurityGroups(vpcID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch security groups: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(groups) == 0 {
+		fmt.Println(utils.Yellow + "No security groups found." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.SecurityGroupTableHeaders,
+		Rows:    views.RowsOf(groups),
+	})
+	return groups
+}
+
+// selectSecurityGroup lists security groups and asks for one group ID
+func selectSecurityGroup() string {
+	if ListSecurityGroupsController() == nil {
+		return ""
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter security group ID: ")
+	input, _ := reader.ReadString('\n')
+	groupID := strings.TrimSpace(input)
+
+	if groupID == "" {
+		fmt.Println(utils.Red + "Please enter a valid security group ID." + utils.Reset)
+	}
+	return groupID
+}
+
+// showRules renders the rules of a security group and returns them
+func showRules(groupID string) []ec2model.SecurityGroupRule {
+	utils.ShowProcessingAnimation("Loading rules")
+	rules, err := ec2model.FetchSecurityGroupRules(groupID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch rules: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(rules) == 0 {
+		fmt.Println(utils.Yellow + "Security group '" + groupID + "' has no rules." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.SecurityGroupRuleTableHeaders,
+		Rows:    views.RowsOf(rules),
+	})
+	printRiskWarning(rules)
+	return rules
+}
+
+func printRiskWarning(rules []ec2model.SecurityGroupRule) {
+	risky := 0
+	for _, rule := range rules {
+		if rule.Risk != "" {
+			risky++
+		}
+	}
+	if risky > 0 {
+		fmt.Printf(utils.Red+utils.Bold+"Warning: %d rule(s) expose SSH, RDP or all traffic to the internet."+utils.Reset+"\n", risky)
+	}
+}
+
+func ViewSecurityGroupRulesController() {
+	if groupID := selectSecurityGroup(); groupID != "" {
+		showRules(groupID)
+	}
+}
+
+func AddSecurityGroupRuleController() {
+	groupID := selectSecurityGroup()
+	if groupID == "" {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	rule := ec2model.RuleInput{Direction: ec2model.RuleDirectionInbound}
+
+	fmt.Print("Direction (i)nbound or (o)utbound [i]: ")
+	input, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(input)) == "o" {
+		rule.Direction = ec2model.RuleDirectionOutbound
+	}
+
+	fmt.Print("Protocol (tcp, udp, icmp or -1 for all) [tcp]: ")
+	input, _ = reader.ReadString('\n')
+	rule.Protocol = valueOrDefault(strings.TrimSpace(input), "tcp")
+
+	if rule.Protocol != "-1" {
+		fmt.Print("Port or port range (e.g., 443 or 8000-8100): ")
+		input, _ = reader.ReadString('\n')
+		from, to, err := parsePortRange(strings.TrimSpace(input))
+		if err != nil {
+			fmt.Println(utils.Red + err.Error() + utils.Reset)
+			return
+		}
+		rule.FromPort, rule.ToPort = from, to
+	}
+
+	fmt.Print("Source/destination CIDR or security group ID (e.g., 10.0.0.0/16 or sg-0123...): ")
+	input, _ = reader.ReadString('\n')
+	source := strings.TrimSpace(input)
+	if source == "" {
+		fmt.Println(utils.Red + "Please enter a CIDR or security group ID." + utils.Reset)
+		return
+	}
+	if strings.HasPrefix(source, "sg-") {
+		rule.SourceGroupID = source
+	} else {
+		rule.CIDR = source
+	}
+
+	fmt.Print("Description (optional): ")
+	input, _ = reader.ReadString('\n')
+	rule.Description = strings.TrimSpace(input)
+
+	if rule.Direction == ec2model.RuleDirectionInbound && (rule.CIDR == "0.0.0.0/0" || rule.CIDR == "::/0") {
+		fmt.Println(utils.Yellow + utils.Bold + "Warning: this rule opens the port range to the whole internet." + utils.Reset)
+		if !confirm("Add it anyway?") {
+			return
+		}
+	}
+
+	utils.ShowProcessingAnimation("Adding rule")
+	err := ec2model.AddSecurityGroupRule(groupID, rule)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error adding rule: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Rule added to '" + groupID + "'." + utils.Reset)
+}
+
+func RevokeSecurityGroupRuleController() {
+	groupID := selectSecurityGroup()
+	if groupID == "" {
+		return
+	}
+	if showRules(groupID) == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter rule ID to revoke: ")
+	input, _ := reader.ReadString('\n')
+	ruleID := strings.TrimSpace(input)
+	if ruleID == "" {
+		fmt.Println(utils.Red + "Please enter a valid rule ID." + utils.Reset)
+		return
+	}
+
+	if !confirm("Revoke rule '" + ruleID + "'?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Revoking rule")
+	err := ec2model.RevokeSecurityGroupRule(groupID, ruleID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error revoking rule: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Rule '" + ruleID + "' revoked." + utils.Reset)
+}
+
+func FindRiskyRulesController() {
+	utils.ShowProcessingAnimation("Scanning security group rules")
+	rules, err := ec2model.FetchRiskyRules()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to scan rules: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(rules) == 0 {
+		fmt.Println(utils.Green + "No risky rules found in " + utils.GetCurrentRegion() + "." + utils.Reset)
+		return
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.SecurityGroupRuleTableHeaders,
+		Rows:    views.RowsOf(rules),
+	})
+	printRiskWarning(rules)
+}
+
+// parsePortRange parses "443" or "8000-8100"
+func parsePortRange(input string) (int32, int32, error) {
+	fromText, toText, isRange := strings.Cut(input, "-")
+	if !isRange {
+		toText = fromText
+	}
+
+	from, err := strconv.Atoi(strings.TrimSpace(fromText))
+	if err != nil || from < 0 || from > 65535 {
+		return 0, 0, fmt.Errorf("invalid port: %s", fromText)
+	}
+	to, err := strconv.Atoi(strings.TrimSpace(toText))
+	if err != nil || to < from || to > 65535 {
+		return 0, 0, fmt.Errorf("invalid port range: %s", input)
+	}
+	return int32(from), int32(to), nil
+}
@@ -0,0 +1,139 @@
+package ec2
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// ListSnapshotsController renders the account's EBS snapshots and returns them
+func ListSnapshotsController() []ec2model.EBSSnapshot {
+	utils.ShowProcessingAnimation("Loading EBS Snapshots")
+	snapshots, err := ec2model.FetchEBSSnapshots()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch EBS snapshots: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(snapshots) == 0 {
+		fmt.Println(utils.Yellow + "No EBS snapshots found in " + utils.GetCurrentRegion() + "." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.EBSSnapshotTableHeaders,
+		Rows:    views.RowsOf(snapshots),
+	})
+	return snapshots
+}
+
+func CreateSnapshotController() {
+	if ListVolumesController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter volume ID to snapshot: ")
+	input, _ := reader.ReadString('\n')
+	volumeID := strings.TrimSpace(input)
+	if volumeID == "" {
+		fmt.Println(utils.Red + "Please enter a valid volume ID." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Description: ")
+	input, _ = reader.ReadString('\n')
+	description := strings.TrimSpace(input)
+
+	fmt.Print("Tags (key=value, comma-separated, optional): ")
+	input, _ = reader.ReadString('\n')
+	tags := ec2model.ParseTags(input)
+
+	utils.ShowProcessingAnimation("Creating snapshot")
+	snapshot, err := ec2model.CreateEBSSnapshot(volumeID, description, tags)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error creating snapshot: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Snapshot '" + snapshot.SnapshotID + "' started for volume '" + volumeID + "'." + utils.Reset)
+}
+
+func CopySnapshotController() {
+	if ListSnapshotsController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter snapshot ID to copy: ")
+	input, _ := reader.ReadString('\n')
+	snapshotID := strings.TrimSpace(input)
+	if snapshotID == "" {
+		fmt.Println(utils.Red + "Please enter a valid snapshot ID." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Destination region (e.g., us-west-2): ")
+	input, _ = reader.ReadString('\n')
+	region := strings.TrimSpace(input)
+	if region == "" {
+		fmt.Println(utils.Red + "Please enter a valid region." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Description (Enter for default): ")
+	input, _ = reader.ReadString('\n')
+	description := strings.TrimSpace(input)
+
+	utils.ShowProcessingAnimation("Copying snapshot to " + region)
+	newSnapshotID, err := ec2model.CopyEBSSnapshot(snapshotID, region, description)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error copying snapshot: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Snapshot '" + snapshotID + "' is being copied to " + region + " as '" + newSnapshotID + "'." + utils.Reset)
+}
+
+func DeleteSnapshotController() {
+	if ListSnapshotsController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter snapshot ID(s) to delete (comma-separated): ")
+	input, _ := reader.ReadString('\n')
+
+	var snapshotIDs []string
+	for _, id := range strings.Split(input, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			snapshotIDs = append(snapshotIDs, id)
+		}
+	}
+	if len(snapshotIDs) == 0 {
+		fmt.Println(utils.Red + "Please enter a valid snapshot ID." + utils.Reset)
+		return
+	}
+
+	if !confirm("Delete " + strings.Join(snapshotIDs, ", ") + "?") {
+		return
+	}
+
+	for _, snapshotID := range snapshotIDs {
+		if err := ec2model.DeleteEBSSnapshot(snapshotID); err != nil {
+			fmt.Println(utils.Red + "Error deleting snapshot '" + snapshotID + "': " + err.Error() + utils.Reset)
+			continue
+		}
+		fmt.Println(utils.Green + "Snapshot '" + snapshotID + "' deleted." + utils.Reset)
+	}
+}
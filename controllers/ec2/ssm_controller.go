@@ -0,0 +1,164 @@
+package ec2
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+	cloudwatch_view "github.com/DragonEmperor9480/aws_cli_manager/views/cloudwatch"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ListManagedInstancesController renders the instances reachable through SSM and returns them
+func ListManagedInstancesController() []ec2model.ManagedInstance {
+	utils.ShowProcessingAnimation("Loading SSM managed instances")
+	instances, err := ec2model.FetchManagedInstances()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch managed instances: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(instances) == 0 {
+		fmt.Println(utils.Yellow + "No instances are registered with SSM in " + utils.GetCurrentRegion() + "." + utils.Reset)
+		fmt.Println(utils.Yellow + "Instances need the SSM agent and an instance profile allowing Systems Manager." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.ManagedInstanceTableHeaders,
+		Rows:    views.RowsOf(instances),
+	})
+	return instances
+}
+
+func StartSessionController() {
+	if ListManagedInstancesController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter instance ID to connect to: ")
+	input, _ := reader.ReadString('\n')
+	instanceID := strings.TrimSpace(input)
+
+	if instanceID == "" {
+		fmt.Println(utils.Red + "Please enter a valid instance ID." + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Cyan + "Starting session on " + instanceID + ". Type 'exit' to return to awsmgr." + utils.Reset)
+	if err := ec2model.StartShellSession(instanceID); err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Session on " + instanceID + " closed." + utils.Reset)
+}
+
+func RunCommandController() {
+	if ListManagedInstancesController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter instance ID(s) to run the command on (comma-separated): ")
+	input, _ := reader.ReadString('\n')
+
+	var instanceIDs []string
+	for _, id := range strings.Split(input, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			instanceIDs = append(instanceIDs, id)
+		}
+	}
+	if len(instanceIDs) == 0 {
+		fmt.Println(utils.Red + "Please enter a valid instance ID." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Enter shell command: ")
+	input, _ = reader.ReadString('\n')
+	command := strings.TrimSpace(input)
+	if command == "" {
+		fmt.Println(utils.Red + "Command cannot be empty." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Timeout in seconds (Enter for default): ")
+	input, _ = reader.ReadString('\n')
+	timeout, err := strconv.Atoi(valueOrDefault(strings.TrimSpace(input), "0"))
+	if err != nil || timeout < 0 {
+		fmt.Println(utils.Red + "Invalid timeout." + utils.Reset)
+		return
+	}
+
+	if !confirm("Run '" + command + "' on " + strings.Join(instanceIDs, ", ") + "?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Sending command")
+	commandID, err := ec2model.RunShellCommand(instanceIDs, []string{command}, timeout)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error sending command: " + err.Error() + utils.Reset)
+		return
+	}
+
+	streamCommandOutput(commandID, command)
+	fmt.Println(utils.Green + "Command ID: " + commandID + utils.Reset)
+}
+
+// streamCommandOutput shows the output of a command in the interactive log viewer
+func streamCommandOutput(commandID, command string) {
+	viewer, err := cloudwatch_view.NewLogViewer(commandID)
+	if err != nil {
+		fmt.Println(utils.Red + "Error initializing viewer: " + err.Error() + utils.Reset)
+		return
+	}
+	defer viewer.Close()
+	viewer.SetTitle("Run Command: " + command + " (" + commandID + ")")
+
+	outputChan := make(chan ec2model.CommandOutput, 100)
+	errChan := make(chan error, 1)
+
+	go ec2model.StreamCommandOutput(viewer.GetContext(), commandID, outputChan, errChan)
+
+	go func() {
+		for {
+			select {
+			case <-viewer.GetContext().Done():
+				return
+			case output := <-outputChan:
+				viewer.AddLog(cloudwatch_view.LogEntry{
+					Message: output.Message,
+					Color:   output.Color,
+				})
+				viewer.GetScreen().PostEvent(tcell.NewEventInterrupt(nil))
+			case err := <-errChan:
+				// Output sent before the final error may still be buffered
+				for len(outputChan) > 0 {
+					output := <-outputChan
+					viewer.AddLog(cloudwatch_view.LogEntry{Message: output.Message, Color: output.Color})
+				}
+
+				entry := cloudwatch_view.LogEntry{Message: "All invocations finished. Press Q to return.", Color: "cyan"}
+				if err != nil {
+					entry = cloudwatch_view.LogEntry{Message: "Error: " + err.Error(), Color: "red"}
+				}
+				viewer.AddLog(entry)
+				viewer.GetScreen().PostEvent(tcell.NewEventInterrupt(nil))
+				return
+			}
+		}
+	}()
+
+	viewer.AutoScroll()
+	viewer.Render()
+	viewer.Run()
+}
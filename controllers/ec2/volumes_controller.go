@@ -0,0 +1,193 @@
+package ec2
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	ec2model "github.com/DragonEmperor9480/aws_cli_manager/models/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// ListVolumesController renders every EBS volume in the current region and returns them
+func ListVolumesController() []ec2model.EBSVolume {
+	utils.ShowProcessingAnimation("Loading EBS Volumes")
+	volumes, err := ec2model.FetchEBSVolumes()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to fetch EBS volumes: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(volumes) == 0 {
+		fmt.Println(utils.Yellow + "No EBS volumes found in " + utils.GetCurrentRegion() + "." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: ec2model.EBSVolumeTableHeaders,
+		Rows:    views.RowsOf(volumes),
+	})
+	return volumes
+}
+
+func CreateVolumeController() {
+	reader := bufio.NewReader(os.Stdin)
+	opts := ec2model.CreateVolumeOptions{}
+
+	fmt.Print("Availability zone (e.g., " + utils.GetCurrentRegion() + "a): ")
+	input, _ := reader.ReadString('\n')
+	opts.AvailabilityZone = strings.TrimSpace(input)
+	if opts.AvailabilityZone == "" {
+		fmt.Println(utils.Red + "Please enter a valid availability zone." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Snapshot ID to restore from (Enter for an empty volume): ")
+	input, _ = reader.ReadString('\n')
+	opts.SnapshotID = strings.TrimSpace(input)
+
+	fmt.Print("Size in GiB: ")
+	input, _ = reader.ReadString('\n')
+	if input = strings.TrimSpace(input); input != "" {
+		size, err := strconv.Atoi(input)
+		if err != nil || size < 1 {
+			fmt.Println(utils.Red + "Invalid size." + utils.Reset)
+			return
+		}
+		opts.Size = int32(size)
+	}
+
+	fmt.Print("Volume type [gp3]: ")
+	input, _ = reader.ReadString('\n')
+	opts.VolumeType = strings.TrimSpace(input)
+
+	fmt.Print("Encrypt volume? (y/n): ")
+	input, _ = reader.ReadString('\n')
+	opts.Encrypted = strings.ToLower(strings.TrimSpace(input)) == "y"
+
+	fmt.Print("Volume name (Name tag, optional): ")
+	input, _ = reader.ReadString('\n')
+	opts.Name = strings.TrimSpace(input)
+
+	utils.ShowProcessingAnimation("Creating volume")
+	volume, err := ec2model.CreateEBSVolume(opts)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error creating volume: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Volume '" + volume.VolumeID + "' created (" + volume.State + ")." + utils.Reset)
+}
+
+func AttachVolumeController() {
+	if ListVolumesController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter volume ID to attach: ")
+	input, _ := reader.ReadString('\n')
+	volumeID := strings.TrimSpace(input)
+	if volumeID == "" {
+		fmt.Println(utils.Red + "Please enter a valid volume ID." + utils.Reset)
+		return
+	}
+
+	if ListInstancesController() == nil {
+		return
+	}
+
+	fmt.Print("Enter instance ID: ")
+	input, _ = reader.ReadString('\n')
+	instanceID := strings.TrimSpace(input)
+	if instanceID == "" {
+		fmt.Println(utils.Red + "Please enter a valid instance ID." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Device name [/dev/sdf]: ")
+	input, _ = reader.ReadString('\n')
+	device := valueOrDefault(strings.TrimSpace(input), "/dev/sdf")
+
+	utils.ShowProcessingAnimation("Attaching volume")
+	err := ec2model.AttachEBSVolume(volumeID, instanceID, device)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error attaching volume: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Volume '" + volumeID + "' is attaching to '" + instanceID + "' as " + device + "." + utils.Reset)
+}
+
+func DetachVolumeController() {
+	if ListVolumesController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter volume ID to detach: ")
+	input, _ := reader.ReadString('\n')
+	volumeID := strings.TrimSpace(input)
+	if volumeID == "" {
+		fmt.Println(utils.Red + "Please enter a valid volume ID." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Force detach? Only use this if a normal detach is stuck (y/n): ")
+	input, _ = reader.ReadString('\n')
+	force := strings.ToLower(strings.TrimSpace(input)) == "y"
+
+	if !confirm("Detach volume '" + volumeID + "'?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Detaching volume")
+	err := ec2model.DetachEBSVolume(volumeID, force)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error detaching volume: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Volume '" + volumeID + "' is detaching." + utils.Reset)
+}
+
+func DeleteVolumeController() {
+	if ListVolumesController() == nil {
+		return
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Enter volume ID to delete: ")
+	input, _ := reader.ReadString('\n')
+	volumeID := strings.TrimSpace(input)
+	if volumeID == "" {
+		fmt.Println(utils.Red + "Please enter a valid volume ID." + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Red + utils.Bold + "Warning: the data on this volume will be lost." + utils.Reset)
+	if !confirm("Delete volume '" + volumeID + "'?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deleting volume")
+	err := ec2model.DeleteEBSVolume(volumeID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error deleting volume: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Volume '" + volumeID + "' deleted." + utils.Reset)
+}
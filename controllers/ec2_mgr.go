@@ -1,12 +1,96 @@
 package controllers
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	ec2controller "github.com/DragonEmperor9480/aws_cli_manager/controllers/ec2"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	ec2view "github.com/DragonEmperor9480/aws_cli_manager/views/ec2"
 )
 
 func EC2_mgr() {
-	// reader := bufio.NewReader(os.Stdin)
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		ec2view.ShowEC2Menu()
 
-	ec2view.ShowEC2Menu()
+		fmt.Print("Choose an option: ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
 
+		switch input {
+		case "1":
+			ec2controller.LaunchInstanceController()
+			utils.Bk()
+		case "2":
+			ec2controller.ListInstancesController()
+			utils.Bk()
+		case "3":
+			ec2controller.StartInstanceController()
+			utils.Bk()
+		case "4":
+			ec2controller.StopInstanceController()
+			utils.Bk()
+		case "5":
+			ec2controller.RebootInstanceController()
+			utils.Bk()
+		case "6":
+			ec2controller.TerminateInstanceController()
+			utils.Bk()
+		case "7":
+			ec2controller.ListVolumesController()
+			utils.Bk()
+		case "8":
+			ec2controller.CreateVolumeController()
+			utils.Bk()
+		case "9":
+			ec2controller.AttachVolumeController()
+			utils.Bk()
+		case "10":
+			ec2controller.DetachVolumeController()
+			utils.Bk()
+		case "11":
+			ec2controller.DeleteVolumeController()
+			utils.Bk()
+		case "12":
+			ec2controller.ListSnapshotsController()
+			utils.Bk()
+		case "13":
+			ec2controller.CreateSnapshotController()
+			utils.Bk()
+		case "14":
+			ec2controller.CopySnapshotController()
+			utils.Bk()
+		case "15":
+			ec2controller.DeleteSnapshotController()
+			utils.Bk()
+		case "16":
+			ec2controller.ListImagesController()
+			utils.Bk()
+		case "17":
+			ec2controller.DeregisterImagesController()
+			utils.Bk()
+		case "18":
+			ec2controller.SecurityGroupsMenuController()
+		case "19":
+			ec2controller.ListManagedInstancesController()
+			utils.Bk()
+		case "20":
+			ec2controller.StartSessionController()
+			utils.Bk()
+		case "21":
+			ec2controller.RunCommandController()
+			utils.Bk()
+		case "22":
+			fmt.Println("Returning to Main Menu...")
+			utils.ClearScreen()
+			return
+		default:
+			fmt.Println(utils.Red + "Invalid input. Please try again." + utils.Reset)
+			utils.Bk()
+		}
+	}
 }
@@ -5,20 +5,23 @@ import (
 
 	model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/group"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
-	view "github.com/DragonEmperor9480/aws_cli_manager/views/iam/group"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 )
 
 func ListGroupsController() {
 	utils.ShowProcessingAnimation("Loading IAM Groups")
-	output, err := model.FetchIAMGroups()
+	groups, err := model.FetchIAMGroups()
 	utils.StopAnimation()
 	fmt.Println()
 
 	if err != nil {
 		fmt.Println(utils.Bold + utils.Red + "Error fetching IAM groups!" + utils.Reset)
-		fmt.Println(output)
+		fmt.Println(err.Error())
 		return
 	}
 
-	view.ShowGroupsTable(output)
+	views.RenderTable(views.TableConfig{
+		Headers: model.IAMGroupTableHeaders,
+		Rows:    views.RowsOf(groups),
+	})
 }
@@ -0,0 +1,209 @@
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// InlinePoliciesController lists the inline policies of a user, group or role and lets them
+// be viewed, created, replaced and deleted
+func InlinePoliciesController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	identityType := readIdentityType(reader, "Manage inline policies of: ")
+	if identityType == "" {
+		return
+	}
+
+	fmt.Print("Enter " + identityType + " name: ")
+	input, _ := reader.ReadString('\n')
+	identityName := strings.TrimSpace(input)
+	if identityName == "" {
+		fmt.Println(utils.Red + "Name cannot be empty." + utils.Reset)
+		return
+	}
+
+	for {
+		names, ok := listInlinePolicies(identityType, identityName)
+		if !ok {
+			return
+		}
+
+		fmt.Println()
+		fmt.Println("  v) View a policy")
+		fmt.Println("  p) Put a policy (create or replace)")
+		fmt.Println("  d) Delete a policy")
+		fmt.Println("  q) Done")
+		fmt.Print("Choose an option: ")
+
+		input, _ := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "v":
+			if len(names) == 0 {
+				continue
+			}
+			index, ok := readIndex(reader, "Enter policy number to view: ", len(names))
+			if !ok {
+				continue
+			}
+			viewInlinePolicy(identityType, identityName, names[index])
+		case "p":
+			putInlinePolicy(reader, identityType, identityName, names)
+		case "d":
+			if len(names) == 0 {
+				continue
+			}
+			index, ok := readIndex(reader, "Enter policy number to delete: ", len(names))
+			if !ok {
+				continue
+			}
+			deleteInlinePolicy(reader, identityType, identityName, names[index])
+		case "q", "":
+			return
+		default:
+			fmt.Println(utils.Red + "Invalid option." + utils.Reset)
+		}
+	}
+}
+
+// readIdentityType asks whether to work on a user, group or role
+func readIdentityType(reader *bufio.Reader, prompt string) string {
+	fmt.Println("  1) User")
+	fmt.Println("  2) Group")
+	fmt.Println("  3) Role")
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+
+	switch strings.TrimSpace(input) {
+	case "1":
+		return policy.IdentityUser
+	case "2":
+		return policy.IdentityGroup
+	case "3":
+		return policy.IdentityRole
+	}
+	fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+	return ""
+}
+
+// listInlinePolicies renders the inline policy names of an identity and returns them
+func listInlinePolicies(identityType, identityName string) ([]string, bool) {
+	utils.ShowProcessingAnimation("Loading inline policies")
+	names, err := policy.ListInlinePolicies(identityType, identityName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error listing inline policies: " + err.Error() + utils.Reset)
+		return nil, false
+	}
+	if len(names) == 0 {
+		fmt.Println(utils.Yellow + "The " + identityType + " '" + identityName + "' has no inline policies." + utils.Reset)
+		return names, true
+	}
+
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, []string{name})
+	}
+	views.RenderTable(views.TableConfig{
+		Headers: []string{"Inline Policy"},
+		Rows:    rows,
+	})
+	return names, true
+}
+
+func viewInlinePolicy(identityType, identityName, policyName string) {
+	utils.ShowProcessingAnimation("Loading policy document")
+	inline, err := policy.GetInlinePolicy(identityType, identityName, policyName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading policy document: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Bold + policyName + utils.Reset)
+	fmt.Println(inline.Document)
+}
+
+// putInlinePolicy creates an inline policy or replaces an existing one, showing the diff
+// against the current document before a replacement
+func putInlinePolicy(reader *bufio.Reader, identityType, identityName string, existing []string) {
+	fmt.Print("Policy name (an existing name replaces that policy): ")
+	input, _ := reader.ReadString('\n')
+	policyName := strings.TrimSpace(input)
+	if policyName == "" {
+		fmt.Println(utils.Red + "Policy name cannot be empty." + utils.Reset)
+		return
+	}
+
+	current := ""
+	for _, name := range existing {
+		if name == policyName {
+			utils.ShowProcessingAnimation("Loading current document")
+			inline, err := policy.GetInlinePolicy(identityType, identityName, policyName)
+			utils.StopAnimation()
+			if err != nil {
+				fmt.Println(utils.Red + "Error loading policy document: " + err.Error() + utils.Reset)
+				return
+			}
+			current = inline.Document
+		}
+	}
+
+	initial := policyTemplate
+	if current != "" {
+		initial = current + "\n"
+	}
+	document, ok := ReadPolicyDocument(reader, initial)
+	if !ok {
+		return
+	}
+
+	if current != "" {
+		diff, err := policy.DiffPolicyDocuments(current, document, "current", "new")
+		if err != nil {
+			fmt.Println(utils.Red + "Error comparing documents: " + err.Error() + utils.Reset)
+			return
+		}
+		if diff == "" {
+			fmt.Println(utils.Yellow + "The new document is identical to the current one. Nothing to do." + utils.Reset)
+			return
+		}
+		PrintDiff(diff)
+		if !confirm(reader, "Replace "+policyName+"?") {
+			return
+		}
+	}
+
+	utils.ShowProcessingAnimation("Saving inline policy")
+	err := policy.PutInlinePolicy(identityType, identityName, policyName, document)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error saving inline policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Inline policy " + policyName + " saved." + utils.Reset)
+}
+
+func deleteInlinePolicy(reader *bufio.Reader, identityType, identityName, policyName string) {
+	if !confirm(reader, "Delete inline policy "+policyName+" from "+identityName+"?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deleting inline policy")
+	err := policy.DeleteInlinePolicy(identityType, identityName, policyName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error deleting inline policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + "Inline policy " + policyName + " deleted." + utils.Reset)
+}
@@ -0,0 +1,531 @@
+package policy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+	iamview "github.com/DragonEmperor9480/aws_cli_manager/views/iam"
+)
+
+// policyTemplate is the starting document offered when a policy is written in the editor
+const policyTemplate = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": [],
+      "Resource": []
+    }
+  ]
+}
+`
+
+// PoliciesMenuController runs the customer-managed policy sub-menu
+func PoliciesMenuController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		iamview.ShowPoliciesMenu()
+
+		fmt.Print("Enter your choice: ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		switch input {
+		case "1":
+			listManagedPolicies()
+			utils.Bk()
+		case "2":
+			ViewPolicyDocumentController()
+			utils.Bk()
+		case "3":
+			CreatePolicyController()
+			utils.Bk()
+		case "4":
+			UpdatePolicyController()
+			utils.Bk()
+		case "5":
+			ListPolicyVersionsController()
+			utils.Bk()
+		case "6":
+			DiffPolicyVersionsController()
+			utils.Bk()
+		case "7":
+			RollbackPolicyController()
+			utils.Bk()
+		case "8":
+			DeletePolicyVersionController()
+			utils.Bk()
+		case "9":
+			DeletePolicyController()
+			utils.Bk()
+		case "10":
+			return
+		default:
+			fmt.Println(utils.Red + "Invalid Input. Please try again." + utils.Reset)
+			utils.Bk()
+		}
+	}
+}
+
+// ViewPolicyDocumentController prints the default version of a policy
+func ViewPolicyDocumentController() {
+	selected := selectManagedPolicy("Enter policy number to view: ")
+	if selected == nil {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Loading policy document")
+	document, err := policy.FetchPolicyDocument(selected.PolicyArn, "")
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading policy document: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Bold + selected.PolicyArn + utils.Reset)
+	fmt.Println(document)
+}
+
+// CreatePolicyController creates a customer-managed policy from a JSON file or the editor
+func CreatePolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Policy name: ")
+	input, _ := reader.ReadString('\n')
+	name := strings.TrimSpace(input)
+	if name == "" {
+		fmt.Println(utils.Red + "Policy name cannot be empty." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Path (default /): ")
+	input, _ = reader.ReadString('\n')
+	path := strings.TrimSpace(input)
+
+	fmt.Print("Description (optional): ")
+	input, _ = reader.ReadString('\n')
+	description := strings.TrimSpace(input)
+
+	document, ok := ReadPolicyDocument(reader, policyTemplate)
+	if !ok {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Creating policy")
+	created, err := policy.CreateManagedPolicy(name, path, description, document)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error creating policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Policy created: " + created.PolicyArn + utils.Reset)
+}
+
+// UpdatePolicyController stores a new document as the default version after showing the
+// diff against the current default
+func UpdatePolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectManagedPolicy("Enter policy number to update: ")
+	if selected == nil {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Loading current version")
+	current, err := policy.FetchPolicyDocument(selected.PolicyArn, "")
+	utils.StopAnimation()
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading policy document: " + err.Error() + utils.Reset)
+		return
+	}
+
+	document, ok := ReadPolicyDocument(reader, current+"\n")
+	if !ok {
+		return
+	}
+
+	diff, err := policy.DiffPolicyDocuments(current, document, "current", "new")
+	if err != nil {
+		fmt.Println(utils.Red + "Error comparing documents: " + err.Error() + utils.Reset)
+		return
+	}
+	if diff == "" {
+		fmt.Println(utils.Yellow + "The new document is identical to the current version. Nothing to do." + utils.Reset)
+		return
+	}
+	PrintDiff(diff)
+
+	if !confirm(reader, "Save this as the new default version?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Creating policy version")
+	version, pruned, err := policy.UpdateManagedPolicy(selected.PolicyArn, document, false)
+	utils.StopAnimation()
+
+	if errors.Is(err, policy.ErrPolicyVersionLimit) {
+		fmt.Println(utils.Yellow + err.Error() + utils.Reset)
+		if !confirm(reader, "Delete the oldest non-default version to make room?") {
+			return
+		}
+		utils.ShowProcessingAnimation("Creating policy version")
+		version, pruned, err = policy.UpdateManagedPolicy(selected.PolicyArn, document, true)
+		utils.StopAnimation()
+	}
+
+	if pruned != "" {
+		fmt.Println(utils.Yellow + "Deleted old version " + pruned + "." + utils.Reset)
+	}
+	if err != nil {
+		fmt.Println(utils.Red + "Error updating policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Version " + version.VersionID + " is now the default." + utils.Reset)
+}
+
+// ListPolicyVersionsController lists the stored versions of a policy
+func ListPolicyVersionsController() {
+	selected := selectManagedPolicy("Enter policy number to list versions for: ")
+	if selected == nil {
+		return
+	}
+	listPolicyVersions(selected.PolicyArn)
+}
+
+// DiffPolicyVersionsController prints a unified diff between two versions of a policy
+func DiffPolicyVersionsController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectManagedPolicy("Enter policy number to compare versions of: ")
+	if selected == nil {
+		return
+	}
+	versions := listPolicyVersions(selected.PolicyArn)
+	if len(versions) < 2 {
+		fmt.Println(utils.Yellow + "The policy has only one version." + utils.Reset)
+		return
+	}
+
+	from := selectPolicyVersion(reader, versions, "Compare from version number: ")
+	if from == nil {
+		return
+	}
+	to := selectPolicyVersion(reader, versions, "To version number: ")
+	if to == nil {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Comparing versions")
+	diff, err := policy.DiffPolicyVersions(selected.PolicyArn, from.VersionID, to.VersionID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error comparing versions: " + err.Error() + utils.Reset)
+		return
+	}
+	if diff == "" {
+		fmt.Println(utils.Green + "The versions are identical." + utils.Reset)
+		return
+	}
+	PrintDiff(diff)
+}
+
+// RollbackPolicyController makes an earlier version the default
+func RollbackPolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectManagedPolicy("Enter policy number to roll back: ")
+	if selected == nil {
+		return
+	}
+	versions := listPolicyVersions(selected.PolicyArn)
+	if versions == nil {
+		return
+	}
+
+	version := selectPolicyVersion(reader, versions, "Version number to make the default: ")
+	if version == nil {
+		return
+	}
+	if version.IsDefault {
+		fmt.Println(utils.Yellow + version.VersionID + " is already the default version." + utils.Reset)
+		return
+	}
+
+	current := ""
+	for _, v := range versions {
+		if v.IsDefault {
+			current = v.VersionID
+		}
+	}
+	if diff, err := policy.DiffPolicyVersions(selected.PolicyArn, current, version.VersionID); err == nil && diff != "" {
+		PrintDiff(diff)
+	}
+	if !confirm(reader, "Make "+version.VersionID+" the default version?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Setting default version")
+	err := policy.SetDefaultPolicyVersion(selected.PolicyArn, version.VersionID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error setting default version: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Version " + version.VersionID + " is now the default." + utils.Reset)
+}
+
+// DeletePolicyVersionController deletes a non-default version of a policy
+func DeletePolicyVersionController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectManagedPolicy("Enter policy number to delete a version of: ")
+	if selected == nil {
+		return
+	}
+	versions := listPolicyVersions(selected.PolicyArn)
+	if versions == nil {
+		return
+	}
+
+	version := selectPolicyVersion(reader, versions, "Version number to delete: ")
+	if version == nil {
+		return
+	}
+	if version.IsDefault {
+		fmt.Println(utils.Yellow + "The default version cannot be deleted. Roll back to another version first." + utils.Reset)
+		return
+	}
+	if !confirm(reader, "Delete version "+version.VersionID+"?") {
+		return
+	}
+
+	if err := policy.DeletePolicyVersion(selected.PolicyArn, version.VersionID); err != nil {
+		fmt.Println(utils.Red + "Error deleting version: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + "Version " + version.VersionID + " deleted." + utils.Reset)
+}
+
+// DeletePolicyController deletes a customer-managed policy, detaching it first on request
+func DeletePolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectManagedPolicy("Enter policy number to delete: ")
+	if selected == nil {
+		return
+	}
+	if !confirm(reader, "Delete policy "+selected.PolicyName+"? This cannot be undone.") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deleting policy")
+	err := policy.DeleteManagedPolicy(selected.PolicyArn, false)
+	utils.StopAnimation()
+
+	if errors.Is(err, policy.ErrPolicyAttached) {
+		fmt.Println(utils.Yellow + err.Error() + utils.Reset)
+		if !confirm(reader, "Detach it from everything and delete it?") {
+			return
+		}
+		utils.ShowProcessingAnimation("Detaching and deleting policy")
+		err = policy.DeleteManagedPolicy(selected.PolicyArn, true)
+		utils.StopAnimation()
+	}
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error deleting policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Policy " + selected.PolicyName + " deleted." + utils.Reset)
+}
+
+// listManagedPolicies renders the customer-managed policies and returns them
+func listManagedPolicies() []policy.Policy {
+	utils.ShowProcessingAnimation("Loading customer-managed policies")
+	policies, err := policy.ListPoliciesModel("Local")
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error listing policies: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(policies) == 0 {
+		fmt.Println(utils.Yellow + "No customer-managed policies found." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: policy.PolicyTableHeaders,
+		Rows:    views.RowsOf(policies),
+	})
+	return policies
+}
+
+// selectManagedPolicy lists the customer-managed policies and asks for one by number
+func selectManagedPolicy(prompt string) *policy.Policy {
+	policies := listManagedPolicies()
+	if policies == nil {
+		return nil
+	}
+
+	index, ok := readIndex(bufio.NewReader(os.Stdin), prompt, len(policies))
+	if !ok {
+		return nil
+	}
+	return &policies[index]
+}
+
+// listPolicyVersions renders the versions of a policy and returns them
+func listPolicyVersions(policyArn string) []policy.PolicyVersion {
+	utils.ShowProcessingAnimation("Loading policy versions")
+	versions, err := policy.FetchPolicyVersions(policyArn)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error listing policy versions: " + err.Error() + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: policy.PolicyVersionTableHeaders,
+		Rows:    views.RowsOf(versions),
+	})
+	return versions
+}
+
+func selectPolicyVersion(reader *bufio.Reader, versions []policy.PolicyVersion, prompt string) *policy.PolicyVersion {
+	index, ok := readIndex(reader, prompt, len(versions))
+	if !ok {
+		return nil
+	}
+	return &versions[index]
+}
+
+// readIndex reads a 1-based table number and returns it as a 0-based index
+func readIndex(reader *bufio.Reader, prompt string, count int) (int, bool) {
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+
+	index, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || index < 1 || index > count {
+		fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+		return 0, false
+	}
+	return index - 1, true
+}
+
+// ReadPolicyDocument reads a policy document from a file, or from the editor prefilled
+// with initial, and validates it locally
+func ReadPolicyDocument(reader *bufio.Reader, initial string) (string, bool) {
+	fmt.Print("Path to policy JSON file (press Enter to open an editor): ")
+	input, _ := reader.ReadString('\n')
+	path := strings.TrimSpace(input)
+
+	var document string
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Println(utils.Red + "Error reading file: " + err.Error() + utils.Reset)
+			return "", false
+		}
+		document = string(data)
+	} else {
+		edited, err := editInEditor(initial)
+		if err != nil {
+			fmt.Println(utils.Red + "Error running editor: " + err.Error() + utils.Reset)
+			return "", false
+		}
+		document = edited
+	}
+
+	if err := policy.ValidatePolicyDocument(document); err != nil {
+		fmt.Println(utils.Red + "Invalid policy document: " + err.Error() + utils.Reset)
+		return "", false
+	}
+	return document, true
+}
+
+// editInEditor opens $VISUAL or $EDITOR on a temporary file containing initial and returns
+// the saved content
+func editInEditor(initial string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	file, err := os.CreateTemp("", "awsmgr-policy-*.json")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(initial); err != nil {
+		file.Close()
+		return "", err
+	}
+	file.Close()
+
+	// The editor setting may carry arguments, such as "code --wait"
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// PrintDiff prints a unified diff with added lines in green and removed lines in red
+func PrintDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Println(utils.Bold + line + utils.Reset)
+		case strings.HasPrefix(line, "@@"):
+			fmt.Println(utils.Cyan + line + utils.Reset)
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(utils.Green + line + utils.Reset)
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(utils.Red + line + utils.Reset)
+		default:
+			fmt.Println(line)
+		}
+	}
+}
+
+// confirm asks a yes/no question and reports whether the answer was yes
+func confirm(reader *bufio.Reader, question string) bool {
+	fmt.Print(question + " (y/n): ")
+	input, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(input)) != "y" {
+		fmt.Println(utils.Yellow + "Operation cancelled." + utils.Reset)
+		return false
+	}
+	return true
+}
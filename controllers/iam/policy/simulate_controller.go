@@ -0,0 +1,159 @@
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// SimulatePolicyController answers "can this user, group or role do these actions on these
+// resources?" using the IAM policy simulator
+func SimulatePolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	identityType := readIdentityType(reader, "Simulate the permissions of: ")
+	if identityType == "" {
+		return
+	}
+
+	fmt.Print("Enter " + identityType + " name: ")
+	input, _ := reader.ReadString('\n')
+	identityName := strings.TrimSpace(input)
+	if identityName == "" {
+		fmt.Println(utils.Red + "Name cannot be empty." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Actions, comma-separated (e.g. s3:PutObject, s3:GetObject): ")
+	input, _ = reader.ReadString('\n')
+	actions := splitList(input)
+	if len(actions) == 0 {
+		fmt.Println(utils.Red + "At least one action is required." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Resource ARNs, comma-separated (press Enter for *): ")
+	input, _ = reader.ReadString('\n')
+	resources := splitList(input)
+
+	utils.ShowProcessingAnimation("Simulating policies")
+	principalArn, err := policy.ResolvePrincipalArn(identityType, identityName)
+	var results []policy.SimulationResult
+	if err == nil {
+		results, err = policy.SimulatePrincipalPolicy(principalArn, actions, resources)
+	}
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error running the simulation: " + err.Error() + utils.Reset)
+		return
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: policy.SimulationTableHeaders,
+		Rows:    views.RowsOf(results),
+	})
+
+	for _, result := range results {
+		status := utils.Green + "ALLOWED" + utils.Reset
+		if !result.Allowed {
+			status = utils.Red + "DENIED" + utils.Reset
+		}
+		fmt.Printf("%s %s on %s\n", status, result.Action, result.Resource)
+		if len(result.MissingContextValues) > 0 {
+			fmt.Println(utils.Yellow + "  The result may differ at request time; missing context keys: " + strings.Join(result.MissingContextValues, ", ") + utils.Reset)
+		}
+	}
+}
+
+// PermissionsBoundaryController shows the permissions boundary of a user and sets or removes it
+func PermissionsBoundaryController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Enter username: ")
+	input, _ := reader.ReadString('\n')
+	username := strings.TrimSpace(input)
+	if username == "" {
+		fmt.Println(utils.Red + "Username cannot be empty." + utils.Reset)
+		return
+	}
+
+	utils.ShowProcessingAnimation("Loading permissions boundary")
+	current, err := policy.FetchUserPermissionsBoundary(username)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading user: " + err.Error() + utils.Reset)
+		return
+	}
+	if current == "" {
+		fmt.Println(utils.Yellow + username + " has no permissions boundary." + utils.Reset)
+	} else {
+		fmt.Println("Permissions boundary of " + username + ": " + utils.Bold + current + utils.Reset)
+	}
+
+	fmt.Println()
+	fmt.Println("  s) Set permissions boundary")
+	if current != "" {
+		fmt.Println("  r) Remove permissions boundary")
+	}
+	fmt.Println("  q) Done")
+	fmt.Print("Choose an option: ")
+	input, _ = reader.ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "s":
+		fmt.Print("Boundary policy ARN or name: ")
+		input, _ = reader.ReadString('\n')
+		policyArn := strings.TrimSpace(input)
+		if policyArn == "" {
+			fmt.Println(utils.Red + "Policy cannot be empty." + utils.Reset)
+			return
+		}
+
+		utils.ShowProcessingAnimation("Setting permissions boundary")
+		if !strings.HasPrefix(policyArn, "arn:") {
+			policyArn, err = policy.FindManagedPolicyArn(policyArn)
+		}
+		if err == nil {
+			err = policy.SetUserPermissionsBoundary(username, policyArn)
+		}
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Red + "Error setting permissions boundary: " + err.Error() + utils.Reset)
+			return
+		}
+		fmt.Println(utils.Green + utils.Bold + "Permissions boundary of " + username + " set to " + policyArn + "." + utils.Reset)
+	case "r":
+		if current == "" || !confirm(reader, "Remove the permissions boundary of "+username+"?") {
+			return
+		}
+
+		utils.ShowProcessingAnimation("Removing permissions boundary")
+		err := policy.RemoveUserPermissionsBoundary(username)
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Red + "Error removing permissions boundary: " + err.Error() + utils.Reset)
+			return
+		}
+		fmt.Println(utils.Green + utils.Bold + "Permissions boundary of " + username + " removed." + utils.Reset)
+	}
+}
+
+// splitList splits a comma-separated answer into its non-empty trimmed items
+func splitList(input string) []string {
+	items := []string{}
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,532 @@
+package role
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	policy_controller "github.com/DragonEmperor9480/aws_cli_manager/controllers/iam/policy"
+	policy_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	role_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/role"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+	iamview "github.com/DragonEmperor9480/aws_cli_manager/views/iam"
+)
+
+// RolesMenuController runs the IAM role sub-menu
+func RolesMenuController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		iamview.ShowRolesMenu()
+
+		fmt.Print("Enter your choice: ")
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(input)
+
+		switch input {
+		case "1":
+			listRoles()
+			utils.Bk()
+		case "2":
+			CreateRoleController()
+			utils.Bk()
+		case "3":
+			TrustPolicyController()
+			utils.Bk()
+		case "4":
+			ListRolePoliciesController()
+			utils.Bk()
+		case "5":
+			AttachRolePolicyController()
+			utils.Bk()
+		case "6":
+			DetachRolePolicyController()
+			utils.Bk()
+		case "7":
+			DeleteRoleController()
+			utils.Bk()
+		case "8":
+			listInstanceProfiles()
+			utils.Bk()
+		case "9":
+			CreateInstanceProfileController()
+			utils.Bk()
+		case "10":
+			LinkRoleToInstanceProfileController()
+			utils.Bk()
+		case "11":
+			UnlinkRoleFromInstanceProfileController()
+			utils.Bk()
+		case "12":
+			DeleteInstanceProfileController()
+			utils.Bk()
+		case "13":
+			return
+		default:
+			fmt.Println(utils.Red + "Invalid Input. Please try again." + utils.Reset)
+			utils.Bk()
+		}
+	}
+}
+
+// CreateRoleController creates a role trusted by an AWS service or by a custom trust policy
+func CreateRoleController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Role name: ")
+	input, _ := reader.ReadString('\n')
+	roleName := strings.TrimSpace(input)
+	if roleName == "" {
+		fmt.Println(utils.Red + "Role name cannot be empty." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Path (default /): ")
+	input, _ = reader.ReadString('\n')
+	path := strings.TrimSpace(input)
+
+	fmt.Print("Description (optional): ")
+	input, _ = reader.ReadString('\n')
+	description := strings.TrimSpace(input)
+
+	fmt.Print("Trusted service, e.g. ec2.amazonaws.com (press Enter to write a custom trust policy): ")
+	input, _ = reader.ReadString('\n')
+	service := strings.TrimSpace(input)
+
+	var trustPolicy string
+	if service != "" {
+		trustPolicy = role_model.ServiceTrustPolicy(service)
+	} else {
+		document, ok := policy_controller.ReadPolicyDocument(reader, role_model.ServiceTrustPolicy("ec2.amazonaws.com")+"\n")
+		if !ok {
+			return
+		}
+		trustPolicy = document
+	}
+
+	utils.ShowProcessingAnimation("Creating IAM Role")
+	created, err := role_model.CreateIAMRole(roleName, path, description, trustPolicy)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error creating role: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Role created: " + created.Arn + utils.Reset)
+}
+
+// TrustPolicyController shows the trust policy of a role and optionally replaces it
+func TrustPolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectRole(reader, "Enter role number: ")
+	if selected == nil {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Loading trust policy")
+	current, err := role_model.FetchTrustPolicy(selected.RoleName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading trust policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Bold + "Trust policy of " + selected.RoleName + utils.Reset)
+	fmt.Println(current)
+
+	fmt.Print("Edit the trust policy? (y/n): ")
+	input, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(input)) != "y" {
+		return
+	}
+
+	document, ok := policy_controller.ReadPolicyDocument(reader, current+"\n")
+	if !ok {
+		return
+	}
+
+	diff, err := policy_model.DiffPolicyDocuments(current, document, "current", "new")
+	if err != nil {
+		fmt.Println(utils.Red + "Error comparing documents: " + err.Error() + utils.Reset)
+		return
+	}
+	if diff == "" {
+		fmt.Println(utils.Yellow + "The trust policy is unchanged." + utils.Reset)
+		return
+	}
+	policy_controller.PrintDiff(diff)
+	if !confirmAction(reader, "Replace the trust policy?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Updating trust policy")
+	err = role_model.UpdateTrustPolicy(selected.RoleName, document)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error updating trust policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Trust policy updated." + utils.Reset)
+}
+
+// ListRolePoliciesController lists the managed policies attached to a role
+func ListRolePoliciesController() {
+	selected := selectRole(bufio.NewReader(os.Stdin), "Enter role number: ")
+	if selected == nil {
+		return
+	}
+	listRolePolicies(selected.RoleName)
+}
+
+// AttachRolePolicyController attaches a managed policy, given by ARN or name, to a role
+func AttachRolePolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectRole(reader, "Enter role number to attach a policy to: ")
+	if selected == nil {
+		return
+	}
+
+	fmt.Print("Policy ARN or name: ")
+	input, _ := reader.ReadString('\n')
+	policyArn := strings.TrimSpace(input)
+	if policyArn == "" {
+		fmt.Println(utils.Red + "Policy cannot be empty." + utils.Reset)
+		return
+	}
+
+	if !strings.HasPrefix(policyArn, "arn:") {
+		utils.ShowProcessingAnimation("Looking up policy")
+		arn, err := policy_model.FindManagedPolicyArn(policyArn)
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Red + err.Error() + utils.Reset)
+			return
+		}
+		policyArn = arn
+	}
+
+	utils.ShowProcessingAnimation("Attaching policy")
+	err := role_model.AttachRolePolicy(selected.RoleName, policyArn)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Attached " + policyArn + " to " + selected.RoleName + "." + utils.Reset)
+}
+
+// DetachRolePolicyController detaches one of the managed policies attached to a role
+func DetachRolePolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectRole(reader, "Enter role number to detach a policy from: ")
+	if selected == nil {
+		return
+	}
+
+	policies := listRolePolicies(selected.RoleName)
+	if len(policies) == 0 {
+		return
+	}
+	index, ok := readIndex(reader, "Enter policy number to detach: ", len(policies))
+	if !ok {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Detaching policy")
+	err := role_model.DetachRolePolicy(selected.RoleName, policies[index].PolicyArn)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Detached " + policies[index].PolicyName + " from " + selected.RoleName + "." + utils.Reset)
+}
+
+// DeleteRoleController deletes a role after showing what still depends on it
+func DeleteRoleController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	selected := selectRole(reader, "Enter role number to delete: ")
+	if selected == nil {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Checking role dependencies")
+	deps, err := role_model.CheckRoleDependencies(selected.RoleName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error checking dependencies: " + err.Error() + utils.Reset)
+		return
+	}
+
+	force := false
+	if deps.HasDependencies() {
+		fmt.Println(utils.Yellow + "Role '" + selected.RoleName + "' has the following dependencies:" + utils.Reset)
+		if len(deps.AttachedPolicies) > 0 {
+			fmt.Println(utils.Yellow+"- Attached policies:", strings.Join(deps.AttachedPolicies, ", ")+utils.Reset)
+		}
+		if len(deps.InlinePolicies) > 0 {
+			fmt.Println(utils.Yellow+"- Inline policies:", strings.Join(deps.InlinePolicies, ", ")+utils.Reset)
+		}
+		if len(deps.InstanceProfiles) > 0 {
+			fmt.Println(utils.Yellow+"- Instance profiles:", strings.Join(deps.InstanceProfiles, ", ")+utils.Reset)
+		}
+		if !confirmAction(reader, "Detach policies, delete inline policies and unlink instance profiles before deleting?") {
+			return
+		}
+		force = true
+	} else if !confirmAction(reader, "Delete role '"+selected.RoleName+"'?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deleting IAM Role")
+	err = role_model.DeleteIAMRole(selected.RoleName, force)
+	utils.StopAnimation()
+
+	if errors.Is(err, role_model.ErrRoleHasDependencies) {
+		fmt.Println(utils.Red + "The role gained dependencies while deleting. Try again." + utils.Reset)
+		return
+	}
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Role '" + selected.RoleName + "' deleted." + utils.Reset)
+}
+
+// CreateInstanceProfileController creates an instance profile, optionally linking a role to it
+func CreateInstanceProfileController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print("Instance profile name: ")
+	input, _ := reader.ReadString('\n')
+	profileName := strings.TrimSpace(input)
+	if profileName == "" {
+		fmt.Println(utils.Red + "Instance profile name cannot be empty." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Role to link (press Enter for none): ")
+	input, _ = reader.ReadString('\n')
+	roleName := strings.TrimSpace(input)
+
+	utils.ShowProcessingAnimation("Creating instance profile")
+	profile, err := role_model.CreateInstanceProfile(profileName, "", roleName)
+	utils.StopAnimation()
+
+	if profile == nil {
+		fmt.Println(utils.Red + "Error creating instance profile: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Instance profile created: " + profile.Arn + utils.Reset)
+	if err != nil {
+		fmt.Println(utils.Red + "Error linking role: " + err.Error() + utils.Reset)
+	}
+}
+
+// LinkRoleToInstanceProfileController adds a role to an instance profile
+func LinkRoleToInstanceProfileController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	profile := selectInstanceProfile(reader, "Enter instance profile number: ")
+	if profile == nil {
+		return
+	}
+	if len(profile.Roles) > 0 {
+		fmt.Println(utils.Yellow + "The instance profile already holds role " + profile.Roles[0] + ". Unlink it first." + utils.Reset)
+		return
+	}
+
+	selected := selectRole(reader, "Enter role number to link: ")
+	if selected == nil {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Linking role")
+	err := role_model.AddRoleToInstanceProfile(profile.InstanceProfileName, selected.RoleName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Linked " + selected.RoleName + " to " + profile.InstanceProfileName + "." + utils.Reset)
+}
+
+// UnlinkRoleFromInstanceProfileController removes the role from an instance profile
+func UnlinkRoleFromInstanceProfileController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	profile := selectInstanceProfile(reader, "Enter instance profile number: ")
+	if profile == nil {
+		return
+	}
+	if len(profile.Roles) == 0 {
+		fmt.Println(utils.Yellow + "The instance profile has no role." + utils.Reset)
+		return
+	}
+
+	roleName := profile.Roles[0]
+	utils.ShowProcessingAnimation("Unlinking role")
+	err := role_model.RemoveRoleFromInstanceProfile(profile.InstanceProfileName, roleName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Unlinked " + roleName + " from " + profile.InstanceProfileName + "." + utils.Reset)
+}
+
+// DeleteInstanceProfileController deletes an instance profile, unlinking its role first
+func DeleteInstanceProfileController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	profile := selectInstanceProfile(reader, "Enter instance profile number to delete: ")
+	if profile == nil {
+		return
+	}
+	if !confirmAction(reader, "Delete instance profile '"+profile.InstanceProfileName+"'?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deleting instance profile")
+	err := role_model.DeleteInstanceProfile(profile.InstanceProfileName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Instance profile '" + profile.InstanceProfileName + "' deleted." + utils.Reset)
+}
+
+// listRoles renders all roles and returns them
+func listRoles() []role_model.IAMRole {
+	utils.ShowProcessingAnimation("Loading IAM Roles")
+	roles, warnings, err := role_model.FetchIAMRolesWithTags()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Bold + utils.Red + "Error fetching IAM roles: " + err.Error() + utils.Reset)
+		return nil
+	}
+	for _, warning := range warnings {
+		fmt.Println(utils.Yellow + "Warning: " + warning + utils.Reset)
+	}
+	if len(roles) == 0 {
+		fmt.Println(utils.Yellow + "No IAM roles found." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: role_model.IAMRoleTableHeaders,
+		Rows:    views.RowsOf(roles),
+	})
+	return roles
+}
+
+func selectRole(reader *bufio.Reader, prompt string) *role_model.IAMRole {
+	roles := listRoles()
+	if roles == nil {
+		return nil
+	}
+
+	index, ok := readIndex(reader, prompt, len(roles))
+	if !ok {
+		return nil
+	}
+	return &roles[index]
+}
+
+// listRolePolicies renders the managed policies attached to a role and returns them
+func listRolePolicies(roleName string) []role_model.AttachedPolicy {
+	utils.ShowProcessingAnimation("Loading attached policies")
+	policies, err := role_model.ListRolePolicies(roleName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(policies) == 0 {
+		fmt.Println(utils.Yellow + "No managed policies are attached to " + roleName + "." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: role_model.AttachedPolicyTableHeaders,
+		Rows:    views.RowsOf(policies),
+	})
+	return policies
+}
+
+// listInstanceProfiles renders all instance profiles and returns them
+func listInstanceProfiles() []role_model.InstanceProfile {
+	utils.ShowProcessingAnimation("Loading instance profiles")
+	profiles, err := role_model.FetchInstanceProfiles()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error fetching instance profiles: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(profiles) == 0 {
+		fmt.Println(utils.Yellow + "No instance profiles found." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: role_model.InstanceProfileTableHeaders,
+		Rows:    views.RowsOf(profiles),
+	})
+	return profiles
+}
+
+func selectInstanceProfile(reader *bufio.Reader, prompt string) *role_model.InstanceProfile {
+	profiles := listInstanceProfiles()
+	if profiles == nil {
+		return nil
+	}
+
+	index, ok := readIndex(reader, prompt, len(profiles))
+	if !ok {
+		return nil
+	}
+	return &profiles[index]
+}
+
+// readIndex reads a 1-based table number and returns it as a 0-based index
+func readIndex(reader *bufio.Reader, prompt string, count int) (int, bool) {
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+
+	index, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || index < 1 || index > count {
+		fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+		return 0, false
+	}
+	return index - 1, true
+}
+
+func confirmAction(reader *bufio.Reader, question string) bool {
+	fmt.Print(utils.Bold + question + " (y/n): " + utils.Reset)
+	input, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(input)) != "y" {
+		fmt.Println(utils.Yellow + "Operation cancelled." + utils.Reset)
+		return false
+	}
+	return true
+}
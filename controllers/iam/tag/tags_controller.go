@@ -0,0 +1,125 @@
+package tag
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	tag_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/tag"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// TagsController lists the tags of a user or role and adds or removes tags
+func TagsController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println("  1) User")
+	fmt.Println("  2) Role")
+	fmt.Print("Manage the tags of: ")
+	input, _ := reader.ReadString('\n')
+
+	var identityType string
+	switch strings.TrimSpace(input) {
+	case "1":
+		identityType = policy.IdentityUser
+	case "2":
+		identityType = policy.IdentityRole
+	default:
+		fmt.Println(utils.Red + "Invalid choice." + utils.Reset)
+		return
+	}
+
+	fmt.Print("Enter " + identityType + " name: ")
+	input, _ = reader.ReadString('\n')
+	name := strings.TrimSpace(input)
+	if name == "" {
+		fmt.Println(utils.Red + "Name cannot be empty." + utils.Reset)
+		return
+	}
+
+	if !showTags(identityType, name) {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("  a) Add or update tags")
+	fmt.Println("  r) Remove tags")
+	fmt.Println("  q) Done")
+	fmt.Print("Choose an option: ")
+	input, _ = reader.ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "a":
+		fmt.Print("Tags as key=value, separated by ';' (e.g. team=payments; cost-center=42): ")
+		input, _ = reader.ReadString('\n')
+		tags, err := tag_model.Parse(input)
+		if err != nil {
+			fmt.Println(utils.Red + err.Error() + utils.Reset)
+			return
+		}
+		if len(tags) == 0 {
+			fmt.Println(utils.Yellow + "No tags given." + utils.Reset)
+			return
+		}
+
+		utils.ShowProcessingAnimation("Adding tags")
+		err = tag_model.AddTags(identityType, name, tags)
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Red + "Error adding tags: " + err.Error() + utils.Reset)
+			return
+		}
+		fmt.Println(utils.Green + utils.Bold + "Tags of " + identityType + " '" + name + "' updated." + utils.Reset)
+		showTags(identityType, name)
+	case "r":
+		fmt.Print("Tag keys to remove, comma-separated: ")
+		input, _ = reader.ReadString('\n')
+		var keys []string
+		for _, key := range strings.Split(input, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			fmt.Println(utils.Yellow + "No keys given." + utils.Reset)
+			return
+		}
+
+		utils.ShowProcessingAnimation("Removing tags")
+		err := tag_model.RemoveTags(identityType, name, keys)
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Red + "Error removing tags: " + err.Error() + utils.Reset)
+			return
+		}
+		fmt.Println(utils.Green + utils.Bold + "Removed " + strings.Join(keys, ", ") + " from " + identityType + " '" + name + "'." + utils.Reset)
+		showTags(identityType, name)
+	}
+}
+
+// showTags renders the tags of a user or role and reports whether they could be loaded
+func showTags(identityType, name string) bool {
+	utils.ShowProcessingAnimation("Loading tags")
+	tags, err := tag_model.ListTags(identityType, name)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading tags: " + err.Error() + utils.Reset)
+		return false
+	}
+	if len(tags) == 0 {
+		fmt.Println(utils.Yellow + strings.ToUpper(identityType[:1]) + identityType[1:] + " '" + name + "' has no tags." + utils.Reset)
+		return true
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: tag_model.TagTableHeaders,
+		Rows:    views.RowsOf(tags),
+	})
+	return true
+}
@@ -8,22 +8,51 @@ import (
 
 	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 )
 
 func ListAccessKeysForUserController() {
+	username := selectExistingUser("list access keys for")
+	if username == "" {
+		return
+	}
+	listAccessKeys(username)
+}
+
+// selectExistingUser lists the users and asks for an existing username
+func selectExistingUser(action string) string {
 	ListUsersController()
 
 	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Enter Username to list access keys for: ")
+	fmt.Print("Enter Username to " + action + ": ")
 	input, _ := reader.ReadString('\n')
 	username := strings.TrimSpace(input)
 
-	cond := user_model.UserExistsOrNotModel(username)
-	if !cond {
+	if username == "" || !user_model.UserExistsOrNotModel(username) {
 		fmt.Println(utils.Yellow + utils.Bold + "User does not exist." + utils.Reset)
-		utils.Bk()
-		return
+		return ""
+	}
+	return username
+}
+
+// listAccessKeys renders a user's access keys and returns them
+func listAccessKeys(username string) []user_model.AccessKey {
+	utils.ShowProcessingAnimation("Listing access keys for user: " + username)
+	keys, err := user_model.FetchAccessKeys(username)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error listing access keys: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(keys) == 0 {
+		fmt.Println(utils.Yellow + utils.Bold + "No access keys found for this user." + utils.Reset)
+		return nil
 	}
-	user_model.ListAccessKeysForUserModel(username)
 
+	views.RenderTable(views.TableConfig{
+		Headers: user_model.AccessKeyTableHeaders,
+		Rows:    views.RowsOf(keys),
+	})
+	return keys
 }
@@ -0,0 +1,235 @@
+package user
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+)
+
+// accessKeyUsePollInterval is how often rotation checks whether the new key was used
+const accessKeyUsePollInterval = 15 * time.Second
+
+// ToggleAccessKeyController activates an inactive key or deactivates an active one
+func ToggleAccessKeyController() {
+	username := selectExistingUser("manage access keys for")
+	if username == "" {
+		return
+	}
+
+	key := selectAccessKey(username, "Enter access key number to activate/deactivate: ")
+	if key == nil {
+		return
+	}
+
+	activate := key.Status != "Active"
+	action := "Deactivate"
+	if activate {
+		action = "Activate"
+	}
+	if !confirmAction(action + " access key " + key.AccessKeyID + "?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation(action + " access key")
+	err := user_model.SetAccessKeyStatus(username, key.AccessKeyID, activate)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error updating access key: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Access key " + key.AccessKeyID + " " + strings.ToLower(action) + "d." + utils.Reset)
+}
+
+func DeleteAccessKeyController() {
+	username := selectExistingUser("delete an access key for")
+	if username == "" {
+		return
+	}
+
+	key := selectAccessKey(username, "Enter access key number to delete: ")
+	if key == nil {
+		return
+	}
+
+	if key.Status == "Active" {
+		fmt.Println(utils.Yellow + "This key is still active. Deactivating it first lets you check nothing breaks." + utils.Reset)
+	}
+	if !confirmAction("Delete access key " + key.AccessKeyID + "? This cannot be undone.") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deleting access key")
+	err := user_model.DeleteAccessKey(username, key.AccessKeyID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error deleting access key: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Access key " + key.AccessKeyID + " deleted." + utils.Reset)
+}
+
+// RotateAccessKeyController creates a replacement key, optionally emails it, waits until
+// the new key is in use or the operator confirms, then deactivates and deletes the old key
+func RotateAccessKeyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	username := selectExistingUser("rotate an access key for")
+	if username == "" {
+		return
+	}
+
+	oldKey := selectAccessKey(username, "Enter number of the access key to rotate: ")
+	if oldKey == nil {
+		return
+	}
+
+	// Step 1: create the new key
+	utils.ShowProcessingAnimation("Creating new access key")
+	newKey, err := user_model.StartAccessKeyRotation(username, oldKey.AccessKeyID)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error creating new access key: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Bold + utils.Green + "\nNew Access Key Created Successfully!" + utils.Reset)
+	fmt.Println("Access Key ID:      ", newKey.AccessKeyID)
+	fmt.Println("Secret Access Key:  ", newKey.SecretAccessKey)
+	fmt.Println(utils.Yellow + "The secret cannot be retrieved again. Store it before continuing." + utils.Reset)
+
+	// Step 2: optionally email it
+	fmt.Print("\nEmail the new key to the user? Enter address (or press Enter to skip): ")
+	input, _ := reader.ReadString('\n')
+	if email := strings.TrimSpace(input); email != "" {
+		sendAccessKeyEmail(username, email, newKey)
+	}
+
+	// Step 3: wait until the new key is deployed
+	fmt.Print("\nWait for the new key to be used before retiring the old one? (y/n): ")
+	input, _ = reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(input)) == "y" {
+		if !waitForAccessKeyUse(reader, newKey.AccessKeyID) {
+			return
+		}
+	} else if !confirmAction("Has the new key been deployed everywhere the old key " + oldKey.AccessKeyID + " was used?") {
+		fmt.Println(utils.Yellow + "The old key is still active. Finish later with the deactivate and delete options." + utils.Reset)
+		return
+	}
+
+	// Step 4: deactivate, then delete the old key
+	fmt.Print("Delete the old key after deactivating it? (y/n): ")
+	input, _ = reader.ReadString('\n')
+	keepOld := strings.ToLower(strings.TrimSpace(input)) != "y"
+
+	utils.ShowProcessingAnimation("Retiring old access key")
+	steps := user_model.FinishAccessKeyRotation(username, oldKey.AccessKeyID, keepOld)
+	utils.StopAnimation()
+
+	printStepResults(steps)
+	if len(user_model.FailedSteps(steps)) == 0 {
+		fmt.Println(utils.Green + utils.Bold + "Access key rotation for '" + username + "' completed." + utils.Reset)
+	}
+}
+
+// waitForAccessKeyUse polls the new key's last-used date for a number of minutes chosen
+// by the operator. It reports whether rotation should continue.
+func waitForAccessKeyUse(reader *bufio.Reader, accessKeyID string) bool {
+	fmt.Print("Minutes to wait (default 10): ")
+	input, _ := reader.ReadString('\n')
+	minutes, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || minutes <= 0 {
+		minutes = 10
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(minutes)*time.Minute)
+	defer cancel()
+
+	utils.ShowProcessingAnimation("Waiting for " + accessKeyID + " to be used")
+	err = user_model.WaitForAccessKeyUse(ctx, accessKeyID, accessKeyUsePollInterval)
+	utils.StopAnimation()
+
+	if err == nil {
+		fmt.Println(utils.Green + "New key " + accessKeyID + " is in use." + utils.Reset)
+		return true
+	}
+
+	fmt.Println(utils.Yellow + err.Error() + utils.Reset)
+	if !confirmAction("Retire the old key anyway?") {
+		fmt.Println(utils.Yellow + "The old key is still active. Finish later with the deactivate and delete options." + utils.Reset)
+		return false
+	}
+	return true
+}
+
+// sendAccessKeyEmail emails a new access key using the saved email configuration
+func sendAccessKeyEmail(username, email string, key *user_model.NewAccessKey) {
+	emailConfig, err := service.LoadEmailConfig()
+	if err != nil {
+		fmt.Println(utils.Red + "Email not sent: " + err.Error() + utils.Reset)
+		return
+	}
+
+	utils.ShowProcessingAnimation("Sending email")
+	err = service.SendAccessKeyEmail(emailConfig, username, email, key.AccessKeyID, key.SecretAccessKey)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Email not sent: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + "Access key emailed to " + email + "." + utils.Reset)
+}
+
+// selectAccessKey lists a user's keys and asks for one by number
+func selectAccessKey(username, prompt string) *user_model.AccessKey {
+	keys := listAccessKeys(username)
+	if keys == nil {
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+
+	index, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || index < 1 || index > len(keys) {
+		fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+		return nil
+	}
+	return &keys[index-1]
+}
+
+// confirmAction asks a yes/no question and reports whether the answer was yes
+func confirmAction(question string) bool {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(question + " (y/n): ")
+	input, _ := reader.ReadString('\n')
+
+	if strings.ToLower(strings.TrimSpace(input)) != "y" {
+		fmt.Println(utils.Yellow + "Operation cancelled." + utils.Reset)
+		return false
+	}
+	return true
+}
+
+// printStepResults prints the outcome of every step of a multi-step operation
+func printStepResults(steps []user_model.UserStepResult) {
+	for _, step := range steps {
+		if step.Success {
+			fmt.Println(utils.Green + "  ✓ " + step.Action + ": " + step.Resource + utils.Reset)
+		} else {
+			fmt.Println(utils.Red + "  ✗ " + step.Action + ": " + step.Resource + " (" + step.Error + ")" + utils.Reset)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// AccessKeyReportController shows every access key in the account with its age and
+// last use, and offers to export the report as CSV or JSON
+func AccessKeyReportController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	opts := user_model.AccessKeyReportOptions{
+		MaxAgeDays:    readDays(reader, fmt.Sprintf("Flag keys older than how many days? (default %d): ", user_model.DefaultMaxAccessKeyAgeDays)),
+		MaxUnusedDays: readDays(reader, "Flag keys unused for how many days? (Enter to skip): "),
+	}
+
+	fmt.Print("Show only flagged keys? (y/n): ")
+	input, _ := reader.ReadString('\n')
+	flaggedOnly := strings.ToLower(strings.TrimSpace(input)) == "y"
+
+	utils.ShowProcessingAnimation("Building access key report")
+	rows, err := user_model.GenerateAccessKeyReport(opts)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to build report: " + err.Error() + utils.Reset)
+		return
+	}
+
+	flagged := user_model.FilterFlaggedAccessKeys(rows)
+	if flaggedOnly {
+		rows = flagged
+	}
+	if len(rows) == 0 {
+		fmt.Println(utils.Green + "No access keys to report." + utils.Reset)
+		return
+	}
+
+	config := views.TableConfig{
+		Headers: user_model.AccessKeyReportTableHeaders,
+		Rows:    views.RowsOf(rows),
+	}
+	views.RenderTable(config)
+	fmt.Printf(utils.Bold+"%d key(s), %d flagged."+utils.Reset+"\n", len(rows), len(flagged))
+
+	exportReport(reader, "access_key_report", rows, config)
+}
+
+// readDays reads an optional positive number of days, returning 0 when skipped or invalid
+func readDays(reader *bufio.Reader, prompt string) int {
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+
+	days, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || days <= 0 {
+		return 0
+	}
+	return days
+}
+
+// exportReport asks for an export format and writes the report to a dated file
+func exportReport(reader *bufio.Reader, name string, data interface{}, config views.TableConfig) {
+	fmt.Print("Export report? (csv/json, Enter to skip): ")
+	input, _ := reader.ReadString('\n')
+	format := strings.ToLower(strings.TrimSpace(input))
+	if format == "" {
+		return
+	}
+	if format != views.OutputCSV && format != views.OutputJSON {
+		fmt.Println(utils.Red + "Unsupported format '" + format + "'." + utils.Reset)
+		return
+	}
+
+	defaultPath := name + "_" + time.Now().Format("2006-01-02") + "." + format
+	fmt.Print("File path (default " + defaultPath + "): ")
+	input, _ = reader.ReadString('\n')
+	path := valueOrDefault(strings.TrimSpace(input), defaultPath)
+
+	file, err := os.Create(path)
+	if err != nil {
+		fmt.Println(utils.Red + "Error creating file: " + err.Error() + utils.Reset)
+		return
+	}
+	defer file.Close()
+
+	if err := views.RenderOutput(file, format, data, config); err != nil {
+		fmt.Println(utils.Red + "Error writing report: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + "Report saved to " + path + utils.Reset)
+}
+
+// valueOrDefault returns value, or fallback when value is empty
+func valueOrDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
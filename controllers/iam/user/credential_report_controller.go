@@ -0,0 +1,91 @@
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// CredentialReportController generates the IAM credential report and shows it as a table
+// that can be re-sorted, narrowed to console users without MFA and exported
+func CredentialReportController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	utils.ShowProcessingAnimation("Generating credential report")
+	rows, generated, err := user_model.FetchCredentialReport()
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Failed to get credential report: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(rows) == 0 {
+		fmt.Println(utils.Yellow + "The credential report is empty." + utils.Reset)
+		return
+	}
+
+	noMFA := user_model.FilterConsoleWithoutMFA(rows)
+	shown := rows
+	onlyNoMFA := false
+	sortKey := user_model.CredentialSortUser
+
+	for {
+		user_model.SortCredentialReport(shown, sortKey)
+		config := views.TableConfig{
+			Headers: user_model.CredentialReportTableHeaders,
+			Rows:    views.RowsOf(shown),
+		}
+		views.RenderTable(config)
+
+		fmt.Printf(utils.Bold+"Generated %s. %d user(s) shown, sorted by %s."+utils.Reset+"\n",
+			generated.Local().Format("2006-01-02 15:04"), len(shown), sortKey)
+		if len(noMFA) > 0 {
+			fmt.Printf(utils.Red+"%d user(s) have console access without MFA."+utils.Reset+"\n", len(noMFA))
+		} else {
+			fmt.Println(utils.Green + "Every console user has MFA enabled." + utils.Reset)
+		}
+
+		fmt.Println()
+		for i, key := range user_model.CredentialSortKeys {
+			fmt.Printf("  %d) Sort by %s\n", i+1, key)
+		}
+		if !onlyNoMFA {
+			fmt.Println("  f) Show only console users without MFA")
+		} else {
+			fmt.Println("  f) Show all users")
+		}
+		fmt.Println("  e) Export report")
+		fmt.Println("  q) Done")
+		fmt.Print("Choose an option: ")
+
+		input, _ := reader.ReadString('\n')
+		choice := strings.ToLower(strings.TrimSpace(input))
+
+		switch choice {
+		case "f":
+			onlyNoMFA = !onlyNoMFA
+			shown = rows
+			if onlyNoMFA {
+				shown = noMFA
+			}
+		case "e":
+			exportReport(reader, "credential_report", shown, config)
+			return
+		case "q", "":
+			return
+		default:
+			index, err := strconv.Atoi(choice)
+			if err != nil || index < 1 || index > len(user_model.CredentialSortKeys) {
+				fmt.Println(utils.Red + "Invalid option." + utils.Reset)
+				continue
+			}
+			sortKey = user_model.CredentialSortKeys[index-1]
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/hygiene"
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// HygieneReportController lists inactive users, empty groups, unattached customer policies
+// and optionally never-used services, and removes confirmed findings
+func HygieneReportController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	days := readDays(reader, fmt.Sprintf("Flag users inactive for how many days? (default %d): ", hygiene.DefaultInactiveDays))
+	if days == 0 {
+		days = hygiene.DefaultInactiveDays
+	}
+
+	fmt.Print("Also find services granted but never accessed? This runs a job per user and role and can take minutes (y/n): ")
+	input, _ := reader.ReadString('\n')
+	includeServices := strings.ToLower(strings.TrimSpace(input)) == "y"
+
+	utils.ShowProcessingAnimation("Scanning IAM")
+	report := hygiene.Generate(hygiene.Options{InactiveDays: days, IncludeServices: includeServices})
+	utils.StopAnimation()
+
+	for _, failure := range report.Errors {
+		fmt.Println(utils.Red + "Check failed: " + failure + utils.Reset)
+	}
+	if len(report.Findings) == 0 {
+		fmt.Println(utils.Green + "No findings. IAM looks tidy." + utils.Reset)
+		return
+	}
+
+	config := views.TableConfig{
+		Headers: hygiene.FindingTableHeaders,
+		Rows:    views.RowsOf(report.Findings),
+	}
+	views.RenderTable(config)
+
+	counts := report.Count()
+	fmt.Printf(utils.Bold+"%d inactive user(s), %d empty group(s), %d unattached policy(ies), %d unused service grant(s)."+utils.Reset+"\n",
+		counts[hygiene.CategoryInactiveUser], counts[hygiene.CategoryEmptyGroup],
+		counts[hygiene.CategoryUnattachedPolicy], counts[hygiene.CategoryUnusedService])
+
+	fmt.Println()
+	fmt.Println("  a) Apply: delete selected users, groups and policies")
+	fmt.Println("  e) Export report")
+	fmt.Println("  q) Done")
+	fmt.Print("Choose an option: ")
+	input, _ = reader.ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "a":
+		applyHygieneFindings(reader, report.Findings, report.InactiveDays)
+	case "e":
+		exportReport(reader, "iam_hygiene_report", report.Findings, config)
+	}
+}
+
+// applyHygieneFindings asks which findings to fix, confirms them and removes the targets that
+// still qualify under the report's inactivity threshold
+func applyHygieneFindings(reader *bufio.Reader, findings []hygiene.Finding, inactiveDays int) {
+	fmt.Print("Finding numbers to apply, comma-separated, or 'all': ")
+	input, _ := reader.ReadString('\n')
+	input = strings.ToLower(strings.TrimSpace(input))
+
+	var selected []hygiene.Finding
+	if input == "all" {
+		for _, f := range findings {
+			if f.Removable {
+				selected = append(selected, f)
+			}
+		}
+	} else {
+		for _, field := range strings.Split(input, ",") {
+			index, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || index < 1 || index > len(findings) {
+				fmt.Println(utils.Red + "Invalid finding number '" + strings.TrimSpace(field) + "'." + utils.Reset)
+				return
+			}
+			f := findings[index-1]
+			if !f.Removable {
+				fmt.Println(utils.Yellow + "Skipping " + f.Target + ": " + f.Category + " findings must be fixed by editing the policy." + utils.Reset)
+				continue
+			}
+			selected = append(selected, f)
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Println(utils.Yellow + "Nothing to apply." + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Yellow + utils.Bold + "The following will be deleted:" + utils.Reset)
+	for _, f := range selected {
+		fmt.Println(utils.Yellow + "  - " + f.Category + ": " + f.Target + utils.Reset)
+	}
+	if !confirmAction(fmt.Sprintf("Delete these %d item(s)? This cannot be undone.", len(selected))) {
+		return
+	}
+
+	failed := 0
+	for _, f := range selected {
+		utils.ShowProcessingAnimation("Removing " + f.Target)
+		steps := hygiene.Apply(f, inactiveDays)
+		utils.StopAnimation()
+
+		fmt.Println(utils.Bold + f.Category + ": " + f.Target + utils.Reset)
+		printStepResults(steps)
+		if len(user_model.FailedSteps(steps)) > 0 {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf(utils.Red+"%d of %d item(s) were not fully removed."+utils.Reset+"\n", failed, len(selected))
+		return
+	}
+	fmt.Printf(utils.Green+utils.Bold+"Removed %d item(s)."+utils.Reset+"\n", len(selected))
+}
@@ -0,0 +1,74 @@
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// ImportUsersController onboards users from a CSV or JSON file: each user is created with
+// its tags, password, groups and policies, and generated credentials are emailed
+func ImportUsersController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Println(utils.Cyan + "CSV columns: " + strings.Join(user_model.UserImportColumns, ", ") + utils.Reset)
+	fmt.Println(utils.Cyan + "Separate several groups, policies or tags (key=value) with ';'." + utils.Reset)
+	fmt.Print("Path to the CSV or JSON file: ")
+	input, _ := reader.ReadString('\n')
+	path := strings.TrimSpace(input)
+	if path == "" {
+		fmt.Println(utils.Red + "Path cannot be empty." + utils.Reset)
+		return
+	}
+
+	rows, err := user_model.LoadUserImportFile(path)
+	if err == nil {
+		err = user_model.ValidateUserImport(rows)
+	}
+	if err != nil {
+		fmt.Println(utils.Red + "Error reading import file: " + err.Error() + utils.Reset)
+		return
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: user_model.UserImportRowTableHeaders,
+		Rows:    views.RowsOf(rows),
+	})
+	if !confirmAction(fmt.Sprintf("Create these %d user(s)?", len(rows))) {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Importing users")
+	results, err := user_model.ImportUsers(rows)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Import cancelled, nothing was created: " + err.Error() + utils.Reset)
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		fmt.Println(utils.Bold + result.Username + utils.Reset)
+		printStepResults(result.Steps)
+		if !result.Success {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf(utils.Red+"%d of %d user(s) were not fully onboarded."+utils.Reset+"\n", failed, len(results))
+	} else {
+		fmt.Printf(utils.Green+utils.Bold+"Imported %d user(s)."+utils.Reset+"\n", len(results))
+	}
+
+	exportReport(reader, "iam_user_import", results, views.TableConfig{
+		Headers: user_model.UserImportResultTableHeaders,
+		Rows:    views.RowsOf(results),
+	})
+}
@@ -1,16 +1,27 @@
 package user
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/DragonEmperor9480/aws_cli_manager/controllers/accounts"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/tag"
 	iam "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
-	userview "github.com/DragonEmperor9480/aws_cli_manager/views/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 )
 
 func ListUsersController() {
+	listUsers(nil)
+}
+
+// listUsers renders the users of the current account with their tags, only those matching
+// filter when it is set
+func listUsers(filter *tag.Filter) {
 	utils.ShowProcessingAnimation("Loading IAM Users")
-	data, err := iam.FetchIAMUsers()
+	users, warnings, err := iam.FetchIAMUsersWithTags(filter)
 	utils.StopAnimation()
 
 	if err != nil {
@@ -18,5 +29,60 @@ func ListUsersController() {
 		return
 	}
 
-	userview.RenderIAMUsersTable(data)
+	views.RenderTable(views.TableConfig{
+		Headers: iam.IAMUserTableHeaders,
+		Rows:    views.RowsOf(users),
+	})
+	printWarnings(warnings)
+}
+
+// ListUsersMenuController lists users, across the configured accounts and filtered by tag
+// if requested
+func ListUsersMenuController() {
+	selected := accounts.SelectAccounts()
+	filter, ok := readTagFilter()
+	if !ok {
+		return
+	}
+	if selected == nil {
+		listUsers(filter)
+		return
+	}
+
+	utils.ShowProcessingAnimation(fmt.Sprintf("Loading IAM Users from %d accounts", len(selected)))
+	users, warnings, failures := iam.FetchIAMUsersAcrossAccounts(selected, filter)
+	utils.StopAnimation()
+
+	views.RenderTable(views.TableConfig{
+		Headers: iam.AccountIAMUserTableHeaders,
+		Rows:    views.RowsOf(users),
+	})
+	printWarnings(warnings)
+	accounts.PrintAccountErrors(failures)
+}
+
+// readTagFilter asks for an optional tag filter, returning nil when skipped and false when
+// the answer is not a valid filter
+func readTagFilter() (*tag.Filter, bool) {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("Filter by tag (e.g. team=payments, or just team; Enter for all users): ")
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, true
+	}
+
+	filter, err := tag.ParseFilter(input)
+	if err != nil {
+		fmt.Println(utils.Red + err.Error() + utils.Reset)
+		return nil, false
+	}
+	return &filter, true
+}
+
+// printWarnings lists problems that left a listing incomplete without failing it
+func printWarnings(warnings []string) {
+	for _, warning := range warnings {
+		fmt.Println(utils.Yellow + "Warning: " + warning + utils.Reset)
+	}
 }
@@ -0,0 +1,172 @@
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+	userview "github.com/DragonEmperor9480/aws_cli_manager/views/iam/user"
+)
+
+// EnrollVirtualMFAController creates a virtual MFA device for a user, shows its QR code
+// and enables it with two consecutive codes from the authenticator app
+func EnrollVirtualMFAController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	username := selectExistingUser("enroll a virtual MFA device for")
+	if username == "" {
+		return
+	}
+
+	fmt.Print("Device name (default " + username + "): ")
+	input, _ := reader.ReadString('\n')
+	deviceName := valueOrDefault(strings.TrimSpace(input), username)
+
+	utils.ShowProcessingAnimation("Creating virtual MFA device")
+	enrollment, err := user_model.CreateVirtualMFADevice(username, deviceName)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error creating virtual MFA device: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Bold + utils.Green + "\nVirtual MFA device created: " + enrollment.SerialNumber + utils.Reset)
+	fmt.Println("Scan this QR code with an authenticator app:")
+	if err := userview.PrintQRCode(enrollment.OTPAuthURI); err != nil {
+		fmt.Println(utils.Red + "Could not render QR code: " + err.Error() + utils.Reset)
+	}
+	fmt.Println("Or enter this secret manually: " + utils.Bold + enrollment.Base32Seed + utils.Reset)
+	fmt.Println(utils.Yellow + "The secret cannot be retrieved again once this screen is left." + utils.Reset)
+
+	fmt.Print("\nFirst code from the app: ")
+	input, _ = reader.ReadString('\n')
+	code1 := strings.TrimSpace(input)
+	fmt.Print("Next code from the app (wait for it to change): ")
+	input, _ = reader.ReadString('\n')
+	code2 := strings.TrimSpace(input)
+
+	utils.ShowProcessingAnimation("Enabling MFA device")
+	err = user_model.EnableMFADevice(username, enrollment.SerialNumber, code1, code2)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error enabling MFA device: " + err.Error() + utils.Reset)
+		// Without an enabled device the seed is lost, so clean up the unassigned device
+		if delErr := user_model.DeleteVirtualMFADevice(enrollment.SerialNumber); delErr != nil {
+			fmt.Println(utils.Red + "Also failed to remove the unused device: " + delErr.Error() + utils.Reset)
+		} else {
+			fmt.Println(utils.Yellow + "The unused device was removed. Start the enrollment again to retry." + utils.Reset)
+		}
+		return
+	}
+
+	fmt.Println(utils.Green + utils.Bold + "MFA enabled for '" + username + "'." + utils.Reset)
+}
+
+// ListMFADevicesController lists the MFA devices assigned to a user
+func ListMFADevicesController() {
+	username := selectExistingUser("list MFA devices for")
+	if username == "" {
+		return
+	}
+	listMFADevices(username)
+}
+
+// DeactivateMFADeviceController removes an MFA device from a user, keeping the device
+func DeactivateMFADeviceController() {
+	username := selectExistingUser("deactivate an MFA device for")
+	if username == "" {
+		return
+	}
+
+	device := selectMFADevice(username, "Enter MFA device number to deactivate: ")
+	if device == nil {
+		return
+	}
+	if !confirmAction("Deactivate MFA device " + device.SerialNumber + "?") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deactivating MFA device")
+	err := user_model.DeactivateMFADevice(username, device.SerialNumber)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error deactivating MFA device: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "MFA device " + device.SerialNumber + " deactivated." + utils.Reset)
+}
+
+// DeleteMFADeviceController deactivates and deletes a user's virtual MFA device
+func DeleteMFADeviceController() {
+	username := selectExistingUser("delete an MFA device for")
+	if username == "" {
+		return
+	}
+
+	device := selectMFADevice(username, "Enter MFA device number to delete: ")
+	if device == nil {
+		return
+	}
+	if !device.Virtual {
+		fmt.Println(utils.Yellow + "Hardware MFA devices can only be deactivated." + utils.Reset)
+		return
+	}
+	if !confirmAction("Delete MFA device " + device.SerialNumber + "? The user will need to enroll again.") {
+		return
+	}
+
+	utils.ShowProcessingAnimation("Deleting MFA device")
+	steps := user_model.DeleteMFADevice(username, device.SerialNumber)
+	utils.StopAnimation()
+
+	printStepResults(steps)
+}
+
+// listMFADevices renders a user's MFA devices and returns them
+func listMFADevices(username string) []user_model.MFADevice {
+	utils.ShowProcessingAnimation("Listing MFA devices for user: " + username)
+	devices, err := user_model.FetchMFADevices(username)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error listing MFA devices: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(devices) == 0 {
+		fmt.Println(utils.Yellow + utils.Bold + "No MFA devices found for this user." + utils.Reset)
+		return nil
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: user_model.MFADeviceTableHeaders,
+		Rows:    views.RowsOf(devices),
+	})
+	return devices
+}
+
+// selectMFADevice lists a user's MFA devices and asks for one by number
+func selectMFADevice(username, prompt string) *user_model.MFADevice {
+	devices := listMFADevices(username)
+	if devices == nil {
+		return nil
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print(prompt)
+	input, _ := reader.ReadString('\n')
+
+	index, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil || index < 1 || index > len(devices) {
+		fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+		return nil
+	}
+	return &devices[index-1]
+}
@@ -0,0 +1,158 @@
+package user
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+)
+
+// PasswordPolicyController shows the account password policy and lets the operator edit it,
+// reset it to the AWS default or test a password against it
+func PasswordPolicyController() {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		utils.ShowProcessingAnimation("Loading password policy")
+		policy, err := user_model.FetchPasswordPolicy()
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Red + "Error reading password policy: " + err.Error() + utils.Reset)
+			return
+		}
+		showPasswordPolicy(policy)
+
+		fmt.Println()
+		fmt.Println("  e) Edit policy")
+		fmt.Println("  r) Reset to AWS default")
+		fmt.Println("  t) Test a password")
+		fmt.Println("  q) Done")
+		fmt.Print("Choose an option: ")
+		input, _ := reader.ReadString('\n')
+
+		switch strings.ToLower(strings.TrimSpace(input)) {
+		case "e":
+			editPasswordPolicy(reader, *policy)
+		case "r":
+			if !confirmAction("Remove the custom password policy?") {
+				continue
+			}
+			if err := user_model.ResetPasswordPolicy(); err != nil {
+				fmt.Println(utils.Red + "Error resetting password policy: " + err.Error() + utils.Reset)
+				continue
+			}
+			fmt.Println(utils.Green + "Password policy reset to the AWS default." + utils.Reset)
+		case "t":
+			fmt.Print("Password to test: ")
+			input, _ = reader.ReadString('\n')
+			violations := policy.CheckPassword(strings.TrimSpace(input))
+			if len(violations) == 0 {
+				fmt.Println(utils.Green + "✓ Password meets the policy." + utils.Reset)
+				continue
+			}
+			for _, violation := range violations {
+				fmt.Println(utils.Red + "  - " + violation + utils.Reset)
+			}
+		case "q", "":
+			return
+		default:
+			fmt.Println(utils.Red + "Invalid option." + utils.Reset)
+		}
+	}
+}
+
+// showPasswordPolicy renders the policy settings as a table
+func showPasswordPolicy(policy *user_model.PasswordPolicy) {
+	if policy.Default {
+		fmt.Println(utils.Yellow + "No custom password policy is set; the AWS default applies." + utils.Reset)
+	}
+
+	maxAge := "Never expires"
+	if policy.MaxPasswordAge > 0 {
+		maxAge = fmt.Sprintf("%d days", policy.MaxPasswordAge)
+	}
+	reuse := "Off"
+	if policy.PasswordReusePrevention > 0 {
+		reuse = fmt.Sprintf("Last %d passwords", policy.PasswordReusePrevention)
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: []string{"Setting", "Value"},
+		Rows: [][]string{
+			{"Minimum length", strconv.Itoa(policy.MinimumPasswordLength)},
+			{"Require uppercase", yesNo(policy.RequireUppercaseCharacters)},
+			{"Require lowercase", yesNo(policy.RequireLowercaseCharacters)},
+			{"Require numbers", yesNo(policy.RequireNumbers)},
+			{"Require symbols", yesNo(policy.RequireSymbols)},
+			{"Users can change password", yesNo(policy.AllowUsersToChangePassword)},
+			{"Maximum age", maxAge},
+			{"Prevent reuse", reuse},
+			{"Admin reset on expiry", yesNo(policy.HardExpiry)},
+		},
+	})
+}
+
+// editPasswordPolicy prompts for every setting, keeping the current value on Enter
+func editPasswordPolicy(reader *bufio.Reader, policy user_model.PasswordPolicy) {
+	fmt.Println(utils.Cyan + "Press Enter to keep the current value." + utils.Reset)
+
+	policy.MinimumPasswordLength = readIntSetting(reader, "Minimum length", policy.MinimumPasswordLength)
+	policy.RequireUppercaseCharacters = readBoolSetting(reader, "Require uppercase", policy.RequireUppercaseCharacters)
+	policy.RequireLowercaseCharacters = readBoolSetting(reader, "Require lowercase", policy.RequireLowercaseCharacters)
+	policy.RequireNumbers = readBoolSetting(reader, "Require numbers", policy.RequireNumbers)
+	policy.RequireSymbols = readBoolSetting(reader, "Require symbols", policy.RequireSymbols)
+	policy.AllowUsersToChangePassword = readBoolSetting(reader, "Users can change password", policy.AllowUsersToChangePassword)
+	policy.MaxPasswordAge = readIntSetting(reader, "Maximum age in days (0 never expires)", policy.MaxPasswordAge)
+	policy.PasswordReusePrevention = readIntSetting(reader, "Passwords remembered (0 off)", policy.PasswordReusePrevention)
+	policy.HardExpiry = readBoolSetting(reader, "Admin reset on expiry", policy.HardExpiry)
+
+	if err := policy.Validate(); err != nil {
+		fmt.Println(utils.Red + "Invalid policy: " + err.Error() + utils.Reset)
+		return
+	}
+
+	utils.ShowProcessingAnimation("Updating password policy")
+	err := user_model.UpdatePasswordPolicy(policy)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error updating password policy: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + utils.Bold + "Password policy updated." + utils.Reset)
+}
+
+func readIntSetting(reader *bufio.Reader, label string, current int) int {
+	fmt.Printf("%s [%d]: ", label, current)
+	input, _ := reader.ReadString('\n')
+	value, err := strconv.Atoi(strings.TrimSpace(input))
+	if err != nil {
+		return current
+	}
+	return value
+}
+
+func readBoolSetting(reader *bufio.Reader, label string, current bool) bool {
+	fmt.Printf("%s (y/n) [%s]: ", label, strings.ToLower(yesNo(current)))
+	input, _ := reader.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y":
+		return true
+	case "n":
+		return false
+	}
+	return current
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
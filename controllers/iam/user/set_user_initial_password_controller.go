@@ -5,49 +5,12 @@ import (
 	"fmt"
 	"os"
 	"strings"
-	"unicode"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/db_service"
 	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 )
 
-// validatePassword checks if password meets requirements
-func validatePassword(password string) (bool, string) {
-	if len(password) < 8 {
-		return false, "Password must be at least 8 characters long"
-	}
-
-	var (
-		hasUpper  bool
-		hasLower  bool
-		hasNumber bool
-	)
-
-	for _, char := range password {
-		switch {
-		case unicode.IsUpper(char):
-			hasUpper = true
-		case unicode.IsLower(char):
-			hasLower = true
-		case unicode.IsNumber(char):
-			hasNumber = true
-		}
-	}
-
-	if !hasUpper {
-		return false, "Password must contain at least one uppercase letter"
-	}
-	if !hasLower {
-		return false, "Password must contain at least one lowercase letter"
-	}
-	if !hasNumber {
-		return false, "Password must contain at least one number"
-	}
-
-	return true, ""
-}
-
 func SetInitialUserPassword() {
 	ListUsersController()
 
@@ -66,23 +29,8 @@ func SetInitialUserPassword() {
 		return
 	}
 
-	fmt.Print("Enter Password for the user: ")
-	input, _ = reader.ReadString('\n')
-	password := strings.TrimSpace(input)
-
+	password, generated := readInitialPassword(reader)
 	if password == "" {
-		fmt.Println(utils.Bold + utils.Red + "Please enter a valid password." + utils.Reset)
-		return
-	}
-
-	// Validate password
-	if valid, errMsg := validatePassword(password); !valid {
-		fmt.Println(utils.Bold + utils.Red + "Error: " + errMsg + utils.Reset)
-		fmt.Println(utils.Yellow + "Password requirements:\n" +
-			"- At least 8 characters long\n" +
-			"- At least one uppercase letter\n" +
-			"- At least one lowercase letter\n" +
-			"- At least one number" + utils.Reset)
 		return
 	}
 
@@ -108,7 +56,7 @@ func SetInitialUserPassword() {
 		return
 	case user_model.PasswordPolicyViolation:
 		fmt.Println(utils.Red + utils.Bold + "Error: Password does not meet AWS policy requirements!" + utils.Reset)
-		fmt.Println(utils.Yellow + "Your AWS account may have additional password policy requirements." + utils.Reset)
+		fmt.Println(utils.Yellow + "The password may match one the user had before." + utils.Reset)
 		return
 	case user_model.PasswordAlreadyExists:
 		fmt.Println(utils.Red + utils.Bold + "Error: Password for user '" + username + "' already exists!" + utils.Reset)
@@ -121,46 +69,14 @@ func SetInitialUserPassword() {
 		fmt.Println(utils.Green + utils.Bold + "✓ User password created successfully!" + utils.Reset)
 	}
 
-	// Save credentials
-	fmt.Print(utils.Yellow + utils.Bold + "Would you like to save " + username + "'s credentials? (y/n): " + utils.Reset)
-	saveChoice, _ := reader.ReadString('\n')
-	saveChoice = strings.ToLower(strings.TrimSpace(saveChoice))
-
-	if saveChoice == "y" {
-		err := db_service.SaveUserCredential(username, password)
-		if err != nil {
-			fmt.Println(utils.Red + utils.Bold + "Error saving credentials: " + err.Error() + utils.Reset)
-		} else {
-			fmt.Println(utils.Green + utils.Bold + "✓ Credentials saved securely to database" + utils.Reset)
-		}
-	}
-
-	// Show credentials
-	fmt.Println(utils.Cyan + utils.Bold + "\nGenerated Credentials:" + utils.Reset)
-	fmt.Println("Username: " + utils.Bold + username + utils.Reset)
-	fmt.Println("Password: " + utils.Bold + password + utils.Reset)
+	deliverInitialPassword(reader, username, password, generated)
 }
 
 func SetInitialUserPasswordDirect(username string) {
 	reader := bufio.NewReader(os.Stdin)
 
-	fmt.Print("Enter Password for the user: ")
-	input, _ := reader.ReadString('\n')
-	password := strings.TrimSpace(input)
-
+	password, generated := readInitialPassword(reader)
 	if password == "" {
-		fmt.Println(utils.Bold + utils.Red + "Failed to create password since it was left empty" + utils.Reset)
-		return
-	}
-
-	// Validate password
-	if valid, errMsg := validatePassword(password); !valid {
-		fmt.Println(utils.Bold + utils.Red + "Error: " + errMsg + utils.Reset)
-		fmt.Println(utils.Yellow + "Password requirements:\n" +
-			"- At least 8 characters long\n" +
-			"- At least one uppercase letter\n" +
-			"- At least one lowercase letter\n" +
-			"- At least one number" + utils.Reset)
 		return
 	}
 
@@ -186,7 +102,7 @@ func SetInitialUserPasswordDirect(username string) {
 		return
 	case user_model.PasswordPolicyViolation:
 		fmt.Println(utils.Red + utils.Bold + "Error: Password does not meet AWS policy requirements!" + utils.Reset)
-		fmt.Println(utils.Yellow + "Your AWS account may have additional password policy requirements." + utils.Reset)
+		fmt.Println(utils.Yellow + "The password may match one the user had before." + utils.Reset)
 		return
 	case user_model.PasswordAlreadyExists:
 		fmt.Println(utils.Red + utils.Bold + "Error: Password for user '" + username + "' already exists!" + utils.Reset)
@@ -199,17 +115,66 @@ func SetInitialUserPasswordDirect(username string) {
 		fmt.Println(utils.Green + utils.Bold + "User password created successfully!" + utils.Reset)
 	}
 
-	// Save credentials
-	fmt.Print(utils.Yellow + utils.Bold + "Would you like to save " + username + "'s credentials? (y/n): " + utils.Reset)
-	saveChoice, _ := reader.ReadString('\n')
-	saveChoice = strings.ToLower(strings.TrimSpace(saveChoice))
+	deliverInitialPassword(reader, username, password, generated)
+}
 
-	if saveChoice == "y" {
+// readInitialPassword asks for a password, generating a policy-compliant one when the
+// operator presses Enter. It returns an empty password when none could be used.
+func readInitialPassword(reader *bufio.Reader) (string, bool) {
+	fmt.Print("Enter Password for the user (press Enter to generate one): ")
+	input, _ := reader.ReadString('\n')
+	password := strings.TrimSpace(input)
+
+	if password == "" {
+		utils.ShowProcessingAnimation("Generating password")
+		password, err := user_model.GenerateCompliantPassword()
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Bold + utils.Red + "Error generating password: " + err.Error() + utils.Reset)
+			return "", false
+		}
+		fmt.Println(utils.Green + "Generated a password that meets the account password policy." + utils.Reset)
+		return password, true
+	}
+
+	// Validate password against the account policy before calling AWS
+	if !passwordMeetsPolicy(password) {
+		return "", false
+	}
+	return password, false
+}
+
+// deliverInitialPassword saves the new password, offers to email it and shows it.
+// Generated passwords are always saved, since they are not known anywhere else.
+func deliverInitialPassword(reader *bufio.Reader, username, password string, generated bool) {
+	save := generated
+	if !generated {
+		fmt.Print(utils.Yellow + utils.Bold + "Would you like to save " + username + "'s credentials? (y/n): " + utils.Reset)
+		saveChoice, _ := reader.ReadString('\n')
+		save = strings.ToLower(strings.TrimSpace(saveChoice)) == "y"
+	}
+
+	if save {
 		err := db_service.SaveUserCredential(username, password)
 		if err != nil {
 			fmt.Println(utils.Red + utils.Bold + "Error saving credentials: " + err.Error() + utils.Reset)
 		} else {
-			fmt.Println(utils.Green + utils.Bold + "Credentials saved securely to database" + utils.Reset)
+			fmt.Println(utils.Green + utils.Bold + "✓ Credentials saved securely to database" + utils.Reset)
+		}
+	}
+
+	fmt.Print("Email the credentials to the user? Enter address (or press Enter to skip): ")
+	input, _ := reader.ReadString('\n')
+	if email := strings.TrimSpace(input); email != "" {
+		utils.ShowProcessingAnimation("Sending email")
+		err := user_model.EmailUserCredentials(username, password, email)
+		utils.StopAnimation()
+
+		if err != nil {
+			fmt.Println(utils.Red + "Email not sent: " + err.Error() + utils.Reset)
+		} else {
+			fmt.Println(utils.Green + "Credentials emailed to " + email + "." + utils.Reset)
 		}
 	}
 
@@ -218,3 +183,34 @@ func SetInitialUserPasswordDirect(username string) {
 	fmt.Println("Username: " + utils.Bold + username + utils.Reset)
 	fmt.Println("Password: " + utils.Bold + password + utils.Reset)
 }
+
+// passwordMeetsPolicy checks a password against the account password policy before it is
+// sent to AWS and prints the rules it breaks. If the policy cannot be read, AWS decides.
+func passwordMeetsPolicy(password string) bool {
+	utils.ShowProcessingAnimation("Checking password policy")
+	policy, violations, err := user_model.CheckPasswordAgainstPolicy(password)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Yellow + "Could not read the password policy, AWS will validate the password: " + err.Error() + utils.Reset)
+		return true
+	}
+	if len(violations) == 0 {
+		return true
+	}
+
+	fmt.Println(utils.Bold + utils.Red + "Error: Password does not meet the account password policy:" + utils.Reset)
+	for _, violation := range violations {
+		fmt.Println(utils.Red + "  - " + violation + utils.Reset)
+	}
+	printPasswordRequirements(policy)
+	return false
+}
+
+// printPasswordRequirements prints the rules of the password policy
+func printPasswordRequirements(policy *user_model.PasswordPolicy) {
+	fmt.Println(utils.Yellow + "Password requirements:" + utils.Reset)
+	for _, requirement := range policy.Requirements() {
+		fmt.Println(utils.Yellow + "- " + requirement + utils.Reset)
+	}
+}
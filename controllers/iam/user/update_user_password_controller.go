@@ -33,6 +33,9 @@ func UpdateUserPasswordController() {
 		return
 	}
 
-	user_model.UpdateUserPasswordModel(username, password)
+	if !passwordMeetsPolicy(password) {
+		return
+	}
 
+	user_model.UpdateUserPasswordModel(username, password)
 }
@@ -7,6 +7,9 @@ import (
 	"strings"
 
 	group "github.com/DragonEmperor9480/aws_cli_manager/controllers/iam/group"
+	policy "github.com/DragonEmperor9480/aws_cli_manager/controllers/iam/policy"
+	role "github.com/DragonEmperor9480/aws_cli_manager/controllers/iam/role"
+	tag "github.com/DragonEmperor9480/aws_cli_manager/controllers/iam/tag"
 	user "github.com/DragonEmperor9480/aws_cli_manager/controllers/iam/user"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	iamview "github.com/DragonEmperor9480/aws_cli_manager/views/iam"
@@ -27,7 +30,7 @@ func IAM_mgr() {
 			user.CreateIAMUserController()
 			utils.Bk()
 		case "2":
-			user.ListUsersController()
+			user.ListUsersMenuController()
 			utils.Bk()
 		case "3":
 			group.AddUserToGroupController()
@@ -46,25 +49,78 @@ func IAM_mgr() {
 			utils.Bk()
 		case "8":
 			user.ListAccessKeysForUserController()
+			utils.Bk()
 		case "9":
-			group.CreateIAMGroupController()
+			user.ToggleAccessKeyController()
 			utils.Bk()
 		case "10":
-			group.ListGroupsController()
+			user.DeleteAccessKeyController()
 			utils.Bk()
 		case "11":
-			group.ListUsersInGroupController()
+			user.RotateAccessKeyController()
 			utils.Bk()
 		case "12":
-			group.ListUserGroupsController()
+			user.AccessKeyReportController()
 			utils.Bk()
 		case "13":
-			group.DeleteIamGroupController()
+			group.CreateIAMGroupController()
 			utils.Bk()
 		case "14":
-			group.RemoveUserFromGroupController()
+			group.ListGroupsController()
 			utils.Bk()
 		case "15":
+			group.ListUsersInGroupController()
+			utils.Bk()
+		case "16":
+			group.ListUserGroupsController()
+			utils.Bk()
+		case "17":
+			group.DeleteIamGroupController()
+			utils.Bk()
+		case "18":
+			group.RemoveUserFromGroupController()
+			utils.Bk()
+		case "19":
+			role.RolesMenuController()
+		case "20":
+			user.EnrollVirtualMFAController()
+			utils.Bk()
+		case "21":
+			user.ListMFADevicesController()
+			utils.Bk()
+		case "22":
+			user.DeactivateMFADeviceController()
+			utils.Bk()
+		case "23":
+			user.DeleteMFADeviceController()
+			utils.Bk()
+		case "24":
+			policy.PoliciesMenuController()
+		case "25":
+			policy.InlinePoliciesController()
+			utils.Bk()
+		case "26":
+			policy.SimulatePolicyController()
+			utils.Bk()
+		case "27":
+			policy.PermissionsBoundaryController()
+			utils.Bk()
+		case "28":
+			user.PasswordPolicyController()
+			utils.Bk()
+		case "29":
+			user.CredentialReportController()
+			utils.Bk()
+		case "30":
+			user.HygieneReportController()
+			utils.Bk()
+		case "31":
+			user.ImportUsersController()
+			utils.Bk()
+		case "32":
+			tag.TagsController()
+			utils.Bk()
+		case "33":
 			fmt.Println("Returning to Main Menu...")
 			utils.ClearScreen()
 			return
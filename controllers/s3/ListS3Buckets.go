@@ -2,17 +2,44 @@ package s3
 
 import (
 	"fmt"
-	"strings"
 
+	"github.com/DragonEmperor9480/aws_cli_manager/controllers/accounts"
 	s3model "github.com/DragonEmperor9480/aws_cli_manager/models/s3"
-	views "github.com/DragonEmperor9480/aws_cli_manager/views/s3"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 )
 
 func ListS3Buckets() {
-	data := s3model.ListS3BucketsModel()
-	if strings.TrimSpace(data) == "" {
+	buckets, err := s3model.ListS3BucketsModel()
+	if err != nil {
+		fmt.Println(utils.Red + "Error listing S3 buckets: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(buckets) == 0 {
 		fmt.Println("No S3 buckets found.")
 		return
 	}
-	views.RenderS3BucketsTable(data)
+	views.RenderTable(views.TableConfig{
+		Headers: s3model.S3BucketTableHeaders,
+		Rows:    views.RowsOf(buckets),
+	})
+}
+
+// ListS3BucketsController lists buckets, across the configured accounts if requested
+func ListS3BucketsController() {
+	selected := accounts.SelectAccounts()
+	if selected == nil {
+		ListS3Buckets()
+		return
+	}
+
+	utils.ShowProcessingAnimation(fmt.Sprintf("Listing S3 Buckets from %d accounts", len(selected)))
+	buckets, failures := s3model.FetchS3BucketsAcrossAccounts(selected)
+	utils.StopAnimation()
+
+	views.RenderTable(views.TableConfig{
+		Headers: s3model.AccountS3BucketTableHeaders,
+		Rows:    views.RowsOf(buckets),
+	})
+	accounts.PrintAccountErrors(failures)
 }
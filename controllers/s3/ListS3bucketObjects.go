@@ -7,7 +7,8 @@ import (
 	"strings"
 
 	s3model "github.com/DragonEmperor9480/aws_cli_manager/models/s3"
-	views "github.com/DragonEmperor9480/aws_cli_manager/views/s3"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
+	s3views "github.com/DragonEmperor9480/aws_cli_manager/views/s3"
 )
 
 func S3ListBucketObjectsController() {
@@ -22,13 +23,19 @@ func S3ListBucketObjectsController() {
 		return
 	}
 
-	output, err := s3model.S3ListBucketObjects(bucketName)
+	objects, err := s3model.S3ListBucketObjects(bucketName)
 	if err != nil {
-		views.PrintError(err.Error())
+		s3views.PrintError(err.Error())
 		return
 	}
 
-	// Parse output back to lines for view
-	lines := strings.Split(output, "\n")
-	views.PrintS3Objects(lines)
+	if len(objects) == 0 {
+		s3views.PrintWarning("No objects found in bucket '" + bucketName + "'.")
+		return
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: s3model.S3ObjectTableHeaders,
+		Rows:    views.RowsOf(objects),
+	})
 }
@@ -26,7 +26,7 @@ func S3_mgr() {
 			s3controller.CreateS3Bucket()
 			utils.Bk()
 		case "2":
-			s3controller.ListS3Buckets()
+			s3controller.ListS3BucketsController()
 			utils.Bk()
 		case "3":
 			s3controller.DeleteS3Bucket()
@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	user_model "github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
 	"github.com/DragonEmperor9480/aws_cli_manager/service"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/DragonEmperor9480/aws_cli_manager/views"
 )
 
 func Settings_mgr() {
@@ -21,6 +25,22 @@ func Settings_mgr() {
 		fmt.Println(utils.Bold + utils.Blue + "[1]" + utils.Reset + " View Current MFA Device")
 		fmt.Println(utils.Bold + utils.Blue + "[2]" + utils.Reset + " Update MFA Device")
 		fmt.Println("────────────────────────────────────")
+		fmt.Println(utils.Bold + utils.Green + "AWS Profile & Region:" + utils.Reset)
+		fmt.Println(utils.Bold + utils.Blue + "[3]" + utils.Reset + " View Current Profile and Region")
+		fmt.Println(utils.Bold + utils.Blue + "[4]" + utils.Reset + " Switch Profile")
+		fmt.Println(utils.Bold + utils.Blue + "[5]" + utils.Reset + " Switch Region")
+		fmt.Println("────────────────────────────────────")
+		fmt.Println(utils.Bold + utils.Green + "STS Session Credentials:" + utils.Reset)
+		fmt.Println(utils.Bold + utils.Blue + "[6]" + utils.Reset + " View Current Session")
+		fmt.Println(utils.Bold + utils.Blue + "[7]" + utils.Reset + " Start MFA Session")
+		fmt.Println(utils.Bold + utils.Blue + "[8]" + utils.Reset + " Assume Role")
+		fmt.Println(utils.Bold + utils.Blue + "[9]" + utils.Reset + " End Session")
+		fmt.Println("────────────────────────────────────")
+		fmt.Println(utils.Bold + utils.Green + "Multi-Account Listings:" + utils.Reset)
+		fmt.Println(utils.Bold + utils.Blue + "[10]" + utils.Reset + " View Accounts")
+		fmt.Println(utils.Bold + utils.Blue + "[11]" + utils.Reset + " Add Account")
+		fmt.Println(utils.Bold + utils.Blue + "[12]" + utils.Reset + " Remove Account")
+		fmt.Println("────────────────────────────────────")
 		fmt.Println(utils.Bold + utils.Red + "[0]" + utils.Reset + " Back to Main Menu")
 		fmt.Println("────────────────────────────────────")
 		fmt.Print("Select option: ")
@@ -33,6 +53,26 @@ func Settings_mgr() {
 			viewMFADevice()
 		case "2":
 			updateMFADevice(reader)
+		case "3":
+			viewAWSProfile()
+		case "4":
+			switchAWSProfile(reader)
+		case "5":
+			switchAWSRegion(reader)
+		case "6":
+			viewAWSSession()
+		case "7":
+			startMFASession(reader)
+		case "8":
+			assumeRole(reader)
+		case "9":
+			endAWSSession()
+		case "10":
+			viewAccounts()
+		case "11":
+			addAccount(reader)
+		case "12":
+			removeAccount(reader)
 		case "0":
 			return
 		default:
@@ -72,6 +112,13 @@ func updateMFADevice(reader *bufio.Reader) {
 		fmt.Printf("%sCurrent ARN:%s    %s\n\n", utils.Bold, utils.Reset, existingDevice.DeviceARN)
 	}
 
+	fmt.Print("Detect the MFA devices of the current AWS identity? (y/n): ")
+	answer, _ := reader.ReadString('\n')
+	if strings.ToLower(strings.TrimSpace(answer)) == "y" {
+		detectMFADevice(reader)
+		return
+	}
+
 	fmt.Print("Device Name (e.g., 'My Phone', 'YubiKey'): ")
 	deviceName, _ := reader.ReadString('\n')
 	deviceName = strings.TrimSpace(deviceName)
@@ -98,3 +145,338 @@ func updateMFADevice(reader *bufio.Reader) {
 
 	fmt.Println(utils.Green + "MFA device updated successfully!" + utils.Reset)
 }
+
+// detectMFADevice lists the MFA devices of the calling identity and saves the chosen one
+func detectMFADevice(reader *bufio.Reader) {
+	utils.ShowProcessingAnimation("Listing your MFA devices")
+	devices, err := user_model.FetchMFADevices("")
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error listing MFA devices: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(devices) == 0 {
+		fmt.Println(utils.Yellow + "No MFA devices are assigned to the current identity." + utils.Reset)
+		return
+	}
+
+	device := devices[0]
+	if len(devices) > 1 {
+		views.RenderTable(views.TableConfig{
+			Headers: user_model.MFADeviceTableHeaders,
+			Rows:    views.RowsOf(devices),
+		})
+		fmt.Print("Select device number: ")
+		input, _ := reader.ReadString('\n')
+		index, err := strconv.Atoi(strings.TrimSpace(input))
+		if err != nil || index < 1 || index > len(devices) {
+			fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+			return
+		}
+		device = devices[index-1]
+	}
+
+	if err := service.SaveMFADevice(device.Name(), device.SerialNumber); err != nil {
+		fmt.Println(utils.Red + "Error saving MFA device: " + err.Error() + utils.Reset)
+		return
+	}
+	fmt.Println(utils.Green + "MFA device set to " + device.SerialNumber + "." + utils.Reset)
+}
+
+func viewAWSProfile() {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Current AWS Profile:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+	fmt.Printf("%sProfile:%s %s\n", utils.Bold, utils.Reset, utils.GetCurrentProfile())
+	fmt.Printf("%sRegion:%s  %s\n", utils.Bold, utils.Reset, utils.GetCurrentRegion())
+}
+
+func switchAWSProfile(reader *bufio.Reader) {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Switch AWS Profile:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+
+	profiles, err := utils.ListAWSProfiles()
+	if err != nil {
+		fmt.Println(utils.Red + "Error reading AWS profiles: " + err.Error() + utils.Reset)
+		return
+	}
+	if len(profiles) == 0 {
+		fmt.Println(utils.Yellow + "No profiles found in ~/.aws/config or ~/.aws/credentials." + utils.Reset)
+		return
+	}
+
+	views.RenderTable(views.TableConfig{
+		Headers: utils.AWSProfileTableHeaders,
+		Rows:    views.RowsOf(profiles),
+	})
+
+	fmt.Print("Select profile number: ")
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	var selection int
+	if _, err := fmt.Sscanf(input, "%d", &selection); err != nil || selection < 1 || selection > len(profiles) {
+		fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+		return
+	}
+	profile := profiles[selection-1]
+
+	// Use the profile's own region unless it has none configured
+	utils.ShowProcessingAnimation("Switching to profile '" + profile.Name + "'")
+	err = utils.SwitchAWSProfile(profile.Name, "")
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error switching profile: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Now using profile '" + profile.Name + "' in region '" + utils.GetCurrentRegion() + "'." + utils.Reset)
+}
+
+func switchAWSRegion(reader *bufio.Reader) {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Switch AWS Region:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+	fmt.Printf("%sCurrent Region:%s %s\n\n", utils.Bold, utils.Reset, utils.GetCurrentRegion())
+
+	fmt.Print("Region (e.g., us-east-1, eu-west-1): ")
+	region, _ := reader.ReadString('\n')
+	region = strings.TrimSpace(region)
+
+	if region == "" {
+		fmt.Println(utils.Red + "Region cannot be empty." + utils.Reset)
+		return
+	}
+
+	if err := utils.SwitchAWSRegion(region); err != nil {
+		fmt.Println(utils.Red + "Error switching region: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Now using region '" + region + "' with profile '" + utils.GetCurrentProfile() + "'." + utils.Reset)
+}
+
+func viewAWSSession() {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Current STS Session:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+
+	session := utils.GetAWSSession()
+	if session == nil {
+		fmt.Println(utils.Yellow + "No STS session active. Using the profile's own credentials." + utils.Reset)
+		return
+	}
+
+	fmt.Printf("%sType:%s       %s\n", utils.Bold, utils.Reset, session.Type)
+	fmt.Printf("%sProfile:%s    %s\n", utils.Bold, utils.Reset, session.Profile)
+	if session.RoleARN != "" {
+		fmt.Printf("%sRole ARN:%s   %s\n", utils.Bold, utils.Reset, session.RoleARN)
+	}
+	if session.MFASerial != "" {
+		fmt.Printf("%sMFA Device:%s %s\n", utils.Bold, utils.Reset, session.MFASerial)
+	}
+
+	if session.Expired() {
+		fmt.Printf("%sExpires:%s    %s%s (expired)%s\n", utils.Bold, utils.Reset, utils.Red, session.Expiration.Local().Format(time.RFC1123), utils.Reset)
+	} else {
+		fmt.Printf("%sExpires:%s    %s (in %s)\n", utils.Bold, utils.Reset, session.Expiration.Local().Format(time.RFC1123), time.Until(session.Expiration).Round(time.Minute))
+	}
+}
+
+func startMFASession(reader *bufio.Reader) {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Start MFA Session:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+
+	device, err := service.LoadMFADevice()
+	if err != nil {
+		fmt.Println(utils.Red + "Error: No MFA device configured." + utils.Reset)
+		fmt.Println(utils.Cyan + "Use option 2 to add your MFA device." + utils.Reset)
+		return
+	}
+
+	fmt.Printf("%sUsing MFA Device:%s %s\n\n", utils.Bold, utils.Reset, device.DeviceName)
+
+	opts := utils.SessionOptions{MFASerial: device.DeviceARN}
+	startSession(reader, opts)
+}
+
+func assumeRole(reader *bufio.Reader) {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Assume Role:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+
+	fmt.Print("Role ARN (e.g., arn:aws:iam::123456789012:role/Admin): ")
+	roleARN, _ := reader.ReadString('\n')
+	roleARN = strings.TrimSpace(roleARN)
+
+	if roleARN == "" {
+		fmt.Println(utils.Red + "Role ARN cannot be empty." + utils.Reset)
+		return
+	}
+
+	fmt.Print("External ID (leave empty if not required): ")
+	externalID, _ := reader.ReadString('\n')
+	externalID = strings.TrimSpace(externalID)
+
+	opts := utils.SessionOptions{RoleARN: roleARN, ExternalID: externalID}
+
+	if device, err := service.LoadMFADevice(); err == nil {
+		fmt.Printf("Use MFA device '%s'? (y/n): ", device.DeviceName)
+		useMFA, _ := reader.ReadString('\n')
+		if strings.ToLower(strings.TrimSpace(useMFA)) == "y" {
+			opts.MFASerial = device.DeviceARN
+		}
+	}
+
+	startSession(reader, opts)
+}
+
+// startSession prompts for an MFA code when needed and switches the clients to the new session
+func startSession(reader *bufio.Reader, opts utils.SessionOptions) {
+	if opts.MFASerial != "" && !utils.HasCachedAWSSession(opts) {
+		fmt.Print("Enter MFA code: ")
+		code, _ := reader.ReadString('\n')
+		opts.TokenCode = strings.TrimSpace(code)
+
+		if opts.TokenCode == "" {
+			fmt.Println(utils.Red + "Error: MFA code cannot be empty." + utils.Reset)
+			return
+		}
+	}
+
+	utils.ShowProcessingAnimation("Requesting session credentials")
+	session, err := utils.StartAWSSession(opts)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error starting session: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Session started. Credentials valid until " + session.Expiration.Local().Format(time.RFC1123) + "." + utils.Reset)
+}
+
+func endAWSSession() {
+	if utils.GetAWSSession() == nil {
+		fmt.Println(utils.Yellow + "No STS session active." + utils.Reset)
+		return
+	}
+
+	if err := utils.EndAWSSession(); err != nil {
+		fmt.Println(utils.Red + "Error ending session: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Session ended. Using the profile's own credentials again." + utils.Reset)
+}
+
+// printAccounts prints the configured accounts and returns them
+func printAccounts() []string {
+	accounts, err := service.LoadAccounts()
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading accounts: " + err.Error() + utils.Reset)
+		return nil
+	}
+	if len(accounts) == 0 {
+		fmt.Println(utils.Yellow + "No accounts configured." + utils.Reset)
+		return nil
+	}
+
+	for i, account := range accounts {
+		kind := "profile"
+		if utils.IsRoleARN(account) {
+			kind = "role"
+		}
+		fmt.Printf(utils.Blue+"[%d]"+utils.Reset+" %s (%s)\n", i+1, account, kind)
+	}
+	return accounts
+}
+
+func viewAccounts() {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Configured Accounts:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+
+	if printAccounts() == nil {
+		fmt.Println(utils.Cyan + "Use option 11 to add a profile name or role ARN." + utils.Reset)
+	}
+}
+
+func addAccount(reader *bufio.Reader) {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Add Account:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+
+	fmt.Print("Profile name or role ARN (e.g., prod or arn:aws:iam::123456789012:role/Audit): ")
+	account, _ := reader.ReadString('\n')
+	account = strings.TrimSpace(account)
+
+	if account == "" {
+		fmt.Println(utils.Red + "Account cannot be empty." + utils.Reset)
+		return
+	}
+
+	accounts, err := service.LoadAccounts()
+	if err != nil {
+		fmt.Println(utils.Red + "Error loading accounts: " + err.Error() + utils.Reset)
+		return
+	}
+	for _, existing := range accounts {
+		if existing == account {
+			fmt.Println(utils.Yellow + "Account '" + account + "' is already configured." + utils.Reset)
+			return
+		}
+	}
+
+	// Make sure the account is reachable before saving it
+	utils.ShowProcessingAnimation("Verifying account")
+	clients, err := utils.GetAccountClients(account)
+	utils.StopAnimation()
+
+	if err != nil {
+		fmt.Println(utils.Red + "Error verifying account: " + err.Error() + utils.Reset)
+		return
+	}
+
+	if err := service.SaveAccounts(append(accounts, account)); err != nil {
+		fmt.Println(utils.Red + "Error saving accounts: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Account '" + account + "' (" + clients.AccountID + ") added successfully!" + utils.Reset)
+}
+
+func removeAccount(reader *bufio.Reader) {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Remove Account:" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+
+	accounts := printAccounts()
+	if accounts == nil {
+		return
+	}
+
+	fmt.Print("Select account number: ")
+	input, _ := reader.ReadString('\n')
+	input = strings.TrimSpace(input)
+
+	var selection int
+	if _, err := fmt.Sscanf(input, "%d", &selection); err != nil || selection < 1 || selection > len(accounts) {
+		fmt.Println(utils.Red + "Invalid selection." + utils.Reset)
+		return
+	}
+
+	removed := accounts[selection-1]
+	accounts = append(accounts[:selection-1], accounts[selection:]...)
+
+	if err := service.SaveAccounts(accounts); err != nil {
+		fmt.Println(utils.Red + "Error saving accounts: " + err.Error() + utils.Reset)
+		return
+	}
+
+	fmt.Println(utils.Green + "Account '" + removed + "' removed." + utils.Reset)
+}
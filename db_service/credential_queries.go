@@ -1,7 +1,16 @@
 package db_service
 
+import "errors"
+
+// ErrNoDatabase is returned when the credential database could not be opened at startup
+var ErrNoDatabase = errors.New("credential database is not available")
+
 // SaveUserCredential saves or updates a user credential in the database
 func SaveUserCredential(username, password string) error {
+	if DB == nil {
+		return ErrNoDatabase
+	}
+
 	// Encrypt password
 	encPassword, err := Encrypt(password)
 	if err != nil {
@@ -20,6 +29,10 @@ func SaveUserCredential(username, password string) error {
 
 // UpdateUserPassword updates the password for a user
 func UpdateUserPassword(username, newPassword string) error {
+	if DB == nil {
+		return ErrNoDatabase
+	}
+
 	encPassword, err := Encrypt(newPassword)
 	if err != nil {
 		return err
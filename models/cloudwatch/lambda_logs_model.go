@@ -17,22 +17,79 @@ type LogEntry struct {
 	Color   string
 }
 
-// FetchLambdaFunctions retrieves all Lambda function names using AWS SDK
-func FetchLambdaFunctions() ([]byte, error) {
+// LambdaFunction is a single row of the Lambda function listing
+type LambdaFunction struct {
+	FunctionName string `json:"function_name" yaml:"function_name"`
+	Runtime      string `json:"runtime" yaml:"runtime"`
+	LastModified string `json:"last_modified" yaml:"last_modified"`
+}
+
+// LambdaFunctionTableHeaders are the column headers matching LambdaFunction.TableRow
+var LambdaFunctionTableHeaders = []string{"Function Name", "Runtime", "Last Modified"}
+
+// TableRow returns the function as a table row
+func (f LambdaFunction) TableRow() []string {
+	return []string{f.FunctionName, f.Runtime, f.LastModified}
+}
+
+// AccountLambdaFunction is a LambdaFunction tagged with the account it was listed from
+type AccountLambdaFunction struct {
+	AccountID      string `json:"account_id" yaml:"account_id"`
+	Account        string `json:"account" yaml:"account"`
+	LambdaFunction `yaml:",inline"`
+}
+
+// AccountLambdaFunctionTableHeaders are the column headers matching AccountLambdaFunction.TableRow
+var AccountLambdaFunctionTableHeaders = append([]string{"Account ID", "Account"}, LambdaFunctionTableHeaders...)
+
+// TableRow returns the function as a table row prefixed with its account
+func (f AccountLambdaFunction) TableRow() []string {
+	return append([]string{f.AccountID, f.Account}, f.LambdaFunction.TableRow()...)
+}
+
+// FetchLambdaFunctions retrieves all Lambda functions using AWS SDK
+func FetchLambdaFunctions() ([]LambdaFunction, error) {
+	return fetchLambdaFunctions(utils.GetLambdaClient())
+}
+
+// FetchLambdaFunctionsAcrossAccounts lists the Lambda functions of several accounts concurrently.
+// Functions are returned in account order, together with the accounts that failed.
+func FetchLambdaFunctionsAcrossAccounts(accounts []string) ([]AccountLambdaFunction, []utils.AccountError) {
+	return utils.CollectAcrossAccounts(accounts, func(clients *utils.AccountClients) ([]AccountLambdaFunction, error) {
+		functions, err := fetchLambdaFunctions(clients.Lambda)
+		if err != nil {
+			return nil, err
+		}
+
+		tagged := make([]AccountLambdaFunction, 0, len(functions))
+		for _, fn := range functions {
+			tagged = append(tagged, AccountLambdaFunction{AccountID: clients.AccountID, Account: clients.Account, LambdaFunction: fn})
+		}
+		return tagged, nil
+	})
+}
+
+func fetchLambdaFunctions(client *lambda.Client) ([]LambdaFunction, error) {
 	ctx := context.TODO()
-	result, err := utils.LambdaClient.ListFunctions(ctx, &lambda.ListFunctionsInput{})
-	if err != nil {
-		return nil, err
-	}
+	functions := []LambdaFunction{}
 
-	// Format output to match old text format
-	var output strings.Builder
-	for _, fn := range result.Functions {
-		output.WriteString(aws.ToString(fn.FunctionName))
-		output.WriteString("\n")
+	paginator := lambda.NewListFunctionsPaginator(client, &lambda.ListFunctionsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fn := range page.Functions {
+			functions = append(functions, LambdaFunction{
+				FunctionName: aws.ToString(fn.FunctionName),
+				Runtime:      string(fn.Runtime),
+				LastModified: aws.ToString(fn.LastModified),
+			})
+		}
 	}
 
-	return []byte(output.String()), nil
+	return functions, nil
 }
 
 // ParseLogLine extracts the actual log message from CloudWatch format
@@ -102,7 +159,7 @@ func StreamLambdaLogs(ctx context.Context, logGroupName string, logChan chan<- L
 	}
 
 	// Initial fetch
-	result, err := utils.LogsClient.FilterLogEvents(ctx, input)
+	result, err := utils.GetLogsClient().FilterLogEvents(ctx, input)
 	if err != nil {
 		errChan <- err
 		return
@@ -132,7 +189,7 @@ func StreamLambdaLogs(ctx context.Context, logGroupName string, logChan chan<- L
 				input.NextToken = nextToken
 			}
 
-			result, err := utils.LogsClient.FilterLogEvents(ctx, input)
+			result, err := utils.GetLogsClient().FilterLogEvents(ctx, input)
 			if err != nil {
 				continue // Ignore errors and keep trying
 			}
@@ -0,0 +1,350 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Image ownership values used in AMIImage.Ownership and ImageFilter.Ownership
+const (
+	ImageOwnershipOwned  = "owned"
+	ImageOwnershipShared = "shared"
+	ImageOwnershipAll    = "all"
+)
+
+// AMIImage is a single row of the AMI catalog
+type AMIImage struct {
+	ImageID        string   `json:"image_id" yaml:"image_id"`
+	Name           string   `json:"name" yaml:"name"`
+	Description    string   `json:"description" yaml:"description"`
+	OwnerID        string   `json:"owner_id" yaml:"owner_id"`
+	Ownership      string   `json:"ownership" yaml:"ownership"`
+	State          string   `json:"state" yaml:"state"`
+	Public         bool     `json:"public" yaml:"public"`
+	Architecture   string   `json:"architecture" yaml:"architecture"`
+	RootDeviceType string   `json:"root_device_type" yaml:"root_device_type"`
+	RootDeviceName string   `json:"root_device_name" yaml:"root_device_name"`
+	CreationDate   string   `json:"creation_date" yaml:"creation_date"`
+	AgeDays        int      `json:"age_days" yaml:"age_days"`
+	Snapshots      []string `json:"snapshots" yaml:"snapshots"`
+}
+
+// ErrImageNotFound is returned when an image does not exist or is not owned by this account
+var ErrImageNotFound = errors.New("image not found or not owned by this account")
+
+// AMIImageTableHeaders are the column headers matching AMIImage.TableRow
+var AMIImageTableHeaders = []string{"Image ID", "Name", "Ownership", "Architecture", "Root Device", "Created", "Age (days)", "Snapshots"}
+
+// TableRow returns the image as a table row
+func (i AMIImage) TableRow() []string {
+	return []string{i.ImageID, i.Name, i.Ownership, i.Architecture, i.RootDeviceType + " " + i.RootDeviceName,
+		i.CreationDate, strconv.Itoa(i.AgeDays), strings.Join(i.Snapshots, ", ")}
+}
+
+// ImageFilter narrows down the AMI catalog
+type ImageFilter struct {
+	Ownership   string // owned, shared or all (default owned)
+	NamePattern string // EC2 name filter, "*" and "?" wildcards; plain text matches anywhere in the name
+	MinAgeDays  int    // only images at least this many days old
+	MaxAgeDays  int    // only images at most this many days old
+}
+
+// ImageDependencies lists what still refers to an image before it is deregistered
+type ImageDependencies struct {
+	Instances        []string `json:"instances"`
+	SharedWith       []string `json:"shared_with"`
+	Public           bool     `json:"public"`
+	Snapshots        []string `json:"snapshots"`
+	SnapshotsInUse   []string `json:"snapshots_in_use"`
+	SnapshotsInUseBy []string `json:"snapshots_in_use_by"`
+}
+
+// HasDependencies checks if the image is still in use or shared
+func (d *ImageDependencies) HasDependencies() bool {
+	return len(d.Instances) > 0 || len(d.SharedWith) > 0 || d.Public
+}
+
+// DeregisterImageResult is the outcome of deregistering one image
+type DeregisterImageResult struct {
+	ImageID          string   `json:"image_id"`
+	Success          bool     `json:"success"`
+	Message          string   `json:"message"`
+	DeletedSnapshots []string `json:"deleted_snapshots"`
+	KeptSnapshots    []string `json:"kept_snapshots"`
+	Errors           []string `json:"errors,omitempty"`
+}
+
+// FetchAMIImages lists owned and/or shared images matching the filter, newest first
+func FetchAMIImages(filter ImageFilter) ([]AMIImage, error) {
+	ownership := filter.Ownership
+	if ownership == "" {
+		ownership = ImageOwnershipOwned
+	}
+
+	var filters []types.Filter
+	if pattern := strings.TrimSpace(filter.NamePattern); pattern != "" {
+		if !strings.ContainsAny(pattern, "*?") {
+			pattern = "*" + pattern + "*"
+		}
+		filters = append(filters, types.Filter{Name: aws.String("name"), Values: []string{pattern}})
+	}
+
+	images := []AMIImage{}
+	seen := map[string]bool{}
+
+	if ownership == ImageOwnershipOwned || ownership == ImageOwnershipAll {
+		owned, err := describeImages(&ec2.DescribeImagesInput{Owners: []string{"self"}, Filters: filters}, ImageOwnershipOwned)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range owned {
+			seen[image.ImageID] = true
+			images = append(images, image)
+		}
+	}
+
+	if ownership == ImageOwnershipShared || ownership == ImageOwnershipAll {
+		shared, err := describeImages(&ec2.DescribeImagesInput{ExecutableUsers: []string{"self"}, Filters: filters}, ImageOwnershipShared)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range shared {
+			if !seen[image.ImageID] {
+				images = append(images, image)
+			}
+		}
+	}
+
+	filtered := images[:0]
+	for _, image := range images {
+		if filter.MinAgeDays > 0 && image.AgeDays < filter.MinAgeDays {
+			continue
+		}
+		if filter.MaxAgeDays > 0 && image.AgeDays > filter.MaxAgeDays {
+			continue
+		}
+		filtered = append(filtered, image)
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].CreationDate > filtered[j].CreationDate
+	})
+	return filtered, nil
+}
+
+func describeImages(input *ec2.DescribeImagesInput, ownership string) ([]AMIImage, error) {
+	ctx := context.TODO()
+	images := []AMIImage{}
+
+	paginator := ec2.NewDescribeImagesPaginator(utils.GetEC2Client(), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, image := range page.Images {
+			images = append(images, toAMIImage(image, ownership))
+		}
+	}
+
+	return images, nil
+}
+
+func toAMIImage(image types.Image, ownership string) AMIImage {
+	item := AMIImage{
+		ImageID:        aws.ToString(image.ImageId),
+		Name:           aws.ToString(image.Name),
+		Description:    aws.ToString(image.Description),
+		OwnerID:        aws.ToString(image.OwnerId),
+		Ownership:      ownership,
+		State:          string(image.State),
+		Public:         aws.ToBool(image.Public),
+		Architecture:   string(image.Architecture),
+		RootDeviceType: string(image.RootDeviceType),
+		RootDeviceName: aws.ToString(image.RootDeviceName),
+		Snapshots:      imageSnapshots(image),
+	}
+
+	if created, err := time.Parse(time.RFC3339, aws.ToString(image.CreationDate)); err == nil {
+		item.CreationDate = created.Format("2006-01-02 15:04:05")
+		item.AgeDays = int(time.Since(created).Hours() / 24)
+	}
+	return item
+}
+
+// imageSnapshots returns the EBS snapshots backing an image
+func imageSnapshots(image types.Image) []string {
+	snapshots := []string{}
+	for _, mapping := range image.BlockDeviceMappings {
+		if mapping.Ebs != nil && mapping.Ebs.SnapshotId != nil {
+			snapshots = append(snapshots, aws.ToString(mapping.Ebs.SnapshotId))
+		}
+	}
+	return snapshots
+}
+
+// CheckImageDependencies checks what still refers to an owned image
+func CheckImageDependencies(imageID string) (*ImageDependencies, error) {
+	ctx := context.TODO()
+	deps := &ImageDependencies{}
+
+	// Check if the image exists and is owned by this account
+	result, err := utils.GetEC2Client().DescribeImages(ctx, &ec2.DescribeImagesInput{
+		ImageIds: []string{imageID},
+		Owners:   []string{"self"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Images) == 0 {
+		return nil, fmt.Errorf("image '%s': %w", imageID, ErrImageNotFound)
+	}
+	image := result.Images[0]
+	deps.Public = aws.ToBool(image.Public)
+	deps.Snapshots = imageSnapshots(image)
+
+	// Instances launched from the image that still exist
+	instances, err := describeInstances(&ec2.DescribeInstancesInput{
+		Filters: []types.Filter{{Name: aws.String("image-id"), Values: []string{imageID}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances launched from the image: %w", err)
+	}
+	for _, instance := range instances {
+		if instance.State != string(types.InstanceStateNameTerminated) {
+			deps.Instances = append(deps.Instances, instance.InstanceID)
+		}
+	}
+
+	// Accounts the image is shared with
+	permissions, err := utils.GetEC2Client().DescribeImageAttribute(ctx, &ec2.DescribeImageAttributeInput{
+		ImageId:   aws.String(imageID),
+		Attribute: types.ImageAttributeNameLaunchPermission,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the image launch permissions: %w", err)
+	}
+	for _, permission := range permissions.LaunchPermissions {
+		if permission.UserId != nil {
+			deps.SharedWith = append(deps.SharedWith, aws.ToString(permission.UserId))
+		}
+	}
+
+	// Snapshots that also back other owned images must be kept
+	if len(deps.Snapshots) > 0 {
+		others, err := describeImages(&ec2.DescribeImagesInput{
+			Owners:  []string{"self"},
+			Filters: []types.Filter{{Name: aws.String("block-device-mapping.snapshot-id"), Values: deps.Snapshots}},
+		}, ImageOwnershipOwned)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list other images using the snapshots: %w", err)
+		}
+		for _, other := range others {
+			if other.ImageID == imageID {
+				continue
+			}
+			for _, snapshot := range other.Snapshots {
+				if containsString(deps.Snapshots, snapshot) && !containsString(deps.SnapshotsInUse, snapshot) {
+					deps.SnapshotsInUse = append(deps.SnapshotsInUse, snapshot)
+				}
+			}
+			deps.SnapshotsInUseBy = append(deps.SnapshotsInUseBy, other.ImageID)
+		}
+	}
+
+	return deps, nil
+}
+
+// DeregisterImageWithSnapshots deregisters an owned image and deletes the snapshots
+// backing it, except snapshots used by other images. Unless force is set the
+// image is left alone when it still has dependencies.
+func DeregisterImageWithSnapshots(imageID string, force, keepSnapshots bool) DeregisterImageResult {
+	ctx := context.TODO()
+	result := DeregisterImageResult{ImageID: imageID, DeletedSnapshots: []string{}, KeptSnapshots: []string{}}
+
+	// Without a complete dependency check the image could be in use, so nothing is touched
+	deps, err := CheckImageDependencies(imageID)
+	if err != nil {
+		result.Message = "Failed to check image dependencies: " + err.Error()
+		return result
+	}
+
+	if deps.HasDependencies() && !force {
+		var reasons []string
+		if len(deps.Instances) > 0 {
+			reasons = append(reasons, "used by instances "+strings.Join(deps.Instances, ", "))
+		}
+		if len(deps.SharedWith) > 0 {
+			reasons = append(reasons, "shared with "+strings.Join(deps.SharedWith, ", "))
+		}
+		if deps.Public {
+			reasons = append(reasons, "public")
+		}
+		result.Message = "Image has dependencies: " + strings.Join(reasons, "; ")
+		return result
+	}
+
+	_, err = utils.GetEC2Client().DeregisterImage(ctx, &ec2.DeregisterImageInput{ImageId: aws.String(imageID)})
+	if err != nil {
+		result.Message = "Failed to deregister image: " + err.Error()
+		return result
+	}
+	result.Success = true
+
+	for _, snapshot := range deps.Snapshots {
+		if keepSnapshots || containsString(deps.SnapshotsInUse, snapshot) {
+			result.KeptSnapshots = append(result.KeptSnapshots, snapshot)
+			continue
+		}
+
+		if err := DeleteEBSSnapshot(snapshot); err != nil {
+			result.Errors = append(result.Errors, "snapshot "+snapshot+": "+err.Error())
+			continue
+		}
+		result.DeletedSnapshots = append(result.DeletedSnapshots, snapshot)
+	}
+
+	result.Message = "Image deregistered"
+	if len(result.Errors) > 0 {
+		result.Message = "Image deregistered, but some snapshots could not be deleted"
+	}
+	return result
+}
+
+// DeregisterMultipleImages deregisters several images in parallel
+func DeregisterMultipleImages(imageIDs []string, force, keepSnapshots bool) []DeregisterImageResult {
+	var wg sync.WaitGroup
+	results := make([]DeregisterImageResult, len(imageIDs))
+
+	for i, imageID := range imageIDs {
+		wg.Add(1)
+		go func(index int, id string) {
+			defer wg.Done()
+			results[index] = DeregisterImageWithSnapshots(id, force, keepSnapshots)
+		}(i, imageID)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,226 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+// EC2Instance is a single row of the instance listing
+type EC2Instance struct {
+	InstanceID       string            `json:"instance_id" yaml:"instance_id"`
+	Name             string            `json:"name" yaml:"name"`
+	State            string            `json:"state" yaml:"state"`
+	InstanceType     string            `json:"instance_type" yaml:"instance_type"`
+	AvailabilityZone string            `json:"availability_zone" yaml:"availability_zone"`
+	PublicIP         string            `json:"public_ip" yaml:"public_ip"`
+	PrivateIP        string            `json:"private_ip" yaml:"private_ip"`
+	ImageID          string            `json:"image_id" yaml:"image_id"`
+	LaunchTime       string            `json:"launch_time" yaml:"launch_time"`
+	Tags             map[string]string `json:"tags" yaml:"tags"`
+}
+
+// ErrInstanceNotFound is returned when an instance ID is malformed or does not exist
+var ErrInstanceNotFound = errors.New("instance not found")
+
+// EC2InstanceTableHeaders are the column headers matching EC2Instance.TableRow
+var EC2InstanceTableHeaders = []string{"Instance ID", "Name", "State", "Type", "AZ", "Public IP", "Private IP", "Tags"}
+
+// TableRow returns the instance as a table row
+func (i EC2Instance) TableRow() []string {
+	return []string{i.InstanceID, i.Name, i.State, i.InstanceType, i.AvailabilityZone, i.PublicIP, i.PrivateIP, FormatTags(i.Tags)}
+}
+
+// InstanceStateChange is the result of starting, stopping or terminating an instance
+type InstanceStateChange struct {
+	InstanceID    string `json:"instance_id" yaml:"instance_id"`
+	PreviousState string `json:"previous_state" yaml:"previous_state"`
+	CurrentState  string `json:"current_state" yaml:"current_state"`
+}
+
+// LaunchInstanceOptions describes an instance to launch from an AMI
+type LaunchInstanceOptions struct {
+	ImageID          string            `json:"image_id"`
+	InstanceType     string            `json:"instance_type"`
+	KeyName          string            `json:"key_name"`
+	SecurityGroupIDs []string          `json:"security_group_ids"`
+	SubnetID         string            `json:"subnet_id"`
+	Name             string            `json:"name"`
+	Count            int32             `json:"count"`
+	Tags             map[string]string `json:"tags"`
+}
+
+// FetchEC2Instances lists all instances in the current region
+func FetchEC2Instances() ([]EC2Instance, error) {
+	return describeInstances(&ec2.DescribeInstancesInput{})
+}
+
+// GetEC2Instance returns a single instance. ErrInstanceNotFound is returned, wrapped, when the
+// ID is malformed or no such instance exists.
+func GetEC2Instance(instanceID string) (*EC2Instance, error) {
+	instances, err := describeInstances(&ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && (apiErr.ErrorCode() == "InvalidInstanceID.NotFound" || apiErr.ErrorCode() == "InvalidInstanceID.Malformed") {
+		return nil, fmt.Errorf("instance '%s': %w: %s", instanceID, ErrInstanceNotFound, apiErr.ErrorMessage())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return nil, fmt.Errorf("instance '%s': %w", instanceID, ErrInstanceNotFound)
+	}
+	return &instances[0], nil
+}
+
+func describeInstances(input *ec2.DescribeInstancesInput) ([]EC2Instance, error) {
+	ctx := context.TODO()
+	instances := []EC2Instance{}
+
+	paginator := ec2.NewDescribeInstancesPaginator(utils.GetEC2Client(), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				instances = append(instances, toEC2Instance(instance))
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+func toEC2Instance(instance types.Instance) EC2Instance {
+	tags := tagsToMap(instance.Tags)
+	item := EC2Instance{
+		InstanceID:   aws.ToString(instance.InstanceId),
+		Name:         tags["Name"],
+		InstanceType: string(instance.InstanceType),
+		PublicIP:     aws.ToString(instance.PublicIpAddress),
+		PrivateIP:    aws.ToString(instance.PrivateIpAddress),
+		ImageID:      aws.ToString(instance.ImageId),
+		Tags:         tags,
+	}
+	if instance.State != nil {
+		item.State = string(instance.State.Name)
+	}
+	if instance.Placement != nil {
+		item.AvailabilityZone = aws.ToString(instance.Placement.AvailabilityZone)
+	}
+	if instance.LaunchTime != nil {
+		item.LaunchTime = instance.LaunchTime.Format("2006-01-02 15:04:05")
+	}
+	return item
+}
+
+// StartEC2Instances starts stopped instances
+func StartEC2Instances(instanceIDs []string) ([]InstanceStateChange, error) {
+	result, err := utils.GetEC2Client().StartInstances(context.TODO(), &ec2.StartInstancesInput{InstanceIds: instanceIDs})
+	if err != nil {
+		return nil, err
+	}
+	return toStateChanges(result.StartingInstances), nil
+}
+
+// StopEC2Instances stops running instances
+func StopEC2Instances(instanceIDs []string) ([]InstanceStateChange, error) {
+	result, err := utils.GetEC2Client().StopInstances(context.TODO(), &ec2.StopInstancesInput{InstanceIds: instanceIDs})
+	if err != nil {
+		return nil, err
+	}
+	return toStateChanges(result.StoppingInstances), nil
+}
+
+// RebootEC2Instances requests a reboot of running instances
+func RebootEC2Instances(instanceIDs []string) error {
+	_, err := utils.GetEC2Client().RebootInstances(context.TODO(), &ec2.RebootInstancesInput{InstanceIds: instanceIDs})
+	return err
+}
+
+// TerminateEC2Instances terminates instances
+func TerminateEC2Instances(instanceIDs []string) ([]InstanceStateChange, error) {
+	result, err := utils.GetEC2Client().TerminateInstances(context.TODO(), &ec2.TerminateInstancesInput{InstanceIds: instanceIDs})
+	if err != nil {
+		return nil, err
+	}
+	return toStateChanges(result.TerminatingInstances), nil
+}
+
+func toStateChanges(changes []types.InstanceStateChange) []InstanceStateChange {
+	result := make([]InstanceStateChange, 0, len(changes))
+	for _, change := range changes {
+		item := InstanceStateChange{InstanceID: aws.ToString(change.InstanceId)}
+		if change.PreviousState != nil {
+			item.PreviousState = string(change.PreviousState.Name)
+		}
+		if change.CurrentState != nil {
+			item.CurrentState = string(change.CurrentState.Name)
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// LaunchEC2Instances launches instances from an AMI and returns them
+func LaunchEC2Instances(opts LaunchInstanceOptions) ([]EC2Instance, error) {
+	if strings.TrimSpace(opts.ImageID) == "" {
+		return nil, errors.New("image_id is required")
+	}
+	if opts.InstanceType == "" {
+		opts.InstanceType = "t3.micro"
+	}
+	if opts.Count < 1 {
+		opts.Count = 1
+	}
+
+	input := &ec2.RunInstancesInput{
+		ImageId:      aws.String(opts.ImageID),
+		InstanceType: types.InstanceType(opts.InstanceType),
+		MinCount:     aws.Int32(opts.Count),
+		MaxCount:     aws.Int32(opts.Count),
+	}
+	if opts.KeyName != "" {
+		input.KeyName = aws.String(opts.KeyName)
+	}
+	if len(opts.SecurityGroupIDs) > 0 {
+		input.SecurityGroupIds = opts.SecurityGroupIDs
+	}
+	if opts.SubnetID != "" {
+		input.SubnetId = aws.String(opts.SubnetID)
+	}
+
+	tags := map[string]string{}
+	for key, value := range opts.Tags {
+		tags[key] = value
+	}
+	if opts.Name != "" {
+		tags["Name"] = opts.Name
+	}
+	if len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{{
+			ResourceType: types.ResourceTypeInstance,
+			Tags:         mapToTags(tags),
+		}}
+	}
+
+	result, err := utils.GetEC2Client().RunInstances(context.TODO(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]EC2Instance, 0, len(result.Instances))
+	for _, instance := range result.Instances {
+		instances = append(instances, toEC2Instance(instance))
+	}
+	return instances, nil
+}
@@ -0,0 +1,85 @@
+package ec2
+
+import (
+	"context"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// KeyPair is a single row of the key pair listing
+type KeyPair struct {
+	KeyName string `json:"key_name" yaml:"key_name"`
+	KeyType string `json:"key_type" yaml:"key_type"`
+	KeyID   string `json:"key_id" yaml:"key_id"`
+}
+
+// KeyPairTableHeaders are the column headers matching KeyPair.TableRow
+var KeyPairTableHeaders = []string{"Key Name", "Type", "Key ID"}
+
+// TableRow returns the key pair as a table row
+func (k KeyPair) TableRow() []string {
+	return []string{k.KeyName, k.KeyType, k.KeyID}
+}
+
+// Subnet is a single row of the subnet listing
+type Subnet struct {
+	SubnetID         string `json:"subnet_id" yaml:"subnet_id"`
+	Name             string `json:"name" yaml:"name"`
+	VpcID            string `json:"vpc_id" yaml:"vpc_id"`
+	AvailabilityZone string `json:"availability_zone" yaml:"availability_zone"`
+	CidrBlock        string `json:"cidr_block" yaml:"cidr_block"`
+}
+
+// SubnetTableHeaders are the column headers matching Subnet.TableRow
+var SubnetTableHeaders = []string{"Subnet ID", "Name", "VPC ID", "AZ", "CIDR"}
+
+// TableRow returns the subnet as a table row
+func (s Subnet) TableRow() []string {
+	return []string{s.SubnetID, s.Name, s.VpcID, s.AvailabilityZone, s.CidrBlock}
+}
+
+// FetchKeyPairs lists the key pairs in the current region
+func FetchKeyPairs() ([]KeyPair, error) {
+	result, err := utils.GetEC2Client().DescribeKeyPairs(context.TODO(), &ec2.DescribeKeyPairsInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	keyPairs := make([]KeyPair, 0, len(result.KeyPairs))
+	for _, key := range result.KeyPairs {
+		keyPairs = append(keyPairs, KeyPair{
+			KeyName: aws.ToString(key.KeyName),
+			KeyType: string(key.KeyType),
+			KeyID:   aws.ToString(key.KeyPairId),
+		})
+	}
+	return keyPairs, nil
+}
+
+// FetchSubnets lists the subnets in the current region
+func FetchSubnets() ([]Subnet, error) {
+	ctx := context.TODO()
+	subnets := []Subnet{}
+
+	paginator := ec2.NewDescribeSubnetsPaginator(utils.GetEC2Client(), &ec2.DescribeSubnetsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subnet := range page.Subnets {
+			subnets = append(subnets, Subnet{
+				SubnetID:         aws.ToString(subnet.SubnetId),
+				Name:             tagsToMap(subnet.Tags)["Name"],
+				VpcID:            aws.ToString(subnet.VpcId),
+				AvailabilityZone: aws.ToString(subnet.AvailabilityZone),
+				CidrBlock:        aws.ToString(subnet.CidrBlock),
+			})
+		}
+	}
+
+	return subnets, nil
+}
@@ -0,0 +1,413 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Rule directions used in SecurityGroupRule.Direction and RuleInput.Direction
+const (
+	RuleDirectionInbound  = "inbound"
+	RuleDirectionOutbound = "outbound"
+)
+
+// riskyPort is a service that must never be reachable from the whole internet
+type riskyPort struct {
+	Service   string
+	Protocols []string // "tcp" or "udp"
+}
+
+// riskyPorts are the ports that must never be reachable from the whole internet
+var riskyPorts = map[int32]riskyPort{
+	22:   {Service: "SSH", Protocols: []string{"tcp"}},
+	3389: {Service: "RDP", Protocols: []string{"tcp", "udp"}},
+}
+
+// VPC is a single row of the VPC listing
+type VPC struct {
+	VpcID     string `json:"vpc_id" yaml:"vpc_id"`
+	Name      string `json:"name" yaml:"name"`
+	CidrBlock string `json:"cidr_block" yaml:"cidr_block"`
+	IsDefault bool   `json:"is_default" yaml:"is_default"`
+	State     string `json:"state" yaml:"state"`
+}
+
+// VPCTableHeaders are the column headers matching VPC.TableRow
+var VPCTableHeaders = []string{"VPC ID", "Name", "CIDR", "Default", "State"}
+
+// TableRow returns the VPC as a table row
+func (v VPC) TableRow() []string {
+	return []string{v.VpcID, v.Name, v.CidrBlock, strconv.FormatBool(v.IsDefault), v.State}
+}
+
+// SecurityGroup is a single row of the security group listing
+type SecurityGroup struct {
+	GroupID       string   `json:"group_id" yaml:"group_id"`
+	GroupName     string   `json:"group_name" yaml:"group_name"`
+	VpcID         string   `json:"vpc_id" yaml:"vpc_id"`
+	Description   string   `json:"description" yaml:"description"`
+	InboundRules  int      `json:"inbound_rules" yaml:"inbound_rules"`
+	OutboundRules int      `json:"outbound_rules" yaml:"outbound_rules"`
+	Risks         []string `json:"risks" yaml:"risks"`
+}
+
+// SecurityGroupTableHeaders are the column headers matching SecurityGroup.TableRow
+var SecurityGroupTableHeaders = []string{"Group ID", "Group Name", "VPC ID", "Inbound", "Outbound", "Risks", "Description"}
+
+// TableRow returns the security group as a table row
+func (g SecurityGroup) TableRow() []string {
+	return []string{g.GroupID, g.GroupName, g.VpcID, strconv.Itoa(g.InboundRules), strconv.Itoa(g.OutboundRules),
+		strings.Join(g.Risks, ", "), g.Description}
+}
+
+// SecurityGroupRule is a single inbound or outbound rule
+type SecurityGroupRule struct {
+	RuleID      string `json:"rule_id" yaml:"rule_id"`
+	GroupID     string `json:"group_id" yaml:"group_id"`
+	Direction   string `json:"direction" yaml:"direction"`
+	Protocol    string `json:"protocol" yaml:"protocol"`
+	FromPort    int32  `json:"from_port" yaml:"from_port"`
+	ToPort      int32  `json:"to_port" yaml:"to_port"`
+	Source      string `json:"source" yaml:"source"`
+	Description string `json:"description" yaml:"description"`
+	Risk        string `json:"risk,omitempty" yaml:"risk,omitempty"`
+}
+
+// SecurityGroupRuleTableHeaders are the column headers matching SecurityGroupRule.TableRow
+var SecurityGroupRuleTableHeaders = []string{"Rule ID", "Group ID", "Direction", "Protocol", "Ports", "Source/Destination", "Description", "Risk"}
+
+// TableRow returns the rule as a table row
+func (r SecurityGroupRule) TableRow() []string {
+	return []string{r.RuleID, r.GroupID, r.Direction, r.protocolName(), r.PortRange(), r.Source, r.Description, r.Risk}
+}
+
+// PortRange renders the rule's ports ("All", "22" or "1024-2048")
+func (r SecurityGroupRule) PortRange() string {
+	if r.Protocol == "-1" || r.FromPort == -1 {
+		return "All"
+	}
+	if r.FromPort == r.ToPort {
+		return strconv.Itoa(int(r.FromPort))
+	}
+	return strconv.Itoa(int(r.FromPort)) + "-" + strconv.Itoa(int(r.ToPort))
+}
+
+func (r SecurityGroupRule) protocolName() string {
+	if r.Protocol == "-1" {
+		return "all"
+	}
+	return r.Protocol
+}
+
+// RuleInput describes a rule to add to a security group.
+// Exactly one of CIDR and SourceGroupID must be set.
+type RuleInput struct {
+	Direction     string `json:"direction"`
+	Protocol      string `json:"protocol"`
+	FromPort      int32  `json:"from_port"`
+	ToPort        int32  `json:"to_port"`
+	CIDR          string `json:"cidr"`
+	SourceGroupID string `json:"source_group_id"`
+	Description   string `json:"description"`
+}
+
+// FetchVPCs lists the VPCs in the current region
+func FetchVPCs() ([]VPC, error) {
+	ctx := context.TODO()
+	vpcs := []VPC{}
+
+	paginator := ec2.NewDescribeVpcsPaginator(utils.GetEC2Client(), &ec2.DescribeVpcsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vpc := range page.Vpcs {
+			vpcs = append(vpcs, VPC{
+				VpcID:     aws.ToString(vpc.VpcId),
+				Name:      tagsToMap(vpc.Tags)["Name"],
+				CidrBlock: aws.ToString(vpc.CidrBlock),
+				IsDefault: aws.ToBool(vpc.IsDefault),
+				State:     string(vpc.State),
+			})
+		}
+	}
+
+	return vpcs, nil
+}
+
+// FetchSecurityGroups lists the security groups with rule counts and risks,
+// optionally limited to one VPC
+func FetchSecurityGroups(vpcID string) ([]SecurityGroup, error) {
+	ctx := context.TODO()
+	groups := []SecurityGroup{}
+
+	input := &ec2.DescribeSecurityGroupsInput{}
+	if vpcID != "" {
+		input.Filters = []types.Filter{{Name: aws.String("vpc-id"), Values: []string{vpcID}}}
+	}
+
+	paginator := ec2.NewDescribeSecurityGroupsPaginator(utils.GetEC2Client(), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, group := range page.SecurityGroups {
+			item := SecurityGroup{
+				GroupID:     aws.ToString(group.GroupId),
+				GroupName:   aws.ToString(group.GroupName),
+				VpcID:       aws.ToString(group.VpcId),
+				Description: aws.ToString(group.Description),
+				Risks:       []string{},
+			}
+
+			for _, rule := range permissionsToRules(item.GroupID, RuleDirectionInbound, group.IpPermissions) {
+				item.InboundRules++
+				if rule.Risk != "" && !containsString(item.Risks, rule.Risk) {
+					item.Risks = append(item.Risks, rule.Risk)
+				}
+			}
+			item.OutboundRules = len(permissionsToRules(item.GroupID, RuleDirectionOutbound, group.IpPermissionsEgress))
+
+			groups = append(groups, item)
+		}
+	}
+
+	return groups, nil
+}
+
+// FetchSecurityGroupRules lists the rules of one security group, or of every group when groupID is empty
+func FetchSecurityGroupRules(groupID string) ([]SecurityGroupRule, error) {
+	ctx := context.TODO()
+	rules := []SecurityGroupRule{}
+
+	input := &ec2.DescribeSecurityGroupRulesInput{}
+	if groupID != "" {
+		input.Filters = []types.Filter{{Name: aws.String("group-id"), Values: []string{groupID}}}
+	}
+
+	paginator := ec2.NewDescribeSecurityGroupRulesPaginator(utils.GetEC2Client(), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range page.SecurityGroupRules {
+			rules = append(rules, toSecurityGroupRule(rule))
+		}
+	}
+
+	return rules, nil
+}
+
+// FetchRiskyRules lists the inbound rules that expose SSH, RDP or all traffic to the internet
+func FetchRiskyRules() ([]SecurityGroupRule, error) {
+	rules, err := FetchSecurityGroupRules("")
+	if err != nil {
+		return nil, err
+	}
+
+	risky := []SecurityGroupRule{}
+	for _, rule := range rules {
+		if rule.Risk != "" {
+			risky = append(risky, rule)
+		}
+	}
+	return risky, nil
+}
+
+func toSecurityGroupRule(rule types.SecurityGroupRule) SecurityGroupRule {
+	item := SecurityGroupRule{
+		RuleID:      aws.ToString(rule.SecurityGroupRuleId),
+		GroupID:     aws.ToString(rule.GroupId),
+		Direction:   RuleDirectionInbound,
+		Protocol:    aws.ToString(rule.IpProtocol),
+		FromPort:    aws.ToInt32(rule.FromPort),
+		ToPort:      aws.ToInt32(rule.ToPort),
+		Description: aws.ToString(rule.Description),
+	}
+	if aws.ToBool(rule.IsEgress) {
+		item.Direction = RuleDirectionOutbound
+	}
+
+	switch {
+	case rule.CidrIpv4 != nil:
+		item.Source = aws.ToString(rule.CidrIpv4)
+	case rule.CidrIpv6 != nil:
+		item.Source = aws.ToString(rule.CidrIpv6)
+	case rule.ReferencedGroupInfo != nil:
+		item.Source = aws.ToString(rule.ReferencedGroupInfo.GroupId)
+	case rule.PrefixListId != nil:
+		item.Source = aws.ToString(rule.PrefixListId)
+	}
+
+	item.Risk = ruleRisk(item)
+	return item
+}
+
+// permissionsToRules flattens IpPermissions into one rule per source
+func permissionsToRules(groupID, direction string, permissions []types.IpPermission) []SecurityGroupRule {
+	var rules []SecurityGroupRule
+	for _, permission := range permissions {
+		base := SecurityGroupRule{
+			GroupID:   groupID,
+			Direction: direction,
+			Protocol:  aws.ToString(permission.IpProtocol),
+			FromPort:  aws.ToInt32(permission.FromPort),
+			ToPort:    aws.ToInt32(permission.ToPort),
+		}
+
+		var sources []string
+		for _, r := range permission.IpRanges {
+			sources = append(sources, aws.ToString(r.CidrIp))
+		}
+		for _, r := range permission.Ipv6Ranges {
+			sources = append(sources, aws.ToString(r.CidrIpv6))
+		}
+		for _, pair := range permission.UserIdGroupPairs {
+			sources = append(sources, aws.ToString(pair.GroupId))
+		}
+		for _, prefix := range permission.PrefixListIds {
+			sources = append(sources, aws.ToString(prefix.PrefixListId))
+		}
+
+		for _, source := range sources {
+			rule := base
+			rule.Source = source
+			rule.Risk = ruleRisk(rule)
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// ruleRisk describes why an inbound rule is risky, or returns "" when it is not
+func ruleRisk(rule SecurityGroupRule) string {
+	if rule.Direction != RuleDirectionInbound || (rule.Source != "0.0.0.0/0" && rule.Source != "::/0") {
+		return ""
+	}
+
+	if rule.Protocol == "-1" {
+		return "All traffic open to the internet"
+	}
+
+	// Rules may name the protocol or give its number
+	protocol := map[string]string{"6": "tcp", "17": "udp"}[rule.Protocol]
+	if protocol == "" {
+		protocol = strings.ToLower(rule.Protocol)
+	}
+
+	var ports []int32
+	for port, risky := range riskyPorts {
+		if containsString(risky.Protocols, protocol) && rule.FromPort <= port && port <= rule.ToPort {
+			ports = append(ports, port)
+		}
+	}
+	if len(ports) == 0 {
+		return ""
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	exposed := make([]string, 0, len(ports))
+	for _, port := range ports {
+		exposed = append(exposed, fmt.Sprintf("%s (%d)", riskyPorts[port].Service, port))
+	}
+	return strings.Join(exposed, ", ") + " open to the internet"
+}
+
+// AddSecurityGroupRule adds an inbound or outbound rule to a security group
+func AddSecurityGroupRule(groupID string, rule RuleInput) error {
+	if rule.Protocol == "" {
+		return errors.New("protocol is required")
+	}
+	if (rule.CIDR == "") == (rule.SourceGroupID == "") {
+		return errors.New("exactly one of cidr and source_group_id is required")
+	}
+
+	permission := types.IpPermission{
+		IpProtocol: aws.String(rule.Protocol),
+	}
+	if rule.Protocol != "-1" {
+		permission.FromPort = aws.Int32(rule.FromPort)
+		permission.ToPort = aws.Int32(rule.ToPort)
+	}
+
+	var description *string
+	if rule.Description != "" {
+		description = aws.String(rule.Description)
+	}
+
+	switch {
+	case rule.SourceGroupID != "":
+		permission.UserIdGroupPairs = []types.UserIdGroupPair{{GroupId: aws.String(rule.SourceGroupID), Description: description}}
+	case strings.Contains(rule.CIDR, ":"):
+		permission.Ipv6Ranges = []types.Ipv6Range{{CidrIpv6: aws.String(rule.CIDR), Description: description}}
+	default:
+		permission.IpRanges = []types.IpRange{{CidrIp: aws.String(rule.CIDR), Description: description}}
+	}
+
+	ctx := context.TODO()
+	var err error
+	switch rule.Direction {
+	case RuleDirectionInbound, "":
+		_, err = utils.GetEC2Client().AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: []types.IpPermission{permission},
+		})
+	case RuleDirectionOutbound:
+		_, err = utils.GetEC2Client().AuthorizeSecurityGroupEgress(ctx, &ec2.AuthorizeSecurityGroupEgressInput{
+			GroupId:       aws.String(groupID),
+			IpPermissions: []types.IpPermission{permission},
+		})
+	default:
+		return errors.New("direction must be inbound or outbound")
+	}
+	return err
+}
+
+// RevokeSecurityGroupRule removes a rule from a security group by its rule ID
+func RevokeSecurityGroupRule(groupID, ruleID string) error {
+	rules, err := FetchSecurityGroupRules(groupID)
+	if err != nil {
+		return err
+	}
+
+	var rule *SecurityGroupRule
+	for i := range rules {
+		if rules[i].RuleID == ruleID {
+			rule = &rules[i]
+			break
+		}
+	}
+	if rule == nil {
+		return errors.New("rule '" + ruleID + "' not found in security group '" + groupID + "'")
+	}
+
+	ctx := context.TODO()
+	if rule.Direction == RuleDirectionOutbound {
+		_, err = utils.GetEC2Client().RevokeSecurityGroupEgress(ctx, &ec2.RevokeSecurityGroupEgressInput{
+			GroupId:              aws.String(groupID),
+			SecurityGroupRuleIds: []string{ruleID},
+		})
+		return err
+	}
+
+	_, err = utils.GetEC2Client().RevokeSecurityGroupIngress(ctx, &ec2.RevokeSecurityGroupIngressInput{
+		GroupId:              aws.String(groupID),
+		SecurityGroupRuleIds: []string{ruleID},
+	})
+	return err
+}
@@ -0,0 +1,79 @@
+package ec2
+
+import "testing"
+
+func TestRuleRisk(t *testing.T) {
+	open := func(protocol string, from, to int32) SecurityGroupRule {
+		return SecurityGroupRule{Direction: RuleDirectionInbound, Protocol: protocol, FromPort: from, ToPort: to, Source: "0.0.0.0/0"}
+	}
+
+	tests := []struct {
+		name string
+		rule SecurityGroupRule
+		want string
+	}{
+		{
+			name: "SSH open to IPv4",
+			rule: open("tcp", 22, 22),
+			want: "SSH (22) open to the internet",
+		},
+		{
+			name: "SSH open to IPv6",
+			rule: SecurityGroupRule{Direction: RuleDirectionInbound, Protocol: "tcp", FromPort: 22, ToPort: 22, Source: "::/0"},
+			want: "SSH (22) open to the internet",
+		},
+		{
+			name: "port range spanning SSH and RDP lists both",
+			rule: open("tcp", 0, 65535),
+			want: "SSH (22), RDP (3389) open to the internet",
+		},
+		{
+			name: "protocol given by number",
+			rule: open("6", 20, 3400),
+			want: "SSH (22), RDP (3389) open to the internet",
+		},
+		{
+			name: "UDP only exposes RDP",
+			rule: open("udp", 0, 65535),
+			want: "RDP (3389) open to the internet",
+		},
+		{
+			name: "UDP by number on the SSH port is not risky",
+			rule: open("17", 22, 22),
+			want: "",
+		},
+		{
+			name: "all traffic",
+			rule: open("-1", -1, -1),
+			want: "All traffic open to the internet",
+		},
+		{
+			name: "ICMP is not risky",
+			rule: open("icmp", -1, -1),
+			want: "",
+		},
+		{
+			name: "other ports are not risky",
+			rule: open("tcp", 80, 443),
+			want: "",
+		},
+		{
+			name: "restricted source",
+			rule: SecurityGroupRule{Direction: RuleDirectionInbound, Protocol: "tcp", FromPort: 22, ToPort: 22, Source: "10.0.0.0/8"},
+			want: "",
+		},
+		{
+			name: "outbound rules are never risky",
+			rule: SecurityGroupRule{Direction: RuleDirectionOutbound, Protocol: "-1", FromPort: -1, ToPort: -1, Source: "0.0.0.0/0"},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ruleRisk(tt.rule); got != tt.want {
+				t.Errorf("ruleRisk() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
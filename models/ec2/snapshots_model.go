@@ -0,0 +1,176 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// EBSSnapshot is a single row of the snapshot listing
+type EBSSnapshot struct {
+	SnapshotID  string            `json:"snapshot_id" yaml:"snapshot_id"`
+	Name        string            `json:"name" yaml:"name"`
+	VolumeID    string            `json:"volume_id" yaml:"volume_id"`
+	Size        int32             `json:"size_gib" yaml:"size_gib"`
+	State       string            `json:"state" yaml:"state"`
+	Progress    string            `json:"progress" yaml:"progress"`
+	Description string            `json:"description" yaml:"description"`
+	StartTime   string            `json:"start_time" yaml:"start_time"`
+	Tags        map[string]string `json:"tags" yaml:"tags"`
+}
+
+// EBSSnapshotTableHeaders are the column headers matching EBSSnapshot.TableRow
+var EBSSnapshotTableHeaders = []string{"Snapshot ID", "Name", "Volume ID", "Size (GiB)", "State", "Started", "Description", "Tags"}
+
+// TableRow returns the snapshot as a table row
+func (s EBSSnapshot) TableRow() []string {
+	return []string{s.SnapshotID, s.Name, s.VolumeID, strconv.Itoa(int(s.Size)), s.State + " " + s.Progress, s.StartTime, s.Description, FormatTags(s.Tags)}
+}
+
+// FetchEBSSnapshots lists the snapshots owned by the account in the current region
+func FetchEBSSnapshots() ([]EBSSnapshot, error) {
+	ctx := context.TODO()
+	snapshots := []EBSSnapshot{}
+
+	paginator := ec2.NewDescribeSnapshotsPaginator(utils.GetEC2Client(), &ec2.DescribeSnapshotsInput{
+		OwnerIds: []string{"self"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snapshot := range page.Snapshots {
+			snapshots = append(snapshots, toEBSSnapshot(snapshot))
+		}
+	}
+
+	return snapshots, nil
+}
+
+func toEBSSnapshot(snapshot types.Snapshot) EBSSnapshot {
+	tags := tagsToMap(snapshot.Tags)
+	item := EBSSnapshot{
+		SnapshotID:  aws.ToString(snapshot.SnapshotId),
+		Name:        tags["Name"],
+		VolumeID:    aws.ToString(snapshot.VolumeId),
+		Size:        aws.ToInt32(snapshot.VolumeSize),
+		State:       string(snapshot.State),
+		Progress:    aws.ToString(snapshot.Progress),
+		Description: aws.ToString(snapshot.Description),
+		Tags:        tags,
+	}
+	if snapshot.StartTime != nil {
+		item.StartTime = snapshot.StartTime.Format("2006-01-02 15:04:05")
+	}
+	return item
+}
+
+// CreateEBSSnapshot snapshots a volume with a description and tags
+func CreateEBSSnapshot(volumeID, description string, tags map[string]string) (*EBSSnapshot, error) {
+	if volumeID == "" {
+		return nil, errors.New("volume_id is required")
+	}
+
+	input := &ec2.CreateSnapshotInput{
+		VolumeId: aws.String(volumeID),
+	}
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+	if len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{{
+			ResourceType: types.ResourceTypeSnapshot,
+			Tags:         mapToTags(tags),
+		}}
+	}
+
+	result, err := utils.GetEC2Client().CreateSnapshot(context.TODO(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := EBSSnapshot{
+		SnapshotID:  aws.ToString(result.SnapshotId),
+		Name:        tags["Name"],
+		VolumeID:    aws.ToString(result.VolumeId),
+		Size:        aws.ToInt32(result.VolumeSize),
+		State:       string(result.State),
+		Progress:    aws.ToString(result.Progress),
+		Description: aws.ToString(result.Description),
+		Tags:        tags,
+	}
+	if result.StartTime != nil {
+		snapshot.StartTime = result.StartTime.Format("2006-01-02 15:04:05")
+	}
+	return &snapshot, nil
+}
+
+// DeleteEBSSnapshot deletes a snapshot
+func DeleteEBSSnapshot(snapshotID string) error {
+	_, err := utils.GetEC2Client().DeleteSnapshot(context.TODO(), &ec2.DeleteSnapshotInput{
+		SnapshotId: aws.String(snapshotID),
+	})
+	return err
+}
+
+// CopyEBSSnapshot copies a snapshot from the current region to another region
+// and returns the ID of the new snapshot. The snapshot's tags are copied as well.
+func CopyEBSSnapshot(snapshotID, destinationRegion, description string) (string, error) {
+	if destinationRegion == "" {
+		return "", errors.New("destination region is required")
+	}
+
+	sourceRegion := utils.GetCurrentRegion()
+	if description == "" {
+		description = "Copy of " + snapshotID + " from " + sourceRegion
+	}
+
+	ctx := context.TODO()
+	source, err := utils.GetEC2Client().DescribeSnapshots(ctx, &ec2.DescribeSnapshotsInput{
+		SnapshotIds: []string{snapshotID},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	input := &ec2.CopySnapshotInput{
+		SourceSnapshotId: aws.String(snapshotID),
+		SourceRegion:     aws.String(sourceRegion),
+		Description:      aws.String(description),
+	}
+	if len(source.Snapshots) > 0 {
+		// Tags with the reserved aws: prefix cannot be set by users
+		tags := tagsToMap(source.Snapshots[0].Tags)
+		for key := range tags {
+			if strings.HasPrefix(key, "aws:") {
+				delete(tags, key)
+			}
+		}
+		if len(tags) > 0 {
+			input.TagSpecifications = []types.TagSpecification{{
+				ResourceType: types.ResourceTypeSnapshot,
+				Tags:         mapToTags(tags),
+			}}
+		}
+	}
+
+	// CopySnapshot is called in the destination region
+	client := ec2.New(utils.GetEC2Client().Options(), func(o *ec2.Options) {
+		o.Region = destinationRegion
+	})
+
+	result, err := client.CopySnapshot(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(result.SnapshotId), nil
+}
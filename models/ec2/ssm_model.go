@@ -0,0 +1,319 @@
+package ec2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// sessionManagerPlugin is the binary the AWS CLI also uses for interactive sessions
+const sessionManagerPlugin = "session-manager-plugin"
+
+// commandPollInterval is how often command invocations are polled for new output
+const commandPollInterval = 2 * time.Second
+
+// Stream names reported in CommandOutput.Stream
+const (
+	CommandStreamStdout = "stdout"
+	CommandStreamStderr = "stderr"
+	CommandStreamStatus = "status"
+)
+
+// ManagedInstance is an instance registered with Systems Manager
+type ManagedInstance struct {
+	InstanceID   string `json:"instance_id" yaml:"instance_id"`
+	ComputerName string `json:"computer_name" yaml:"computer_name"`
+	PingStatus   string `json:"ping_status" yaml:"ping_status"`
+	Platform     string `json:"platform" yaml:"platform"`
+	AgentVersion string `json:"agent_version" yaml:"agent_version"`
+	IPAddress    string `json:"ip_address" yaml:"ip_address"`
+}
+
+// ManagedInstanceTableHeaders are the column headers matching ManagedInstance.TableRow
+var ManagedInstanceTableHeaders = []string{"Instance ID", "Computer Name", "Ping Status", "Platform", "Agent Version", "IP Address"}
+
+// TableRow returns the managed instance as a table row
+func (i ManagedInstance) TableRow() []string {
+	return []string{i.InstanceID, i.ComputerName, i.PingStatus, i.Platform, i.AgentVersion, i.IPAddress}
+}
+
+// CommandOutput is a line of output, or the final status, of a command on one instance
+type CommandOutput struct {
+	InstanceID string `json:"instance_id"`
+	Stream     string `json:"stream"`
+	Message    string `json:"message"`
+	Color      string `json:"color"`
+}
+
+// FetchManagedInstances lists the instances the SSM agent has registered in the current region
+func FetchManagedInstances() ([]ManagedInstance, error) {
+	ctx := context.TODO()
+	instances := []ManagedInstance{}
+
+	paginator := ssm.NewDescribeInstanceInformationPaginator(utils.GetSSMClient(), &ssm.DescribeInstanceInformationInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, info := range page.InstanceInformationList {
+			platform := string(info.PlatformType)
+			if name := aws.ToString(info.PlatformName); name != "" {
+				platform = name + " " + aws.ToString(info.PlatformVersion)
+			}
+
+			instances = append(instances, ManagedInstance{
+				InstanceID:   aws.ToString(info.InstanceId),
+				ComputerName: aws.ToString(info.ComputerName),
+				PingStatus:   string(info.PingStatus),
+				Platform:     strings.TrimSpace(platform),
+				AgentVersion: aws.ToString(info.AgentVersion),
+				IPAddress:    aws.ToString(info.IPAddress),
+			})
+		}
+	}
+
+	return instances, nil
+}
+
+// StartShellSession opens an interactive Session Manager shell on an instance.
+// The terminal is handed to session-manager-plugin until the session ends.
+func StartShellSession(instanceID string) error {
+	pluginPath, err := exec.LookPath(sessionManagerPlugin)
+	if err != nil {
+		return fmt.Errorf("%s was not found in PATH; install the Session Manager plugin to open shells", sessionManagerPlugin)
+	}
+
+	ctx := context.TODO()
+	input := &ssm.StartSessionInput{Target: aws.String(instanceID)}
+
+	session, err := utils.GetSSMClient().StartSession(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	sessionJSON, err := json.Marshal(map[string]string{
+		"SessionId":  aws.ToString(session.SessionId),
+		"StreamUrl":  aws.ToString(session.StreamUrl),
+		"TokenValue": aws.ToString(session.TokenValue),
+	})
+	if err != nil {
+		return err
+	}
+	requestJSON, err := json.Marshal(map[string]string{"Target": instanceID})
+	if err != nil {
+		return err
+	}
+
+	region := utils.GetCurrentRegion()
+	cmd := exec.Command(pluginPath, string(sessionJSON), region, "StartSession", "",
+		string(requestJSON), "https://ssm."+region+".amazonaws.com")
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// The plugin signs its own requests, so hand it the credentials in use,
+	// including those of an active STS session
+	env, err := credentialsEnv(ctx)
+	if err != nil {
+		return err
+	}
+	cmd.Env = append(os.Environ(), env...)
+
+	if err := cmd.Run(); err != nil {
+		utils.GetSSMClient().TerminateSession(ctx, &ssm.TerminateSessionInput{SessionId: session.SessionId})
+		return fmt.Errorf("session ended with an error: %v", err)
+	}
+	return nil
+}
+
+// credentialsEnv returns the current credentials as AWS_* environment variables
+func credentialsEnv(ctx context.Context) ([]string, error) {
+	creds, err := utils.GetAWSConfig().Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	env := []string{
+		"AWS_ACCESS_KEY_ID=" + creds.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY=" + creds.SecretAccessKey,
+		"AWS_REGION=" + utils.GetCurrentRegion(),
+	}
+	if creds.SessionToken != "" {
+		env = append(env, "AWS_SESSION_TOKEN="+creds.SessionToken)
+	}
+	return env, nil
+}
+
+// RunShellCommand sends AWS-RunShellScript to one or more instances and returns the command ID.
+// A timeoutSeconds of 0 uses the document's default execution timeout.
+func RunShellCommand(instanceIDs []string, commands []string, timeoutSeconds int) (string, error) {
+	if len(instanceIDs) == 0 {
+		return "", errors.New("at least one instance ID is required")
+	}
+	if len(commands) == 0 {
+		return "", errors.New("at least one command is required")
+	}
+
+	parameters := map[string][]string{"commands": commands}
+	if timeoutSeconds > 0 {
+		parameters["executionTimeout"] = []string{fmt.Sprint(timeoutSeconds)}
+	}
+
+	result, err := utils.GetSSMClient().SendCommand(context.TODO(), &ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  instanceIDs,
+		Parameters:   parameters,
+		Comment:      aws.String("Sent by awsmgr"),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(result.Command.CommandId), nil
+}
+
+// commandInstanceIDs returns the instances a command was sent to
+func commandInstanceIDs(ctx context.Context, commandID string) ([]string, error) {
+	result, err := utils.GetSSMClient().ListCommands(ctx, &ssm.ListCommandsInput{CommandId: aws.String(commandID)})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Commands) == 0 {
+		return nil, fmt.Errorf("command %s not found", commandID)
+	}
+	return result.Commands[0].InstanceIds, nil
+}
+
+// StreamCommandOutput polls the invocations of a command and sends new stdout and
+// stderr lines, then a status line per instance once it finishes. A nil error is
+// sent on errChan when every instance has finished.
+func StreamCommandOutput(ctx context.Context, commandID string, outputChan chan<- CommandOutput, errChan chan<- error) {
+	instanceIDs, err := commandInstanceIDs(ctx, commandID)
+	if err != nil {
+		errChan <- err
+		return
+	}
+
+	sentStdout := map[string]int{}
+	sentStderr := map[string]int{}
+	finished := map[string]bool{}
+
+	send := func(output CommandOutput) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case outputChan <- output:
+			return true
+		}
+	}
+
+	for len(finished) < len(instanceIDs) {
+		for _, instanceID := range instanceIDs {
+			if finished[instanceID] {
+				continue
+			}
+
+			invocation, err := utils.GetSSMClient().GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+				CommandId:  aws.String(commandID),
+				InstanceId: aws.String(instanceID),
+			})
+			if err != nil {
+				var notYet *types.InvocationDoesNotExist
+				if errors.As(err, &notYet) {
+					continue // The invocation is registered shortly after SendCommand
+				}
+				if ctx.Err() == nil {
+					errChan <- err
+				}
+				return
+			}
+
+			done := isFinalCommandStatus(invocation.Status)
+
+			stdout := aws.ToString(invocation.StandardOutputContent)
+			for _, line := range newOutputLines(stdout, sentStdout, instanceID, done) {
+				if !send(CommandOutput{InstanceID: instanceID, Stream: CommandStreamStdout, Message: "[" + instanceID + "] " + line, Color: "white"}) {
+					return
+				}
+			}
+
+			stderr := aws.ToString(invocation.StandardErrorContent)
+			for _, line := range newOutputLines(stderr, sentStderr, instanceID, done) {
+				if !send(CommandOutput{InstanceID: instanceID, Stream: CommandStreamStderr, Message: "[" + instanceID + "] " + line, Color: "red"}) {
+					return
+				}
+			}
+
+			if done {
+				finished[instanceID] = true
+				status := string(invocation.Status)
+				color := "red"
+				if invocation.Status == types.CommandInvocationStatusSuccess {
+					color = "green"
+				}
+				message := fmt.Sprintf("[%s] %s (exit code %d)", instanceID, status, invocation.ResponseCode)
+				if !send(CommandOutput{InstanceID: instanceID, Stream: CommandStreamStatus, Message: message, Color: color}) {
+					return
+				}
+			}
+		}
+
+		if len(finished) == len(instanceIDs) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(commandPollInterval):
+		}
+	}
+
+	errChan <- nil
+}
+
+// newOutputLines returns the complete lines of content not sent yet and records them as sent.
+// A trailing partial line is held back until the invocation is done.
+func newOutputLines(content string, sent map[string]int, instanceID string, done bool) []string {
+	if sent[instanceID] >= len(content) {
+		return nil
+	}
+
+	pending := content[sent[instanceID]:]
+	if !done {
+		end := strings.LastIndex(pending, "\n")
+		if end < 0 {
+			return nil
+		}
+		pending = pending[:end+1]
+	}
+	sent[instanceID] += len(pending)
+
+	pending = strings.TrimRight(pending, "\n")
+	if pending == "" {
+		return nil
+	}
+	return strings.Split(pending, "\n")
+}
+
+// isFinalCommandStatus reports whether an invocation will not produce more output
+func isFinalCommandStatus(status types.CommandInvocationStatus) bool {
+	switch status {
+	case types.CommandInvocationStatusPending, types.CommandInvocationStatusInProgress,
+		types.CommandInvocationStatusDelayed, types.CommandInvocationStatusCancelling:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,58 @@
+package ec2
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// tagsToMap converts EC2 tags into a key/value map
+func tagsToMap(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// mapToTags converts a key/value map into EC2 tags, sorted by key
+func mapToTags(tags map[string]string) []types.Tag {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]types.Tag, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return result
+}
+
+// FormatTags renders tags as "key=value" pairs sorted by key, skipping the Name tag
+func FormatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		if key == "Name" {
+			continue
+		}
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// ParseTags parses "key=value" pairs separated by commas
+func ParseTags(input string) map[string]string {
+	tags := map[string]string{}
+	for _, pair := range strings.Split(input, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), "=")
+		if key = strings.TrimSpace(key); key != "" {
+			tags[key] = strings.TrimSpace(value)
+		}
+	}
+	return tags
+}
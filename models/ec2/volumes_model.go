@@ -0,0 +1,186 @@
+package ec2
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// EBSVolume is a single row of the volume listing
+type EBSVolume struct {
+	VolumeID         string            `json:"volume_id" yaml:"volume_id"`
+	Name             string            `json:"name" yaml:"name"`
+	Size             int32             `json:"size_gib" yaml:"size_gib"`
+	VolumeType       string            `json:"volume_type" yaml:"volume_type"`
+	State            string            `json:"state" yaml:"state"`
+	AvailabilityZone string            `json:"availability_zone" yaml:"availability_zone"`
+	Encrypted        bool              `json:"encrypted" yaml:"encrypted"`
+	SnapshotID       string            `json:"snapshot_id" yaml:"snapshot_id"`
+	InstanceID       string            `json:"instance_id" yaml:"instance_id"`
+	Device           string            `json:"device" yaml:"device"`
+	CreateTime       string            `json:"create_time" yaml:"create_time"`
+	Tags             map[string]string `json:"tags" yaml:"tags"`
+}
+
+// EBSVolumeTableHeaders are the column headers matching EBSVolume.TableRow
+var EBSVolumeTableHeaders = []string{"Volume ID", "Name", "Size (GiB)", "Type", "State", "AZ", "Attached To", "Device", "Tags"}
+
+// TableRow returns the volume as a table row
+func (v EBSVolume) TableRow() []string {
+	return []string{v.VolumeID, v.Name, strconv.Itoa(int(v.Size)), v.VolumeType, v.State, v.AvailabilityZone, v.InstanceID, v.Device, FormatTags(v.Tags)}
+}
+
+// CreateVolumeOptions describes a new EBS volume
+type CreateVolumeOptions struct {
+	AvailabilityZone string            `json:"availability_zone"`
+	Size             int32             `json:"size_gib"`
+	VolumeType       string            `json:"volume_type"`
+	SnapshotID       string            `json:"snapshot_id"`
+	Encrypted        bool              `json:"encrypted"`
+	Name             string            `json:"name"`
+	Tags             map[string]string `json:"tags"`
+}
+
+// FetchEBSVolumes lists all volumes in the current region
+func FetchEBSVolumes() ([]EBSVolume, error) {
+	return describeVolumes(&ec2.DescribeVolumesInput{})
+}
+
+func describeVolumes(input *ec2.DescribeVolumesInput) ([]EBSVolume, error) {
+	ctx := context.TODO()
+	volumes := []EBSVolume{}
+
+	paginator := ec2.NewDescribeVolumesPaginator(utils.GetEC2Client(), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, volume := range page.Volumes {
+			volumes = append(volumes, toEBSVolume(volume))
+		}
+	}
+
+	return volumes, nil
+}
+
+func toEBSVolume(volume types.Volume) EBSVolume {
+	tags := tagsToMap(volume.Tags)
+	item := EBSVolume{
+		VolumeID:         aws.ToString(volume.VolumeId),
+		Name:             tags["Name"],
+		Size:             aws.ToInt32(volume.Size),
+		VolumeType:       string(volume.VolumeType),
+		State:            string(volume.State),
+		AvailabilityZone: aws.ToString(volume.AvailabilityZone),
+		Encrypted:        aws.ToBool(volume.Encrypted),
+		SnapshotID:       aws.ToString(volume.SnapshotId),
+		Tags:             tags,
+	}
+	if len(volume.Attachments) > 0 {
+		item.InstanceID = aws.ToString(volume.Attachments[0].InstanceId)
+		item.Device = aws.ToString(volume.Attachments[0].Device)
+	}
+	if volume.CreateTime != nil {
+		item.CreateTime = volume.CreateTime.Format("2006-01-02 15:04:05")
+	}
+	return item
+}
+
+// CreateEBSVolume creates a new volume, empty or from a snapshot
+func CreateEBSVolume(opts CreateVolumeOptions) (*EBSVolume, error) {
+	if opts.AvailabilityZone == "" {
+		return nil, errors.New("availability_zone is required")
+	}
+	if opts.Size < 1 && opts.SnapshotID == "" {
+		return nil, errors.New("size_gib or snapshot_id is required")
+	}
+	if opts.VolumeType == "" {
+		opts.VolumeType = string(types.VolumeTypeGp3)
+	}
+
+	input := &ec2.CreateVolumeInput{
+		AvailabilityZone: aws.String(opts.AvailabilityZone),
+		VolumeType:       types.VolumeType(opts.VolumeType),
+	}
+	if opts.Size > 0 {
+		input.Size = aws.Int32(opts.Size)
+	}
+	if opts.SnapshotID != "" {
+		input.SnapshotId = aws.String(opts.SnapshotID)
+	}
+	if opts.Encrypted {
+		input.Encrypted = aws.Bool(true)
+	}
+
+	tags := withName(opts.Tags, opts.Name)
+	if len(tags) > 0 {
+		input.TagSpecifications = []types.TagSpecification{{
+			ResourceType: types.ResourceTypeVolume,
+			Tags:         mapToTags(tags),
+		}}
+	}
+
+	result, err := utils.GetEC2Client().CreateVolume(context.TODO(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	volume := EBSVolume{
+		VolumeID:         aws.ToString(result.VolumeId),
+		Name:             tags["Name"],
+		Size:             aws.ToInt32(result.Size),
+		VolumeType:       string(result.VolumeType),
+		State:            string(result.State),
+		AvailabilityZone: aws.ToString(result.AvailabilityZone),
+		Encrypted:        aws.ToBool(result.Encrypted),
+		SnapshotID:       aws.ToString(result.SnapshotId),
+		Tags:             tags,
+	}
+	return &volume, nil
+}
+
+// AttachEBSVolume attaches a volume to an instance as the given device (e.g. /dev/sdf)
+func AttachEBSVolume(volumeID, instanceID, device string) error {
+	_, err := utils.GetEC2Client().AttachVolume(context.TODO(), &ec2.AttachVolumeInput{
+		VolumeId:   aws.String(volumeID),
+		InstanceId: aws.String(instanceID),
+		Device:     aws.String(device),
+	})
+	return err
+}
+
+// DetachEBSVolume detaches a volume from its instance
+func DetachEBSVolume(volumeID string, force bool) error {
+	_, err := utils.GetEC2Client().DetachVolume(context.TODO(), &ec2.DetachVolumeInput{
+		VolumeId: aws.String(volumeID),
+		Force:    aws.Bool(force),
+	})
+	return err
+}
+
+// DeleteEBSVolume deletes an unattached volume
+func DeleteEBSVolume(volumeID string) error {
+	_, err := utils.GetEC2Client().DeleteVolume(context.TODO(), &ec2.DeleteVolumeInput{
+		VolumeId: aws.String(volumeID),
+	})
+	return err
+}
+
+// withName returns a copy of tags with the Name tag set, when name is not empty
+func withName(tags map[string]string, name string) map[string]string {
+	result := map[string]string{}
+	for key, value := range tags {
+		result[key] = value
+	}
+	if name != "" {
+		result["Name"] = name
+	}
+	return result
+}
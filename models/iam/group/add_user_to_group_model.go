@@ -10,14 +10,20 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
-func AddUserToGroupModel(username, groupname string) {
-	utils.ShowProcessingAnimation("Adding User to Group")
-
+// AddUserToGroup adds a user to a group without printing anything
+func AddUserToGroup(username, groupname string) error {
 	ctx := context.TODO()
-	_, err := utils.IAMClient.AddUserToGroup(ctx, &iam.AddUserToGroupInput{
+	_, err := utils.GetIAMClient().AddUserToGroup(ctx, &iam.AddUserToGroupInput{
 		UserName:  aws.String(username),
 		GroupName: aws.String(groupname),
 	})
+	return err
+}
+
+func AddUserToGroupModel(username, groupname string) {
+	utils.ShowProcessingAnimation("Adding User to Group")
+
+	err := AddUserToGroup(username, groupname)
 
 	utils.StopAnimation()
 	fmt.Println()
@@ -10,6 +10,7 @@ import (
 type GroupDependencies struct {
 	Users            []string `json:"users"`
 	AttachedPolicies []string `json:"attached_policies"`
+	InlinePolicies   []string `json:"inline_policies"`
 }
 
 func CheckGroupDependencies(groupname string) (*GroupDependencies, error) {
@@ -19,6 +20,7 @@ func CheckGroupDependencies(groupname string) (*GroupDependencies, error) {
 	deps := &GroupDependencies{
 		Users:            []string{},
 		AttachedPolicies: []string{},
+		InlinePolicies:   []string{},
 	}
 
 	// Get group and its users
@@ -52,5 +54,14 @@ func CheckGroupDependencies(groupname string) (*GroupDependencies, error) {
 		}
 	}
 
+	// Get inline policies
+	inlineOutput, err := client.ListGroupPolicies(ctx, &iam.ListGroupPoliciesInput{
+		GroupName: &groupname,
+	})
+	if err != nil {
+		return nil, err
+	}
+	deps.InlinePolicies = append(deps.InlinePolicies, inlineOutput.PolicyNames...)
+
 	return deps, nil
 }
@@ -9,20 +9,25 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
-func CreateIAMGroup(groupname string) {
-	// Start animation in background
-	utils.ShowProcessingAnimation("Creating IAM Group")
-
-	// Get AWS IAM client
+// CreateGroup creates an IAM group without printing anything
+func CreateGroup(groupname string) error {
 	client := utils.GetIAMClient()
 	ctx := context.TODO()
 
-	// Create group using AWS SDK
 	input := &iam.CreateGroupInput{
 		GroupName: &groupname,
 	}
 
 	_, err := client.CreateGroup(ctx, input)
+	return err
+}
+
+func CreateIAMGroup(groupname string) {
+	// Start animation in background
+	utils.ShowProcessingAnimation("Creating IAM Group")
+
+	// Create group using AWS SDK
+	err := CreateGroup(groupname)
 
 	// Stop animation and print a newline
 	utils.StopAnimation()
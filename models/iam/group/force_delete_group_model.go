@@ -30,6 +30,18 @@ func ForceDeleteGroup(groupname string) error {
 		}
 	}
 
+	// Delete inline policies
+	for _, policyName := range deps.InlinePolicies {
+		deleteInput := &iam.DeleteGroupPolicyInput{
+			GroupName:  &groupname,
+			PolicyName: &policyName,
+		}
+		_, err := client.DeleteGroupPolicy(ctx, deleteInput)
+		if err != nil {
+			return fmt.Errorf("failed to delete inline policy %s: %w", policyName, err)
+		}
+	}
+
 	// Remove all users from group
 	for _, username := range deps.Users {
 		removeUserInput := &iam.RemoveUserFromGroupInput{
@@ -2,43 +2,55 @@ package group
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
-func FetchIAMGroups() (string, error) {
-	client := utils.GetIAMClient()
-	ctx := context.TODO()
+// IAMGroup is a single row of the IAM group listing
+type IAMGroup struct {
+	GroupName  string `json:"groupname" yaml:"groupname"`
+	GroupID    string `json:"group_id" yaml:"group_id"`
+	CreateDate string `json:"create_date" yaml:"create_date"`
+}
 
-	input := &iam.ListGroupsInput{}
-	result, err := client.ListGroups(ctx, input)
-	if err != nil {
-		return "", err
-	}
+// IAMGroupTableHeaders are the column headers matching IAMGroup.TableRow
+var IAMGroupTableHeaders = []string{"Group Name", "Group ID", "Created At"}
 
-	var output strings.Builder
-	for _, group := range result.Groups {
-		groupName := ""
-		groupID := ""
-		createDate := ""
+// TableRow returns the group as a table row
+func (g IAMGroup) TableRow() []string {
+	return []string{g.GroupName, g.GroupID, g.CreateDate}
+}
 
-		if group.GroupName != nil {
-			groupName = *group.GroupName
-		}
-		if group.GroupId != nil {
-			groupID = *group.GroupId
-		}
-		if group.CreateDate != nil {
-			createDate = group.CreateDate.Format("2006-01-02T15:04:05Z")
+// FetchIAMGroups lists all IAM groups in the account
+func FetchIAMGroups() ([]IAMGroup, error) {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+	groups := []IAMGroup{}
+
+	paginator := iam.NewListGroupsPaginator(client, &iam.ListGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
 		}
 
-		output.WriteString(fmt.Sprintf("%s\t%s\t%s\n", groupName, groupID, createDate))
+		for _, group := range page.Groups {
+			item := IAMGroup{}
+			if group.GroupName != nil {
+				item.GroupName = *group.GroupName
+			}
+			if group.GroupId != nil {
+				item.GroupID = *group.GroupId
+			}
+			if group.CreateDate != nil {
+				item.CreateDate = group.CreateDate.Format("2006-01-02T15:04:05Z")
+			}
+			groups = append(groups, item)
+		}
 	}
 
-	return output.String(), nil
+	return groups, nil
 }
 
 func FetchOnlyGroupNames() []string {
@@ -9,9 +9,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
-func RemoveUserFromGroupModel(username, groupname string) {
-	utils.ShowProcessingAnimation("Removing user '" + username + "' from group '" + groupname + "'")
-
+// RemoveUserFromGroup removes a user from a group without printing anything
+func RemoveUserFromGroup(username, groupname string) error {
 	client := utils.GetIAMClient()
 	ctx := context.TODO()
 
@@ -21,6 +20,13 @@ func RemoveUserFromGroupModel(username, groupname string) {
 	}
 
 	_, err := client.RemoveUserFromGroup(ctx, input)
+	return err
+}
+
+func RemoveUserFromGroupModel(username, groupname string) {
+	utils.ShowProcessingAnimation("Removing user '" + username + "' from group '" + groupname + "'")
+
+	err := RemoveUserFromGroup(username, groupname)
 	utils.StopAnimation()
 
 	if err != nil {
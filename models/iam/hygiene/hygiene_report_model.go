@@ -0,0 +1,447 @@
+package hygiene
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/group"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/role"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// Finding categories
+const (
+	CategoryInactiveUser     = "inactive-user"
+	CategoryEmptyGroup       = "empty-group"
+	CategoryUnattachedPolicy = "unattached-policy"
+	CategoryUnusedService    = "unused-service"
+)
+
+// DefaultInactiveDays is used when no inactivity threshold is given
+const DefaultInactiveDays = 90
+
+const (
+	serviceAccessJobTimeout   = 2 * time.Minute
+	serviceAccessPollInterval = 2 * time.Second
+	serviceAccessWorkers      = 5
+)
+
+// Options controls what the hygiene report looks at
+type Options struct {
+	InactiveDays    int  `json:"inactive_days"`
+	IncludeServices bool `json:"include_services"` // Runs a slow last-accessed job per user and role
+}
+
+// Finding is one item of the hygiene report. Target is what an apply step acts on: a user
+// or group name, or a policy ARN.
+type Finding struct {
+	Category     string     `json:"category" yaml:"category"`
+	Target       string     `json:"target" yaml:"target"`
+	Detail       string     `json:"detail" yaml:"detail"`
+	LastActivity *time.Time `json:"last_activity" yaml:"last_activity"`
+	Removable    bool       `json:"removable" yaml:"removable"`
+}
+
+// FindingTableHeaders are the column headers matching Finding.TableRow
+var FindingTableHeaders = []string{"Category", "Target", "Detail", "Last Activity"}
+
+// TableRow returns the finding as a table row
+func (f Finding) TableRow() []string {
+	last := "never"
+	if f.LastActivity != nil {
+		last = f.LastActivity.Local().Format("2006-01-02")
+	}
+	return []string{f.Category, f.Target, f.Detail, last}
+}
+
+// Report is the result of a hygiene scan. Checks that failed are listed in Errors and do
+// not stop the others.
+type Report struct {
+	GeneratedAt  time.Time `json:"generated_at"`
+	InactiveDays int       `json:"inactive_days"`
+	Findings     []Finding `json:"findings"`
+	Errors       []string  `json:"errors,omitempty"`
+}
+
+// Generate scans IAM for inactive users, groups without members, customer-managed policies
+// attached to nothing and, optionally, services granted to users and roles but never used
+func Generate(options Options) *Report {
+	if options.InactiveDays <= 0 {
+		options.InactiveDays = DefaultInactiveDays
+	}
+
+	report := &Report{
+		GeneratedAt:  time.Now(),
+		InactiveDays: options.InactiveDays,
+		Findings:     []Finding{},
+	}
+
+	checks := []struct {
+		name string
+		run  func() ([]Finding, error)
+	}{
+		{"inactive users", func() ([]Finding, error) { return findInactiveUsers(options.InactiveDays) }},
+		{"empty groups", findEmptyGroups},
+		{"unattached policies", findUnattachedPolicies},
+	}
+	if options.IncludeServices {
+		checks = append(checks, struct {
+			name string
+			run  func() ([]Finding, error)
+		}{"unused services", findUnusedServices})
+	}
+
+	for _, check := range checks {
+		findings, err := check.run()
+		if err != nil {
+			report.Errors = append(report.Errors, check.name+": "+err.Error())
+		}
+		report.Findings = append(report.Findings, findings...)
+	}
+
+	return report
+}
+
+// Count returns the number of findings per category
+func (r *Report) Count() map[string]int {
+	counts := map[string]int{}
+	for _, f := range r.Findings {
+		counts[f.Category]++
+	}
+	return counts
+}
+
+// findInactiveUsers uses the credential report, which carries the last console sign-in and
+// the last use of both access keys for every user in one call
+func findInactiveUsers(days int) ([]Finding, error) {
+	rows, _, err := user.FetchCredentialReport()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	findings := []Finding{}
+	for _, row := range rows {
+		if row.User == "<root_account>" {
+			continue
+		}
+
+		last := latest(row.PasswordLastUsed, row.AccessKey1.LastUsedDate, row.AccessKey2.LastUsedDate)
+		reference := last
+		if reference == nil {
+			reference = row.UserCreationTime
+		}
+		if reference == nil || reference.After(cutoff) {
+			continue
+		}
+
+		detail := fmt.Sprintf("no sign-in or key use for %d days", int(time.Since(*reference).Hours()/24))
+		if last == nil {
+			detail = fmt.Sprintf("never used, created %d days ago", int(time.Since(*reference).Hours()/24))
+		}
+		findings = append(findings, Finding{
+			Category:     CategoryInactiveUser,
+			Target:       row.User,
+			Detail:       detail,
+			LastActivity: last,
+			Removable:    true,
+		})
+	}
+
+	return findings, nil
+}
+
+func findEmptyGroups() ([]Finding, error) {
+	groups, err := group.FetchIAMGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	client := utils.GetIAMClient()
+	findings := []Finding{}
+	for _, g := range groups {
+		result, err := client.GetGroup(context.TODO(), &iam.GetGroupInput{
+			GroupName: aws.String(g.GroupName),
+			MaxItems:  aws.Int32(1),
+		})
+		if err != nil {
+			return findings, err
+		}
+		if len(result.Users) == 0 {
+			findings = append(findings, Finding{
+				Category:  CategoryEmptyGroup,
+				Target:    g.GroupName,
+				Detail:    "group has no members",
+				Removable: true,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+func findUnattachedPolicies() ([]Finding, error) {
+	policies, err := policy.ListPoliciesModel("Local")
+	if err != nil {
+		return nil, err
+	}
+
+	findings := []Finding{}
+	for _, p := range policies {
+		if p.AttachmentCount == 0 && p.PermissionsBoundaryUsageCount == 0 {
+			findings = append(findings, Finding{
+				Category:  CategoryUnattachedPolicy,
+				Target:    p.PolicyArn,
+				Detail:    p.PolicyName + " is not attached to any user, group or role",
+				Removable: true,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// findUnusedServices runs a service last-accessed job for every user and role and reports
+// the services their policies grant that were never used in the IAM tracking period
+func findUnusedServices() ([]Finding, error) {
+	type principal struct{ kind, name, arn string }
+	var principals []principal
+
+	users, err := user.FetchIAMUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		principals = append(principals, principal{"user", u.UserName, u.Arn})
+	}
+
+	roles, err := role.FetchIAMRoles()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range roles {
+		// Service-linked roles are managed by AWS and cannot be narrowed
+		if strings.HasPrefix(r.Path, "/aws-service-role/") {
+			continue
+		}
+		principals = append(principals, principal{"role", r.RoleName, r.Arn})
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		findings = []Finding{}
+		failures []string
+		jobs     = make(chan principal)
+	)
+	for i := 0; i < serviceAccessWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				services, err := fetchNeverAccessedServices(p.arn)
+
+				mu.Lock()
+				if err != nil {
+					failures = append(failures, p.kind+" "+p.name+": "+err.Error())
+				}
+				for _, service := range services {
+					findings = append(findings, Finding{
+						Category: CategoryUnusedService,
+						Target:   p.kind + "/" + p.name,
+						Detail:   service + " granted but never accessed",
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, p := range principals {
+		jobs <- p
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Target != findings[j].Target {
+			return findings[i].Target < findings[j].Target
+		}
+		return findings[i].Detail < findings[j].Detail
+	})
+
+	if len(failures) > 0 {
+		sort.Strings(failures)
+		return findings, fmt.Errorf("%d principal(s) could not be analysed: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return findings, nil
+}
+
+// fetchNeverAccessedServices returns the names of the services granted to an identity that it
+// has never authenticated to
+func fetchNeverAccessedServices(arn string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), serviceAccessJobTimeout)
+	defer cancel()
+	client := utils.GetIAMClient()
+
+	job, err := client.GenerateServiceLastAccessedDetails(ctx, &iam.GenerateServiceLastAccessedDetailsInput{
+		Arn: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []string
+	var marker *string
+	for {
+		result, err := client.GetServiceLastAccessedDetails(ctx, &iam.GetServiceLastAccessedDetailsInput{
+			JobId:  job.JobId,
+			Marker: marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		switch result.JobStatus {
+		case types.JobStatusTypeFailed:
+			if result.Error != nil {
+				return nil, fmt.Errorf("service last-accessed job failed: %s", aws.ToString(result.Error.Message))
+			}
+			return nil, fmt.Errorf("service last-accessed job failed")
+		case types.JobStatusTypeInProgress:
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("timed out waiting for the service last-accessed job")
+			case <-time.After(serviceAccessPollInterval):
+			}
+			continue
+		}
+
+		for _, service := range result.ServicesLastAccessed {
+			if service.LastAuthenticated == nil {
+				services = append(services, aws.ToString(service.ServiceName))
+			}
+		}
+		if !result.IsTruncated {
+			return services, nil
+		}
+		marker = result.Marker
+	}
+}
+
+// Apply removes the target of a finding once it has been re-checked against the current IAM
+// data, so a stale or edited finding cannot delete something in use: inactive users must
+// still be inactive for inactiveDays and are torn down like a forced user deletion, empty
+// groups must still have no members and are force-deleted with their attached and inline
+// policies, and unattached policies must still be attached to nothing and are deleted with
+// their versions. Unused services need a policy edit and are not applied.
+func Apply(f Finding, inactiveDays int) []user.UserStepResult {
+	if inactiveDays <= 0 {
+		inactiveDays = DefaultInactiveDays
+	}
+
+	switch f.Category {
+	case CategoryInactiveUser:
+		if err := checkUserInactive(f.Target, inactiveDays); err != nil {
+			return []user.UserStepResult{stepResult("check user", f.Target, err)}
+		}
+		steps, err := user.DeleteIAMUserAPI(f.Target)
+		if steps == nil && err != nil {
+			return []user.UserStepResult{stepResult("delete user", f.Target, err)}
+		}
+		return steps
+	case CategoryEmptyGroup:
+		if err := checkGroupEmpty(f.Target); err != nil {
+			return []user.UserStepResult{stepResult("check group", f.Target, err)}
+		}
+		return []user.UserStepResult{stepResult("delete group", f.Target, group.ForceDeleteGroup(f.Target))}
+	case CategoryUnattachedPolicy:
+		if err := checkPolicyUnattached(f.Target); err != nil {
+			return []user.UserStepResult{stepResult("check policy", f.Target, err)}
+		}
+		return []user.UserStepResult{stepResult("delete policy", f.Target, policy.DeleteManagedPolicy(f.Target, false))}
+	}
+	return []user.UserStepResult{stepResult("apply", f.Target, fmt.Errorf("%s findings must be fixed by editing the policy", f.Category))}
+}
+
+// checkUserInactive fails when the user signed in or used an access key within the last days
+// days, or was created within them and never used
+func checkUserInactive(username string, days int) error {
+	result, err := utils.GetIAMClient().GetUser(context.TODO(), &iam.GetUserInput{UserName: aws.String(username)})
+	if err != nil {
+		return err
+	}
+	keys, err := user.FetchAccessKeys(username)
+	if err != nil {
+		return err
+	}
+
+	times := []*time.Time{result.User.PasswordLastUsed}
+	for _, key := range keys {
+		times = append(times, key.LastUsedDate)
+	}
+	reference := latest(times...)
+	if reference == nil {
+		reference = result.User.CreateDate
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	if reference != nil && reference.After(cutoff) {
+		return fmt.Errorf("user was active on %s, within the last %d days", reference.Local().Format("2006-01-02"), days)
+	}
+	return nil
+}
+
+func checkGroupEmpty(groupname string) error {
+	result, err := utils.GetIAMClient().GetGroup(context.TODO(), &iam.GetGroupInput{
+		GroupName: aws.String(groupname),
+		MaxItems:  aws.Int32(1),
+	})
+	if err != nil {
+		return err
+	}
+	if len(result.Users) > 0 {
+		return fmt.Errorf("group has members")
+	}
+	return nil
+}
+
+func checkPolicyUnattached(policyArn string) error {
+	result, err := utils.GetIAMClient().GetPolicy(context.TODO(), &iam.GetPolicyInput{PolicyArn: aws.String(policyArn)})
+	if err != nil {
+		return err
+	}
+	if attached := aws.ToInt32(result.Policy.AttachmentCount); attached > 0 {
+		return fmt.Errorf("policy is attached to %d user(s), group(s) or role(s)", attached)
+	}
+	if boundaries := aws.ToInt32(result.Policy.PermissionsBoundaryUsageCount); boundaries > 0 {
+		return fmt.Errorf("policy is used as the permissions boundary of %d user(s) or role(s)", boundaries)
+	}
+	return nil
+}
+
+func stepResult(action, resource string, err error) user.UserStepResult {
+	step := user.UserStepResult{Action: action, Resource: resource, Success: err == nil}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	return step
+}
+
+func latest(times ...*time.Time) *time.Time {
+	var last *time.Time
+	for _, t := range times {
+		if t != nil && (last == nil || t.After(*last)) {
+			last = t
+		}
+	}
+	return last
+}
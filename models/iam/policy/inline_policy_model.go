@@ -0,0 +1,176 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// Identity types that can carry inline policies
+const (
+	IdentityUser  = "user"
+	IdentityGroup = "group"
+	IdentityRole  = "role"
+)
+
+// InlinePolicy is an inline policy embedded in a user, group or role
+type InlinePolicy struct {
+	IdentityType string `json:"identity_type" yaml:"identity_type"`
+	IdentityName string `json:"identity_name" yaml:"identity_name"`
+	PolicyName   string `json:"policy_name" yaml:"policy_name"`
+	Document     string `json:"document" yaml:"document"`
+}
+
+// ValidateIdentityType checks that identityType is one of IdentityUser, IdentityGroup or IdentityRole
+func ValidateIdentityType(identityType string) error {
+	switch identityType {
+	case IdentityUser, IdentityGroup, IdentityRole:
+		return nil
+	}
+	return fmt.Errorf("unknown identity type '%s' (use user, group or role)", identityType)
+}
+
+// ListInlinePolicies returns the names of the inline policies of a user, group or role
+func ListInlinePolicies(identityType, identityName string) ([]string, error) {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	client := utils.GetIAMClient()
+	names := []string{}
+
+	switch identityType {
+	case IdentityUser:
+		paginator := iam.NewListUserPoliciesPaginator(client, &iam.ListUserPoliciesInput{UserName: aws.String(identityName)})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, page.PolicyNames...)
+		}
+	case IdentityGroup:
+		paginator := iam.NewListGroupPoliciesPaginator(client, &iam.ListGroupPoliciesInput{GroupName: aws.String(identityName)})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, page.PolicyNames...)
+		}
+	case IdentityRole:
+		paginator := iam.NewListRolePoliciesPaginator(client, &iam.ListRolePoliciesInput{RoleName: aws.String(identityName)})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, page.PolicyNames...)
+		}
+	}
+
+	return names, nil
+}
+
+// GetInlinePolicy returns an inline policy with its document as indented JSON
+func GetInlinePolicy(identityType, identityName, policyName string) (*InlinePolicy, error) {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return nil, err
+	}
+
+	ctx := context.TODO()
+	client := utils.GetIAMClient()
+	var document string
+
+	switch identityType {
+	case IdentityUser:
+		result, err := client.GetUserPolicy(ctx, &iam.GetUserPolicyInput{UserName: aws.String(identityName), PolicyName: aws.String(policyName)})
+		if err != nil {
+			return nil, err
+		}
+		document = aws.ToString(result.PolicyDocument)
+	case IdentityGroup:
+		result, err := client.GetGroupPolicy(ctx, &iam.GetGroupPolicyInput{GroupName: aws.String(identityName), PolicyName: aws.String(policyName)})
+		if err != nil {
+			return nil, err
+		}
+		document = aws.ToString(result.PolicyDocument)
+	case IdentityRole:
+		result, err := client.GetRolePolicy(ctx, &iam.GetRolePolicyInput{RoleName: aws.String(identityName), PolicyName: aws.String(policyName)})
+		if err != nil {
+			return nil, err
+		}
+		document = aws.ToString(result.PolicyDocument)
+	}
+
+	return &InlinePolicy{
+		IdentityType: identityType,
+		IdentityName: identityName,
+		PolicyName:   policyName,
+		Document:     FormatPolicyDocument(document),
+	}, nil
+}
+
+// PutInlinePolicy creates or replaces an inline policy after validating its document
+func PutInlinePolicy(identityType, identityName, policyName, document string) error {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return err
+	}
+	if policyName == "" {
+		return fmt.Errorf("policy name is required")
+	}
+	if err := ValidatePolicyDocument(document); err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	client := utils.GetIAMClient()
+	var err error
+
+	switch identityType {
+	case IdentityUser:
+		_, err = client.PutUserPolicy(ctx, &iam.PutUserPolicyInput{
+			UserName:       aws.String(identityName),
+			PolicyName:     aws.String(policyName),
+			PolicyDocument: aws.String(document),
+		})
+	case IdentityGroup:
+		_, err = client.PutGroupPolicy(ctx, &iam.PutGroupPolicyInput{
+			GroupName:      aws.String(identityName),
+			PolicyName:     aws.String(policyName),
+			PolicyDocument: aws.String(document),
+		})
+	case IdentityRole:
+		_, err = client.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+			RoleName:       aws.String(identityName),
+			PolicyName:     aws.String(policyName),
+			PolicyDocument: aws.String(document),
+		})
+	}
+	return err
+}
+
+// DeleteInlinePolicy deletes an inline policy from a user, group or role
+func DeleteInlinePolicy(identityType, identityName, policyName string) error {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return err
+	}
+
+	ctx := context.TODO()
+	client := utils.GetIAMClient()
+	var err error
+
+	switch identityType {
+	case IdentityUser:
+		_, err = client.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{UserName: aws.String(identityName), PolicyName: aws.String(policyName)})
+	case IdentityGroup:
+		_, err = client.DeleteGroupPolicy(ctx, &iam.DeleteGroupPolicyInput{GroupName: aws.String(identityName), PolicyName: aws.String(policyName)})
+	case IdentityRole:
+		_, err = client.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{RoleName: aws.String(identityName), PolicyName: aws.String(policyName)})
+	}
+	return err
+}
@@ -2,18 +2,36 @@ package policy
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 )
 
+// ErrPolicyNotFound is returned when a policy name matches no managed policy
+var ErrPolicyNotFound = errors.New("no managed policy named")
+
 type Policy struct {
 	PolicyName   string `json:"policy_name"`
 	PolicyArn    string `json:"policy_arn"`
 	Path         string `json:"path"`
 	CreateDate   string `json:"create_date"`
 	IsAWSManaged bool   `json:"is_aws_managed"`
+
+	AttachmentCount               int32 `json:"attachment_count"`
+	PermissionsBoundaryUsageCount int32 `json:"permissions_boundary_usage_count"`
+}
+
+// PolicyTableHeaders are the column headers matching Policy.TableRow
+var PolicyTableHeaders = []string{"Policy Name", "Path", "Created", "ARN"}
+
+// TableRow returns the policy as a table row
+func (p Policy) TableRow() []string {
+	return []string{p.PolicyName, p.Path, p.CreateDate, p.PolicyArn}
 }
 
 // ListPoliciesModel lists all IAM policies (both AWS managed and customer managed)
@@ -55,6 +73,12 @@ func ListPoliciesModel(scope string) ([]Policy, error) {
 				Path:         *p.Path,
 				IsAWSManaged: len(arn) >= 17 && arn[:17] == "arn:aws:iam::aws:",
 			}
+			if p.AttachmentCount != nil {
+				policy.AttachmentCount = *p.AttachmentCount
+			}
+			if p.PermissionsBoundaryUsageCount != nil {
+				policy.PermissionsBoundaryUsageCount = *p.PermissionsBoundaryUsageCount
+			}
 
 			if p.CreateDate != nil {
 				policy.CreateDate = p.CreateDate.Format("2006-01-02 15:04:05")
@@ -71,3 +95,56 @@ func ListPoliciesModel(scope string) ([]Policy, error) {
 
 	return policies, nil
 }
+
+// FindManagedPolicyArn looks up a managed policy by name, customer-managed policies first
+func FindManagedPolicyArn(name string) (string, error) {
+	arns, err := ResolvePolicyArns([]string{name})
+	if err != nil {
+		return "", err
+	}
+	return arns[name], nil
+}
+
+// ResolvePolicyArns maps policy references, given as ARNs or names, to ARNs. Names are looked up
+// with a single policy listing, customer-managed policies taking precedence over AWS ones.
+func ResolvePolicyArns(refs []string) (map[string]string, error) {
+	arns := map[string]string{}
+	var names []string
+	for _, ref := range refs {
+		if strings.HasPrefix(ref, "arn:") {
+			arns[ref] = ref
+		} else {
+			names = append(names, ref)
+		}
+	}
+	if len(names) == 0 {
+		return arns, nil
+	}
+
+	policies, err := ListPoliciesModel("All")
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]string{}
+	for _, p := range policies {
+		if _, seen := byName[p.PolicyName]; !seen || !p.IsAWSManaged {
+			byName[p.PolicyName] = p.PolicyArn
+		}
+	}
+
+	var missing []string
+	for _, name := range names {
+		arn, ok := byName[name]
+		if !ok {
+			missing = append(missing, "'"+name+"'")
+			continue
+		}
+		arns[name] = arn
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("%w %s", ErrPolicyNotFound, strings.Join(missing, ", "))
+	}
+
+	return arns, nil
+}
@@ -0,0 +1,291 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// maxPolicyVersions is the IAM limit on stored versions of a managed policy
+const maxPolicyVersions = 5
+
+// Errors returned when an operation needs the caller's permission to go further
+var (
+	ErrPolicyVersionLimit = errors.New("policy has reached the limit of 5 versions")
+	ErrPolicyAttached     = errors.New("policy is still attached")
+	ErrAWSManagedPolicy   = errors.New("AWS managed policies cannot be deleted")
+)
+
+// PolicyVersion is one stored version of a managed policy
+type PolicyVersion struct {
+	VersionID  string    `json:"version_id" yaml:"version_id"`
+	IsDefault  bool      `json:"is_default" yaml:"is_default"`
+	CreateDate time.Time `json:"create_date" yaml:"create_date"`
+}
+
+// PolicyVersionTableHeaders are the column headers matching PolicyVersion.TableRow
+var PolicyVersionTableHeaders = []string{"Version", "Default", "Created"}
+
+// TableRow returns the version as a table row
+func (v PolicyVersion) TableRow() []string {
+	isDefault := ""
+	if v.IsDefault {
+		isDefault = "✓"
+	}
+	return []string{v.VersionID, isDefault, v.CreateDate.Local().Format("2006-01-02 15:04:05")}
+}
+
+// CreateManagedPolicy creates a customer-managed policy from a JSON document
+func CreateManagedPolicy(name, path, description, document string) (*Policy, error) {
+	if err := ValidatePolicyDocument(document); err != nil {
+		return nil, err
+	}
+
+	input := &iam.CreatePolicyInput{
+		PolicyName:     aws.String(name),
+		PolicyDocument: aws.String(document),
+	}
+	if path != "" {
+		input.Path = aws.String(path)
+	}
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+
+	result, err := utils.GetIAMClient().CreatePolicy(context.TODO(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Policy{
+		PolicyName: aws.ToString(result.Policy.PolicyName),
+		PolicyArn:  aws.ToString(result.Policy.Arn),
+		Path:       aws.ToString(result.Policy.Path),
+		CreateDate: aws.ToTime(result.Policy.CreateDate).Format("2006-01-02 15:04:05"),
+	}, nil
+}
+
+// FetchPolicyVersions lists the stored versions of a managed policy, newest first
+func FetchPolicyVersions(policyArn string) ([]PolicyVersion, error) {
+	result, err := utils.GetIAMClient().ListPolicyVersions(context.TODO(), &iam.ListPolicyVersionsInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]PolicyVersion, 0, len(result.Versions))
+	for _, v := range result.Versions {
+		versions = append(versions, PolicyVersion{
+			VersionID:  aws.ToString(v.VersionId),
+			IsDefault:  v.IsDefaultVersion,
+			CreateDate: aws.ToTime(v.CreateDate),
+		})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].CreateDate.After(versions[j].CreateDate) })
+
+	return versions, nil
+}
+
+// FetchPolicyDocument returns a version of a managed policy as indented JSON.
+// An empty versionID returns the default version.
+func FetchPolicyDocument(policyArn, versionID string) (string, error) {
+	ctx := context.TODO()
+	client := utils.GetIAMClient()
+
+	if versionID == "" {
+		result, err := client.GetPolicy(ctx, &iam.GetPolicyInput{PolicyArn: aws.String(policyArn)})
+		if err != nil {
+			return "", err
+		}
+		versionID = aws.ToString(result.Policy.DefaultVersionId)
+	}
+
+	result, err := client.GetPolicyVersion(ctx, &iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: aws.String(versionID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return FormatPolicyDocument(aws.ToString(result.PolicyVersion.Document)), nil
+}
+
+// UpdateManagedPolicy stores document as the new default version of a managed policy.
+// IAM keeps five versions; when the limit is reached the oldest non-default version is
+// deleted if pruneOldest is set, otherwise an error is returned. The pruned version ID
+// is returned alongside the new version.
+func UpdateManagedPolicy(policyArn, document string, pruneOldest bool) (*PolicyVersion, string, error) {
+	if err := ValidatePolicyDocument(document); err != nil {
+		return nil, "", err
+	}
+
+	versions, err := FetchPolicyVersions(policyArn)
+	if err != nil {
+		return nil, "", err
+	}
+
+	pruned := ""
+	if len(versions) >= maxPolicyVersions {
+		oldest := oldestNonDefaultVersion(versions)
+		if !pruneOldest || oldest == "" {
+			return nil, "", fmt.Errorf("%w; delete a version first", ErrPolicyVersionLimit)
+		}
+		if err := DeletePolicyVersion(policyArn, oldest); err != nil {
+			return nil, "", fmt.Errorf("failed to delete oldest version %s: %v", oldest, err)
+		}
+		pruned = oldest
+	}
+
+	result, err := utils.GetIAMClient().CreatePolicyVersion(context.TODO(), &iam.CreatePolicyVersionInput{
+		PolicyArn:      aws.String(policyArn),
+		PolicyDocument: aws.String(document),
+		SetAsDefault:   true,
+	})
+	if err != nil {
+		return nil, pruned, err
+	}
+
+	return &PolicyVersion{
+		VersionID:  aws.ToString(result.PolicyVersion.VersionId),
+		IsDefault:  result.PolicyVersion.IsDefaultVersion,
+		CreateDate: aws.ToTime(result.PolicyVersion.CreateDate),
+	}, pruned, nil
+}
+
+func oldestNonDefaultVersion(versions []PolicyVersion) string {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !versions[i].IsDefault {
+			return versions[i].VersionID
+		}
+	}
+	return ""
+}
+
+// SetDefaultPolicyVersion makes a stored version the default, for example to roll back a change
+func SetDefaultPolicyVersion(policyArn, versionID string) error {
+	_, err := utils.GetIAMClient().SetDefaultPolicyVersion(context.TODO(), &iam.SetDefaultPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: aws.String(versionID),
+	})
+	return err
+}
+
+// DeletePolicyVersion deletes a non-default version of a managed policy
+func DeletePolicyVersion(policyArn, versionID string) error {
+	_, err := utils.GetIAMClient().DeletePolicyVersion(context.TODO(), &iam.DeletePolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: aws.String(versionID),
+	})
+	return err
+}
+
+// DiffPolicyVersions returns a unified diff between two versions of a managed policy.
+// An empty result means the documents are identical.
+func DiffPolicyVersions(policyArn, fromVersion, toVersion string) (string, error) {
+	from, err := FetchPolicyDocument(policyArn, fromVersion)
+	if err != nil {
+		return "", err
+	}
+	to, err := FetchPolicyDocument(policyArn, toVersion)
+	if err != nil {
+		return "", err
+	}
+	return DiffPolicyDocuments(from, to, fromVersion, toVersion)
+}
+
+// DiffPolicyDocuments returns a unified diff between two policy documents
+func DiffPolicyDocuments(from, to, fromLabel, toLabel string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(FormatPolicyDocument(from) + "\n"),
+		B:        difflib.SplitLines(FormatPolicyDocument(to) + "\n"),
+		FromFile: fromLabel,
+		ToFile:   toLabel,
+		Context:  3,
+	})
+}
+
+// DeleteManagedPolicy deletes a customer-managed policy and its non-default versions.
+// A policy still attached to users, groups or roles is only deleted when force is set,
+// in which case it is detached from all of them first. AWS managed policies are refused
+// before anything is detached.
+func DeleteManagedPolicy(policyArn string, force bool) error {
+	if isAWSManagedPolicyArn(policyArn) {
+		return fmt.Errorf("%w: %s", ErrAWSManagedPolicy, policyArn)
+	}
+
+	ctx := context.TODO()
+	client := utils.GetIAMClient()
+
+	entities := &iam.ListEntitiesForPolicyOutput{}
+	paginator := iam.NewListEntitiesForPolicyPaginator(client, &iam.ListEntitiesForPolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		entities.PolicyUsers = append(entities.PolicyUsers, page.PolicyUsers...)
+		entities.PolicyGroups = append(entities.PolicyGroups, page.PolicyGroups...)
+		entities.PolicyRoles = append(entities.PolicyRoles, page.PolicyRoles...)
+	}
+
+	attached := len(entities.PolicyUsers) + len(entities.PolicyGroups) + len(entities.PolicyRoles)
+	if attached > 0 && !force {
+		return fmt.Errorf("%w to %d user(s), group(s) or role(s); detach it first or force the deletion", ErrPolicyAttached, attached)
+	}
+
+	var failures []string
+	for _, u := range entities.PolicyUsers {
+		if err := DetachUserPolicy(aws.ToString(u.UserName), policyArn); err != nil {
+			failures = append(failures, "user "+aws.ToString(u.UserName)+": "+err.Error())
+		}
+	}
+	for _, g := range entities.PolicyGroups {
+		_, err := client.DetachGroupPolicy(ctx, &iam.DetachGroupPolicyInput{GroupName: g.GroupName, PolicyArn: aws.String(policyArn)})
+		if err != nil {
+			failures = append(failures, "group "+aws.ToString(g.GroupName)+": "+err.Error())
+		}
+	}
+	for _, r := range entities.PolicyRoles {
+		_, err := client.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: r.RoleName, PolicyArn: aws.String(policyArn)})
+		if err != nil {
+			failures = append(failures, "role "+aws.ToString(r.RoleName)+": "+err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New("failed to detach policy from " + strings.Join(failures, "; "))
+	}
+
+	versions, err := FetchPolicyVersions(policyArn)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if !v.IsDefault {
+			if err := DeletePolicyVersion(policyArn, v.VersionID); err != nil {
+				return fmt.Errorf("failed to delete version %s: %v", v.VersionID, err)
+			}
+		}
+	}
+
+	_, err = client.DeletePolicy(ctx, &iam.DeletePolicyInput{PolicyArn: aws.String(policyArn)})
+	return err
+}
+
+// isAWSManagedPolicyArn reports whether the ARN names a policy owned by AWS, whose account
+// field is "aws" in every partition (arn:aws:iam::aws:policy/..., arn:aws-cn:iam::aws:...)
+func isAWSManagedPolicyArn(policyArn string) bool {
+	fields := strings.SplitN(policyArn, ":", 6)
+	return len(fields) == 6 && fields[0] == "arn" && fields[2] == "iam" && fields[4] == "aws"
+}
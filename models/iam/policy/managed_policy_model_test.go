@@ -0,0 +1,29 @@
+package policy
+
+import "testing"
+
+func TestIsAWSManagedPolicyArn(t *testing.T) {
+	tests := []struct {
+		name string
+		arn  string
+		want bool
+	}{
+		{"AWS managed", "arn:aws:iam::aws:policy/AdministratorAccess", true},
+		{"AWS managed with a path", "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole", true},
+		{"AWS managed in China", "arn:aws-cn:iam::aws:policy/ReadOnlyAccess", true},
+		{"AWS managed in GovCloud", "arn:aws-us-gov:iam::aws:policy/ReadOnlyAccess", true},
+		{"customer managed", "arn:aws:iam::123456789012:policy/deploy", false},
+		{"customer managed named aws", "arn:aws:iam::123456789012:policy/aws", false},
+		{"other service", "arn:aws:s3::aws:bucket", false},
+		{"not an ARN", "AdministratorAccess", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAWSManagedPolicyArn(tt.arn); got != tt.want {
+				t.Errorf("isAWSManagedPolicyArn(%q) = %v, want %v", tt.arn, got, tt.want)
+			}
+		})
+	}
+}
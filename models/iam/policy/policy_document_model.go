@@ -0,0 +1,56 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// policyDocument is the part of an IAM policy document checked locally
+type policyDocument struct {
+	Version   string          `json:"Version"`
+	Statement json.RawMessage `json:"Statement"`
+}
+
+// ValidatePolicyDocument checks that a policy document is well-formed JSON with a Statement,
+// catching mistakes before IAM rejects the document with a less helpful message
+func ValidatePolicyDocument(document string) error {
+	if strings.TrimSpace(document) == "" {
+		return errors.New("policy document is empty")
+	}
+
+	var doc policyDocument
+	decoder := json.NewDecoder(strings.NewReader(document))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("policy document is not valid JSON: %v", err)
+	}
+
+	if len(doc.Statement) == 0 || string(doc.Statement) == "null" {
+		return errors.New("policy document has no Statement")
+	}
+	if doc.Version != "" && doc.Version != "2012-10-17" && doc.Version != "2008-10-17" {
+		return fmt.Errorf("unknown policy language Version '%s' (use 2012-10-17)", doc.Version)
+	}
+	return nil
+}
+
+// FormatPolicyDocument decodes a document as returned by IAM and indents it, so that
+// documents can be shown and compared line by line
+func FormatPolicyDocument(document string) string {
+	// IAM returns documents URL-encoded; local files are plain JSON
+	if !strings.HasPrefix(strings.TrimSpace(document), "{") {
+		if decoded, err := url.PathUnescape(document); err == nil {
+			document = decoded
+		}
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(document), "", "  "); err != nil {
+		return document
+	}
+	return out.String()
+}
@@ -0,0 +1,66 @@
+package policy
+
+import "testing"
+
+func TestFormatPolicyDocument(t *testing.T) {
+	indented := `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": "s3:GetObject",
+      "Resource": "arn:aws:s3:::reports/*"
+    }
+  ]
+}`
+
+	tests := []struct {
+		name     string
+		document string
+		want     string
+	}{
+		{
+			name:     "plain JSON is indented",
+			document: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"arn:aws:s3:::reports/*"}]}`,
+			want:     indented,
+		},
+		{
+			name:     "URL-encoded document from IAM is decoded",
+			document: "%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%7B%22Effect%22%3A%22Allow%22%2C%22Action%22%3A%22s3%3AGetObject%22%2C%22Resource%22%3A%22arn%3Aaws%3As3%3A%3A%3Areports%2F%2A%22%7D%5D%7D",
+			want:     indented,
+		},
+		{
+			name:     "URL-encoded whitespace is decoded",
+			document: "%7B%0A%20%20%22Version%22%3A%20%222012-10-17%22%2C%0A%20%20%22Statement%22%3A%20%5B%5D%0A%7D",
+			want:     "{\n  \"Version\": \"2012-10-17\",\n  \"Statement\": []\n}",
+		},
+		{
+			name:     "percent signs in plain JSON are kept",
+			document: `{"Condition":{"StringLike":{"s3:prefix":"home/%41"}}}`,
+			want:     "{\n  \"Condition\": {\n    \"StringLike\": {\n      \"s3:prefix\": \"home/%41\"\n    }\n  }\n}",
+		},
+		{
+			name:     "already indented JSON is unchanged",
+			document: indented,
+			want:     indented,
+		},
+		{
+			name:     "invalid JSON is returned decoded but otherwise as is",
+			document: "%7Bnot%20json",
+			want:     "{not json",
+		},
+		{
+			name:     "invalid escape is returned as is",
+			document: "%zz",
+			want:     "%zz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatPolicyDocument(tt.document); got != tt.want {
+				t.Errorf("FormatPolicyDocument() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
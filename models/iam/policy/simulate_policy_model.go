@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// MatchedStatement points at the policy statement that decided a simulation result
+type MatchedStatement struct {
+	PolicyID   string `json:"policy_id" yaml:"policy_id"`
+	PolicyType string `json:"policy_type" yaml:"policy_type"`
+	StartLine  int32  `json:"start_line" yaml:"start_line"`
+	EndLine    int32  `json:"end_line" yaml:"end_line"`
+}
+
+// String describes the statement as "policy (type, lines a-b)"
+func (s MatchedStatement) String() string {
+	if s.StartLine == 0 {
+		return fmt.Sprintf("%s (%s)", s.PolicyID, s.PolicyType)
+	}
+	if s.EndLine == 0 || s.EndLine == s.StartLine {
+		return fmt.Sprintf("%s (%s, line %d)", s.PolicyID, s.PolicyType, s.StartLine)
+	}
+	return fmt.Sprintf("%s (%s, lines %d-%d)", s.PolicyID, s.PolicyType, s.StartLine, s.EndLine)
+}
+
+// SimulationResult is the decision for one action on one resource
+type SimulationResult struct {
+	Action               string             `json:"action" yaml:"action"`
+	Resource             string             `json:"resource" yaml:"resource"`
+	Decision             string             `json:"decision" yaml:"decision"`
+	Allowed              bool               `json:"allowed" yaml:"allowed"`
+	BlockedByBoundary    bool               `json:"blocked_by_boundary" yaml:"blocked_by_boundary"`
+	MatchedStatements    []MatchedStatement `json:"matched_statements" yaml:"matched_statements"`
+	MissingContextValues []string           `json:"missing_context_values,omitempty" yaml:"missing_context_values,omitempty"`
+}
+
+// SimulationTableHeaders are the column headers matching SimulationResult.TableRow
+var SimulationTableHeaders = []string{"Action", "Resource", "Decision", "Matched Statement"}
+
+// TableRow returns the result as a table row
+func (r SimulationResult) TableRow() []string {
+	decision := r.Decision
+	if r.BlockedByBoundary {
+		decision += " (permissions boundary)"
+	}
+
+	matched := make([]string, 0, len(r.MatchedStatements))
+	for _, s := range r.MatchedStatements {
+		matched = append(matched, s.String())
+	}
+	statement := strings.Join(matched, "\n")
+	if statement == "" {
+		statement = "-"
+	}
+	return []string{r.Action, r.Resource, decision, statement}
+}
+
+// ResolvePrincipalArn returns the ARN of a user, group or role
+func ResolvePrincipalArn(identityType, identityName string) (string, error) {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return "", err
+	}
+
+	ctx := context.TODO()
+	client := utils.GetIAMClient()
+
+	switch identityType {
+	case IdentityGroup:
+		result, err := client.GetGroup(ctx, &iam.GetGroupInput{GroupName: aws.String(identityName), MaxItems: aws.Int32(1)})
+		if err != nil {
+			return "", err
+		}
+		return aws.ToString(result.Group.Arn), nil
+	case IdentityRole:
+		result, err := client.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(identityName)})
+		if err != nil {
+			return "", err
+		}
+		return aws.ToString(result.Role.Arn), nil
+	default:
+		result, err := client.GetUser(ctx, &iam.GetUserInput{UserName: aws.String(identityName)})
+		if err != nil {
+			return "", err
+		}
+		return aws.ToString(result.User.Arn), nil
+	}
+}
+
+// SimulatePrincipalPolicy evaluates the policies of a user, group or role for each action on
+// each resource. Resources default to "*" when none are given.
+func SimulatePrincipalPolicy(principalArn string, actions, resources []string) ([]SimulationResult, error) {
+	if len(actions) == 0 {
+		return nil, errors.New("at least one action is required")
+	}
+
+	input := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalArn),
+		ActionNames:     actions,
+	}
+	if len(resources) > 0 {
+		input.ResourceArns = resources
+	}
+
+	results := []SimulationResult{}
+	paginator := iam.NewSimulatePrincipalPolicyPaginator(utils.GetIAMClient(), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, evaluation := range page.EvaluationResults {
+			results = append(results, simulationResultFromSDK(evaluation))
+		}
+	}
+
+	return results, nil
+}
+
+func simulationResultFromSDK(evaluation types.EvaluationResult) SimulationResult {
+	result := SimulationResult{
+		Action:               aws.ToString(evaluation.EvalActionName),
+		Resource:             aws.ToString(evaluation.EvalResourceName),
+		Decision:             string(evaluation.EvalDecision),
+		Allowed:              evaluation.EvalDecision == types.PolicyEvaluationDecisionTypeAllowed,
+		MatchedStatements:    []MatchedStatement{},
+		MissingContextValues: evaluation.MissingContextValues,
+	}
+	if detail := evaluation.PermissionsBoundaryDecisionDetail; detail != nil {
+		result.BlockedByBoundary = !detail.AllowedByPermissionsBoundary
+	}
+
+	for _, s := range evaluation.MatchedStatements {
+		statement := MatchedStatement{
+			PolicyID:   aws.ToString(s.SourcePolicyId),
+			PolicyType: string(s.SourcePolicyType),
+		}
+		if s.StartPosition != nil {
+			statement.StartLine = s.StartPosition.Line
+		}
+		if s.EndPosition != nil {
+			statement.EndLine = s.EndPosition.Line
+		}
+		result.MatchedStatements = append(result.MatchedStatements, statement)
+	}
+
+	return result
+}
+
+// FetchUserPermissionsBoundary returns the ARN of the permissions boundary of a user, or an
+// empty string when none is set
+func FetchUserPermissionsBoundary(username string) (string, error) {
+	result, err := utils.GetIAMClient().GetUser(context.TODO(), &iam.GetUserInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if result.User.PermissionsBoundary == nil {
+		return "", nil
+	}
+	return aws.ToString(result.User.PermissionsBoundary.PermissionsBoundaryArn), nil
+}
+
+// SetUserPermissionsBoundary sets a managed policy as the permissions boundary of a user
+func SetUserPermissionsBoundary(username, policyArn string) error {
+	_, err := utils.GetIAMClient().PutUserPermissionsBoundary(context.TODO(), &iam.PutUserPermissionsBoundaryInput{
+		UserName:            aws.String(username),
+		PermissionsBoundary: aws.String(policyArn),
+	})
+	return err
+}
+
+// RemoveUserPermissionsBoundary removes the permissions boundary of a user
+func RemoveUserPermissionsBoundary(username string) error {
+	_, err := utils.GetIAMClient().DeleteUserPermissionsBoundary(context.TODO(), &iam.DeleteUserPermissionsBoundaryInput{
+		UserName: aws.String(username),
+	})
+	return err
+}
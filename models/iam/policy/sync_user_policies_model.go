@@ -32,32 +32,7 @@ func SyncUserPolicies(username string, desiredArns, currentArns []string) SyncPo
 		Success:      true,
 	}
 
-	// Convert to sets for efficient lookup
-	currentSet := make(map[string]bool)
-	for _, arn := range currentArns {
-		currentSet[arn] = true
-	}
-
-	desiredSet := make(map[string]bool)
-	for _, arn := range desiredArns {
-		desiredSet[arn] = true
-	}
-
-	// Find policies to attach (in desired but not in current)
-	toAttach := []string{}
-	for _, arn := range desiredArns {
-		if !currentSet[arn] {
-			toAttach = append(toAttach, arn)
-		}
-	}
-
-	// Find policies to detach (in current but not in desired)
-	toDetach := []string{}
-	for _, arn := range currentArns {
-		if !desiredSet[arn] {
-			toDetach = append(toDetach, arn)
-		}
-	}
+	toAttach, toDetach := DiffSets(desiredArns, currentArns)
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex
@@ -101,3 +76,35 @@ func SyncUserPolicies(username string, desiredArns, currentArns []string) SyncPo
 	wg.Wait()
 	return result
 }
+
+// DiffSets returns the items of desired missing from current, and the items of current
+// missing from desired, each in input order and without duplicates
+func DiffSets(desired, current []string) (toAdd, toRemove []string) {
+	currentSet := make(map[string]bool)
+	for _, item := range current {
+		currentSet[item] = true
+	}
+
+	desiredSet := make(map[string]bool)
+	for _, item := range desired {
+		desiredSet[item] = true
+	}
+
+	toAdd = []string{}
+	for _, item := range desired {
+		if !currentSet[item] {
+			toAdd = append(toAdd, item)
+			currentSet[item] = true
+		}
+	}
+
+	toRemove = []string{}
+	for _, item := range current {
+		if !desiredSet[item] {
+			toRemove = append(toRemove, item)
+			desiredSet[item] = true
+		}
+	}
+
+	return toAdd, toRemove
+}
@@ -0,0 +1,67 @@
+package policy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSets(t *testing.T) {
+	tests := []struct {
+		name         string
+		desired      []string
+		current      []string
+		wantToAdd    []string
+		wantToRemove []string
+	}{
+		{
+			name:         "both empty",
+			wantToAdd:    []string{},
+			wantToRemove: []string{},
+		},
+		{
+			name:         "everything is new",
+			desired:      []string{"a", "b"},
+			wantToAdd:    []string{"a", "b"},
+			wantToRemove: []string{},
+		},
+		{
+			name:         "everything goes",
+			current:      []string{"a", "b"},
+			wantToAdd:    []string{},
+			wantToRemove: []string{"a", "b"},
+		},
+		{
+			name:         "already in sync, in another order",
+			desired:      []string{"a", "b"},
+			current:      []string{"b", "a"},
+			wantToAdd:    []string{},
+			wantToRemove: []string{},
+		},
+		{
+			name:         "partial overlap keeps input order",
+			desired:      []string{"c", "a", "d"},
+			current:      []string{"b", "a", "e"},
+			wantToAdd:    []string{"c", "d"},
+			wantToRemove: []string{"b", "e"},
+		},
+		{
+			name:         "duplicates are reported once",
+			desired:      []string{"a", "a", "b"},
+			current:      []string{"c", "c"},
+			wantToAdd:    []string{"a", "b"},
+			wantToRemove: []string{"c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			toAdd, toRemove := DiffSets(tt.desired, tt.current)
+			if !reflect.DeepEqual(toAdd, tt.wantToAdd) {
+				t.Errorf("DiffSets() toAdd = %q, want %q", toAdd, tt.wantToAdd)
+			}
+			if !reflect.DeepEqual(toRemove, tt.wantToRemove) {
+				t.Errorf("DiffSets() toRemove = %q, want %q", toRemove, tt.wantToRemove)
+			}
+		})
+	}
+}
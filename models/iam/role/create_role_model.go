@@ -0,0 +1,80 @@
+package role
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// ServiceTrustPolicy returns a trust policy that lets an AWS service, such as
+// ec2.amazonaws.com or lambda.amazonaws.com, assume the role
+func ServiceTrustPolicy(service string) string {
+	document := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect":    "Allow",
+				"Principal": map[string]string{"Service": service},
+				"Action":    "sts:AssumeRole",
+			},
+		},
+	}
+
+	data, _ := json.MarshalIndent(document, "", "  ")
+	return string(data)
+}
+
+// CreateIAMRole creates a role with the given trust policy
+func CreateIAMRole(roleName, path, description, trustPolicy string) (*IAMRole, error) {
+	if err := policy.ValidatePolicyDocument(trustPolicy); err != nil {
+		return nil, err
+	}
+
+	input := &iam.CreateRoleInput{
+		RoleName:                 aws.String(roleName),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+	}
+	if path != "" {
+		input.Path = aws.String(path)
+	}
+	if description != "" {
+		input.Description = aws.String(description)
+	}
+
+	result, err := utils.GetIAMClient().CreateRole(context.TODO(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	role := roleFromSDK(*result.Role)
+	return &role, nil
+}
+
+// FetchTrustPolicy returns the trust policy of a role as indented JSON
+func FetchTrustPolicy(roleName string) (string, error) {
+	result, err := utils.GetIAMClient().GetRole(context.TODO(), &iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return policy.FormatPolicyDocument(aws.ToString(result.Role.AssumeRolePolicyDocument)), nil
+}
+
+// UpdateTrustPolicy replaces the trust policy of a role
+func UpdateTrustPolicy(roleName, trustPolicy string) error {
+	if err := policy.ValidatePolicyDocument(trustPolicy); err != nil {
+		return err
+	}
+
+	_, err := utils.GetIAMClient().UpdateAssumeRolePolicy(context.TODO(), &iam.UpdateAssumeRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyDocument: aws.String(trustPolicy),
+	})
+	return err
+}
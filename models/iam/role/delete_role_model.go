@@ -0,0 +1,100 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// ErrRoleHasDependencies is returned when a role still has policies or instance profiles
+// and the deletion was not forced
+var ErrRoleHasDependencies = errors.New("role still has attached policies, inline policies or instance profiles")
+
+type RoleDependencies struct {
+	AttachedPolicies []string `json:"attached_policies"`
+	InlinePolicies   []string `json:"inline_policies"`
+	InstanceProfiles []string `json:"instance_profiles"`
+}
+
+// HasDependencies reports whether anything must be removed before the role can be deleted
+func (d *RoleDependencies) HasDependencies() bool {
+	return len(d.AttachedPolicies) > 0 || len(d.InlinePolicies) > 0 || len(d.InstanceProfiles) > 0
+}
+
+// CheckRoleDependencies lists what has to be detached or removed before a role can be deleted
+func CheckRoleDependencies(roleName string) (*RoleDependencies, error) {
+	deps := &RoleDependencies{
+		AttachedPolicies: []string{},
+		InlinePolicies:   []string{},
+		InstanceProfiles: []string{},
+	}
+
+	attached, err := ListRolePolicies(roleName)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range attached {
+		deps.AttachedPolicies = append(deps.AttachedPolicies, p.PolicyArn)
+	}
+
+	inline, err := policy.ListInlinePolicies(policy.IdentityRole, roleName)
+	if err != nil {
+		return nil, err
+	}
+	deps.InlinePolicies = append(deps.InlinePolicies, inline...)
+
+	profiles, err := FetchInstanceProfilesForRole(roleName)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range profiles {
+		deps.InstanceProfiles = append(deps.InstanceProfiles, p.InstanceProfileName)
+	}
+
+	return deps, nil
+}
+
+// DeleteIAMRole deletes a role. A role with dependencies is only deleted when force is set,
+// in which case its policies are detached or deleted and it is removed from its instance
+// profiles first. The instance profiles themselves are kept.
+func DeleteIAMRole(roleName string, force bool) error {
+	deps, err := CheckRoleDependencies(roleName)
+	if err != nil {
+		return fmt.Errorf("failed to check dependencies: %w", err)
+	}
+	if deps.HasDependencies() && !force {
+		return ErrRoleHasDependencies
+	}
+
+	for _, policyArn := range deps.AttachedPolicies {
+		if err := DetachRolePolicy(roleName, policyArn); err != nil {
+			return fmt.Errorf("failed to detach policy %s: %w", policyArn, err)
+		}
+	}
+
+	for _, policyName := range deps.InlinePolicies {
+		if err := policy.DeleteInlinePolicy(policy.IdentityRole, roleName, policyName); err != nil {
+			return fmt.Errorf("failed to delete inline policy %s: %w", policyName, err)
+		}
+	}
+
+	for _, profileName := range deps.InstanceProfiles {
+		if err := RemoveRoleFromInstanceProfile(profileName, roleName); err != nil {
+			return fmt.Errorf("failed to remove role from instance profile %s: %w", profileName, err)
+		}
+	}
+
+	_, err = utils.GetIAMClient().DeleteRole(context.TODO(), &iam.DeleteRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
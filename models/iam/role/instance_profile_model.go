@@ -0,0 +1,158 @@
+package role
+
+import (
+	"context"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// InstanceProfile is an EC2 instance profile and the role it carries
+type InstanceProfile struct {
+	InstanceProfileName string   `json:"instance_profile_name" yaml:"instance_profile_name"`
+	Arn                 string   `json:"arn" yaml:"arn"`
+	Path                string   `json:"path" yaml:"path"`
+	Roles               []string `json:"roles" yaml:"roles"`
+	CreateDate          string   `json:"create_date" yaml:"create_date"`
+}
+
+// InstanceProfileTableHeaders are the column headers matching InstanceProfile.TableRow
+var InstanceProfileTableHeaders = []string{"Instance Profile", "Path", "Roles", "Created At"}
+
+// TableRow returns the instance profile as a table row
+func (p InstanceProfile) TableRow() []string {
+	roles := strings.Join(p.Roles, ", ")
+	if roles == "" {
+		roles = "-"
+	}
+	return []string{p.InstanceProfileName, p.Path, roles, p.CreateDate}
+}
+
+// FetchInstanceProfiles lists all instance profiles in the account
+func FetchInstanceProfiles() ([]InstanceProfile, error) {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+	profiles := []InstanceProfile{}
+
+	paginator := iam.NewListInstanceProfilesPaginator(client, &iam.ListInstanceProfilesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, profile := range page.InstanceProfiles {
+			profiles = append(profiles, instanceProfileFromSDK(profile))
+		}
+	}
+
+	return profiles, nil
+}
+
+// FetchInstanceProfilesForRole lists the instance profiles a role belongs to
+func FetchInstanceProfilesForRole(roleName string) ([]InstanceProfile, error) {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+	profiles := []InstanceProfile{}
+
+	paginator := iam.NewListInstanceProfilesForRolePaginator(client, &iam.ListInstanceProfilesForRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, profile := range page.InstanceProfiles {
+			profiles = append(profiles, instanceProfileFromSDK(profile))
+		}
+	}
+
+	return profiles, nil
+}
+
+// CreateInstanceProfile creates an instance profile and, when roleName is set, adds the role to it
+func CreateInstanceProfile(profileName, path, roleName string) (*InstanceProfile, error) {
+	input := &iam.CreateInstanceProfileInput{
+		InstanceProfileName: aws.String(profileName),
+	}
+	if path != "" {
+		input.Path = aws.String(path)
+	}
+
+	result, err := utils.GetIAMClient().CreateInstanceProfile(context.TODO(), input)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := instanceProfileFromSDK(*result.InstanceProfile)
+	if roleName != "" {
+		if err := AddRoleToInstanceProfile(profileName, roleName); err != nil {
+			return &profile, err
+		}
+		profile.Roles = append(profile.Roles, roleName)
+	}
+	return &profile, nil
+}
+
+// AddRoleToInstanceProfile links a role to an instance profile. A profile holds at most one role.
+func AddRoleToInstanceProfile(profileName, roleName string) error {
+	_, err := utils.GetIAMClient().AddRoleToInstanceProfile(context.TODO(), &iam.AddRoleToInstanceProfileInput{
+		InstanceProfileName: aws.String(profileName),
+		RoleName:            aws.String(roleName),
+	})
+	return err
+}
+
+// RemoveRoleFromInstanceProfile unlinks a role from an instance profile
+func RemoveRoleFromInstanceProfile(profileName, roleName string) error {
+	_, err := utils.GetIAMClient().RemoveRoleFromInstanceProfile(context.TODO(), &iam.RemoveRoleFromInstanceProfileInput{
+		InstanceProfileName: aws.String(profileName),
+		RoleName:            aws.String(roleName),
+	})
+	return err
+}
+
+// DeleteInstanceProfile removes any role from an instance profile and deletes it
+func DeleteInstanceProfile(profileName string) error {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+
+	result, err := client.GetInstanceProfile(ctx, &iam.GetInstanceProfileInput{
+		InstanceProfileName: aws.String(profileName),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, role := range result.InstanceProfile.Roles {
+		if err := RemoveRoleFromInstanceProfile(profileName, aws.ToString(role.RoleName)); err != nil {
+			return err
+		}
+	}
+
+	_, err = client.DeleteInstanceProfile(ctx, &iam.DeleteInstanceProfileInput{
+		InstanceProfileName: aws.String(profileName),
+	})
+	return err
+}
+
+func instanceProfileFromSDK(profile types.InstanceProfile) InstanceProfile {
+	item := InstanceProfile{
+		InstanceProfileName: aws.ToString(profile.InstanceProfileName),
+		Arn:                 aws.ToString(profile.Arn),
+		Path:                aws.ToString(profile.Path),
+		Roles:               []string{},
+	}
+	for _, role := range profile.Roles {
+		item.Roles = append(item.Roles, aws.ToString(role.RoleName))
+	}
+	if profile.CreateDate != nil {
+		item.CreateDate = profile.CreateDate.Format("2006-01-02T15:04:05Z")
+	}
+	return item
+}
@@ -0,0 +1,91 @@
+package role
+
+import (
+	"context"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/tag"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// IAMRole is a single row of the IAM role listing
+type IAMRole struct {
+	RoleName           string `json:"role_name" yaml:"role_name"`
+	RoleID             string `json:"role_id" yaml:"role_id"`
+	Arn                string `json:"arn" yaml:"arn"`
+	Path               string `json:"path" yaml:"path"`
+	Description        string `json:"description" yaml:"description"`
+	MaxSessionDuration int32  `json:"max_session_duration" yaml:"max_session_duration"`
+	CreateDate         string `json:"create_date" yaml:"create_date"`
+
+	Tags map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"` // Only filled by FetchIAMRolesWithTags
+}
+
+// IAMRoleTableHeaders are the column headers matching IAMRole.TableRow
+var IAMRoleTableHeaders = []string{"Role Name", "Path", "Description", "Created At", "Tags"}
+
+// TableRow returns the role as a table row
+func (r IAMRole) TableRow() []string {
+	return []string{r.RoleName, r.Path, r.Description, r.CreateDate, tag.Format(r.Tags)}
+}
+
+// TagMap returns the tags of the role, for tag.FilterByTag
+func (r IAMRole) TagMap() map[string]string {
+	return r.Tags
+}
+
+// FetchIAMRolesWithTags lists all IAM roles in the account together with their tags, which
+// takes one extra call per role. Roles whose tags could not be read are listed without them
+// and reported in the warnings.
+func FetchIAMRolesWithTags() ([]IAMRole, []string, error) {
+	roles, err := FetchIAMRoles()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tag.Fill(utils.GetIAMClient(), policy.IdentityRole, roles,
+		func(r IAMRole) string { return r.RoleName },
+		func(r *IAMRole, tags map[string]string) { r.Tags = tags },
+		false)
+}
+
+// FetchIAMRoles lists all IAM roles in the account, without their tags
+func FetchIAMRoles() ([]IAMRole, error) {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+	roles := []IAMRole{}
+
+	paginator := iam.NewListRolesPaginator(client, &iam.ListRolesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, role := range page.Roles {
+			roles = append(roles, roleFromSDK(role))
+		}
+	}
+
+	return roles, nil
+}
+
+func roleFromSDK(role types.Role) IAMRole {
+	item := IAMRole{
+		RoleName:    aws.ToString(role.RoleName),
+		RoleID:      aws.ToString(role.RoleId),
+		Arn:         aws.ToString(role.Arn),
+		Path:        aws.ToString(role.Path),
+		Description: aws.ToString(role.Description),
+	}
+	if role.MaxSessionDuration != nil {
+		item.MaxSessionDuration = *role.MaxSessionDuration
+	}
+	if role.CreateDate != nil {
+		item.CreateDate = role.CreateDate.Format("2006-01-02T15:04:05Z")
+	}
+	return item
+}
@@ -0,0 +1,80 @@
+package role
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// AttachedPolicy is a managed policy attached to a role
+type AttachedPolicy struct {
+	PolicyName string `json:"policy_name" yaml:"policy_name"`
+	PolicyArn  string `json:"policy_arn" yaml:"policy_arn"`
+}
+
+// AttachedPolicyTableHeaders are the column headers matching AttachedPolicy.TableRow
+var AttachedPolicyTableHeaders = []string{"Policy Name", "Policy ARN"}
+
+// TableRow returns the attached policy as a table row
+func (p AttachedPolicy) TableRow() []string {
+	return []string{p.PolicyName, p.PolicyArn}
+}
+
+func AttachRolePolicy(roleName, policyArn string) error {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+
+	_, err := client.AttachRolePolicy(ctx, &iam.AttachRolePolicyInput{
+		RoleName:  aws.String(roleName),
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to attach policy: %w", err)
+	}
+
+	return nil
+}
+
+func DetachRolePolicy(roleName, policyArn string) error {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+
+	_, err := client.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{
+		RoleName:  aws.String(roleName),
+		PolicyArn: aws.String(policyArn),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach policy: %w", err)
+	}
+
+	return nil
+}
+
+// ListRolePolicies lists the managed policies attached to a role
+func ListRolePolicies(roleName string) ([]AttachedPolicy, error) {
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+	policies := []AttachedPolicy{}
+
+	paginator := iam.NewListAttachedRolePoliciesPaginator(client, &iam.ListAttachedRolePoliciesInput{
+		RoleName: aws.String(roleName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policies: %w", err)
+		}
+
+		for _, policy := range page.AttachedPolicies {
+			policies = append(policies, AttachedPolicy{
+				PolicyName: aws.ToString(policy.PolicyName),
+				PolicyArn:  aws.ToString(policy.PolicyArn),
+			})
+		}
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,101 @@
+package state
+
+import (
+	"errors"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/group"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+)
+
+// Result is the outcome of applying one change
+type Result struct {
+	Change  `yaml:",inline"`
+	Success bool   `json:"success" yaml:"success"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ResultTableHeaders are the column headers matching Result.TableRow
+var ResultTableHeaders = []string{"Op", "Action", "Identity", "Target", "Result"}
+
+// TableRow returns the result as a table row
+func (r Result) TableRow() []string {
+	status := "OK"
+	if !r.Success {
+		status = "FAILED: " + r.Error
+	}
+	return append(r.Change.TableRow(), status)
+}
+
+// Apply executes the changes in order and returns one result per change. A failure does not
+// stop the run, but changes to a user or group whose creation failed are skipped.
+func Apply(changes []Change) []Result {
+	results := make([]Result, 0, len(changes))
+	notCreated := map[string]bool{}
+
+	for _, c := range changes {
+		var err error
+		switch {
+		case notCreated[c.IdentityType+"/"+c.IdentityName]:
+			err = errors.New("skipped: " + c.IdentityType + " '" + c.IdentityName + "' was not created")
+		case c.Action == ActionAddToGroup && notCreated[policy.IdentityGroup+"/"+c.Target]:
+			err = errors.New("skipped: group '" + c.Target + "' was not created")
+		default:
+			err = applyChange(c)
+		}
+
+		if err != nil && c.Action == ActionCreate {
+			notCreated[c.IdentityType+"/"+c.IdentityName] = true
+		}
+
+		result := Result{Change: c, Success: err == nil}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// FailedResults returns the results of the changes that did not apply
+func FailedResults(results []Result) []Result {
+	failed := []Result{}
+	for _, r := range results {
+		if !r.Success {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+func applyChange(c Change) error {
+	isUser := c.IdentityType == policy.IdentityUser
+
+	switch c.Action {
+	case ActionCreate:
+		if !isUser {
+			return group.CreateGroup(c.IdentityName)
+		}
+		status, err := user.CreateIAMUser(c.IdentityName)
+		if status == user.UserCreationError {
+			return err
+		}
+		return nil
+	case ActionAddToGroup:
+		return group.AddUserToGroup(c.IdentityName, c.Target)
+	case ActionRemoveFromGroup:
+		return group.RemoveUserFromGroup(c.IdentityName, c.Target)
+	case ActionAttachPolicy:
+		if isUser {
+			return policy.AttachUserPolicy(c.IdentityName, c.Target)
+		}
+		return group.AttachGroupPolicy(c.IdentityName, c.Target)
+	case ActionDetachPolicy:
+		if isUser {
+			return policy.DetachUserPolicy(c.IdentityName, c.Target)
+		}
+		return group.DetachGroupPolicy(c.IdentityName, c.Target)
+	}
+	return errors.New("unknown action '" + c.Action + "'")
+}
@@ -0,0 +1,261 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/group"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/user"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// Change actions
+const (
+	ActionCreate          = "create"
+	ActionAddToGroup      = "add-to-group"
+	ActionRemoveFromGroup = "remove-from-group"
+	ActionAttachPolicy    = "attach-policy"
+	ActionDetachPolicy    = "detach-policy"
+)
+
+// Change is one step needed to bring live IAM in line with the state file. IdentityType is
+// policy.IdentityUser or policy.IdentityGroup; Target is a group name or a policy ARN.
+type Change struct {
+	Action       string `json:"action" yaml:"action"`
+	IdentityType string `json:"identity_type" yaml:"identity_type"`
+	IdentityName string `json:"identity_name" yaml:"identity_name"`
+	Target       string `json:"target,omitempty" yaml:"target,omitempty"`
+}
+
+// ChangeTableHeaders are the column headers matching Change.TableRow
+var ChangeTableHeaders = []string{"Op", "Action", "Identity", "Target"}
+
+// TableRow returns the change as a table row
+func (c Change) TableRow() []string {
+	return []string{c.Symbol(), c.Action, c.IdentityType + " " + c.IdentityName, c.Target}
+}
+
+// Symbol returns "+" for changes that add something and "-" for changes that remove it
+func (c Change) Symbol() string {
+	if c.Removes() {
+		return "-"
+	}
+	return "+"
+}
+
+// Removes reports whether the change takes away a membership or a policy
+func (c Change) Removes() bool {
+	return c.Action == ActionRemoveFromGroup || c.Action == ActionDetachPolicy
+}
+
+// String describes the change in a single diff-style line
+func (c Change) String() string {
+	identity := c.IdentityType + " " + c.IdentityName
+	if c.Action == ActionCreate {
+		return "+ create " + identity
+	}
+	return c.Symbol() + " " + identity + ": " + strings.ReplaceAll(c.Action, "-", " ") + " " + c.Target
+}
+
+// CountChanges returns how many of the changes add and how many remove something
+func CountChanges(changes []Change) (additions, removals int) {
+	for _, c := range changes {
+		if c.Removes() {
+			removals++
+		} else {
+			additions++
+		}
+	}
+	return additions, removals
+}
+
+// Plan compares the state file with live IAM and returns the changes apply would make, in the
+// order they must run: groups before users, and each identity created before it is changed
+func Plan(s *State) ([]Change, error) {
+	policyArns, err := resolvePolicyArns(s)
+	if err != nil {
+		return nil, err
+	}
+
+	liveGroups := map[string]bool{}
+	groups, err := group.FetchIAMGroups()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range groups {
+		liveGroups[g.GroupName] = true
+	}
+
+	liveUsers := map[string]bool{}
+	users, err := user.FetchIAMUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		liveUsers[u.UserName] = true
+	}
+
+	// A membership may name a group that is not managed by the file, but it must exist
+	fileGroups := map[string]bool{}
+	for _, g := range s.Groups {
+		fileGroups[g.Name] = true
+	}
+	for _, u := range s.Users {
+		for _, name := range listOf(u.Groups) {
+			if !fileGroups[name] && !liveGroups[name] {
+				return nil, fmt.Errorf("user '%s': group '%s' does not exist and is not in the state file", u.Name, name)
+			}
+		}
+	}
+
+	changes := []Change{}
+
+	// Only the aspects whose key is present in the file are compared with live IAM
+	for _, g := range s.Groups {
+		exists := liveGroups[g.Name]
+		if !exists {
+			changes = append(changes, Change{Action: ActionCreate, IdentityType: policy.IdentityGroup, IdentityName: g.Name})
+		}
+
+		if g.Policies != nil {
+			var currentPolicies []string
+			if exists {
+				if currentPolicies, err = fetchGroupPolicyArns(g.Name); err != nil {
+					return nil, fmt.Errorf("group '%s': %w", g.Name, err)
+				}
+			}
+			changes = append(changes, policyChanges(policy.IdentityGroup, g.Name, mapEach(*g.Policies, policyArns), currentPolicies)...)
+		}
+	}
+
+	for _, u := range s.Users {
+		exists := liveUsers[u.Name]
+		if !exists {
+			changes = append(changes, Change{Action: ActionCreate, IdentityType: policy.IdentityUser, IdentityName: u.Name})
+		}
+
+		if u.Groups != nil {
+			var currentGroups []string
+			if exists {
+				if currentGroups, err = fetchUserGroups(u.Name); err != nil {
+					return nil, fmt.Errorf("user '%s': %w", u.Name, err)
+				}
+			}
+			toAdd, toRemove := policy.DiffSets(*u.Groups, currentGroups)
+			for _, name := range toAdd {
+				changes = append(changes, Change{Action: ActionAddToGroup, IdentityType: policy.IdentityUser, IdentityName: u.Name, Target: name})
+			}
+			for _, name := range toRemove {
+				changes = append(changes, Change{Action: ActionRemoveFromGroup, IdentityType: policy.IdentityUser, IdentityName: u.Name, Target: name})
+			}
+		}
+
+		if u.Policies != nil {
+			var currentPolicies []string
+			if exists {
+				if currentPolicies, err = fetchUserPolicyArns(u.Name); err != nil {
+					return nil, fmt.Errorf("user '%s': %w", u.Name, err)
+				}
+			}
+			changes = append(changes, policyChanges(policy.IdentityUser, u.Name, mapEach(*u.Policies, policyArns), currentPolicies)...)
+		}
+	}
+
+	return changes, nil
+}
+
+func policyChanges(identityType, name string, desired, current []string) []Change {
+	toAttach, toDetach := policy.DiffSets(desired, current)
+
+	changes := []Change{}
+	for _, arn := range toAttach {
+		changes = append(changes, Change{Action: ActionAttachPolicy, IdentityType: identityType, IdentityName: name, Target: arn})
+	}
+	for _, arn := range toDetach {
+		changes = append(changes, Change{Action: ActionDetachPolicy, IdentityType: identityType, IdentityName: name, Target: arn})
+	}
+	return changes
+}
+
+// resolvePolicyArns maps every policy reference in the file to its ARN
+func resolvePolicyArns(s *State) (map[string]string, error) {
+	var refs []string
+	for _, g := range s.Groups {
+		refs = append(refs, listOf(g.Policies)...)
+	}
+	for _, u := range s.Users {
+		refs = append(refs, listOf(u.Policies)...)
+	}
+	return policy.ResolvePolicyArns(refs)
+}
+
+// listOf returns the entries of an optional list, none when it is not managed
+func listOf(list *[]string) []string {
+	if list == nil {
+		return nil
+	}
+	return *list
+}
+
+func mapEach(items []string, mapping map[string]string) []string {
+	mapped := make([]string, len(items))
+	for i, item := range items {
+		mapped[i] = mapping[item]
+	}
+	return mapped
+}
+
+func fetchUserGroups(username string) ([]string, error) {
+	client := utils.GetIAMClient()
+	names := []string{}
+
+	paginator := iam.NewListGroupsForUserPaginator(client, &iam.ListGroupsForUserInput{UserName: aws.String(username)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range page.Groups {
+			names = append(names, aws.ToString(g.GroupName))
+		}
+	}
+	return names, nil
+}
+
+func fetchUserPolicyArns(username string) ([]string, error) {
+	client := utils.GetIAMClient()
+	arns := []string{}
+
+	paginator := iam.NewListAttachedUserPoliciesPaginator(client, &iam.ListAttachedUserPoliciesInput{UserName: aws.String(username)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.AttachedPolicies {
+			arns = append(arns, aws.ToString(p.PolicyArn))
+		}
+	}
+	return arns, nil
+}
+
+func fetchGroupPolicyArns(groupname string) ([]string, error) {
+	client := utils.GetIAMClient()
+	arns := []string{}
+
+	paginator := iam.NewListAttachedGroupPoliciesPaginator(client, &iam.ListAttachedGroupPoliciesInput{GroupName: aws.String(groupname)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range page.AttachedPolicies {
+			arns = append(arns, aws.ToString(p.PolicyArn))
+		}
+	}
+	return arns, nil
+}
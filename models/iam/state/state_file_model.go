@@ -0,0 +1,98 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// State is the desired IAM state kept in a YAML or JSON file. Only the users and groups it
+// lists are managed: missing ones are created, and for listed users and groups the group
+// memberships and managed policies whose key is present are made to match the file exactly.
+// An omitted key leaves that aspect alone, while an empty list removes everything.
+// Identities that are not in the file are never touched.
+type State struct {
+	Groups []GroupState `json:"groups" yaml:"groups"`
+	Users  []UserState  `json:"users" yaml:"users"`
+}
+
+// GroupState is the desired state of one group. Policies are managed policy ARNs or names;
+// nil means the group's policies are not managed.
+type GroupState struct {
+	Name     string    `json:"name" yaml:"name"`
+	Policies *[]string `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// UserState is the desired state of one user. Policies are managed policy ARNs or names;
+// nil Groups or Policies means that aspect of the user is not managed.
+type UserState struct {
+	Name     string    `json:"name" yaml:"name"`
+	Groups   *[]string `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Policies *[]string `json:"policies,omitempty" yaml:"policies,omitempty"`
+}
+
+// LoadStateFile reads a state file. Files ending in .json are parsed as JSON, anything else
+// as YAML. Unknown keys are rejected so that typos do not silently drop entries.
+func LoadStateFile(path string) (*State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseState(data, strings.EqualFold(filepath.Ext(path), ".json"))
+}
+
+// ParseState parses and validates the contents of a state file
+func ParseState(data []byte, isJSON bool) (*State, error) {
+	var s State
+	if isJSON {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&s); err != nil {
+			return nil, fmt.Errorf("invalid state file: %w", err)
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// An empty file decodes to io.EOF and means an empty state
+		if err := decoder.Decode(&s); err != nil && len(bytes.TrimSpace(data)) > 0 {
+			return nil, fmt.Errorf("invalid state file: %w", err)
+		}
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate checks that every user and group has a name and is listed only once
+func (s *State) Validate() error {
+	groups := map[string]bool{}
+	for i, g := range s.Groups {
+		if strings.TrimSpace(g.Name) == "" {
+			return fmt.Errorf("group #%d has no name", i+1)
+		}
+		if groups[g.Name] {
+			return fmt.Errorf("group '%s' is listed more than once", g.Name)
+		}
+		groups[g.Name] = true
+	}
+
+	users := map[string]bool{}
+	for i, u := range s.Users {
+		if strings.TrimSpace(u.Name) == "" {
+			return fmt.Errorf("user #%d has no name", i+1)
+		}
+		if users[u.Name] {
+			return fmt.Errorf("user '%s' is listed more than once", u.Name)
+		}
+		users[u.Name] = true
+	}
+
+	return nil
+}
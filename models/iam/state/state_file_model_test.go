@@ -0,0 +1,171 @@
+package state
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func list(items ...string) *[]string {
+	if items == nil {
+		items = []string{}
+	}
+	return &items
+}
+
+func TestParseState(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		isJSON  bool
+		want    *State
+		wantErr string
+	}{
+		{
+			name: "YAML",
+			data: `
+groups:
+  - name: developers
+    policies: [ReadOnlyAccess]
+users:
+  - name: alice
+    groups: [developers]
+    policies: [arn:aws:iam::123456789012:policy/deploy]
+`,
+			want: &State{
+				Groups: []GroupState{{Name: "developers", Policies: list("ReadOnlyAccess")}},
+				Users:  []UserState{{Name: "alice", Groups: list("developers"), Policies: list("arn:aws:iam::123456789012:policy/deploy")}},
+			},
+		},
+		{
+			name:   "JSON",
+			data:   `{"groups": [{"name": "ops"}], "users": [{"name": "bob", "groups": ["ops"]}]}`,
+			isJSON: true,
+			want: &State{
+				Groups: []GroupState{{Name: "ops"}},
+				Users:  []UserState{{Name: "bob", Groups: list("ops")}},
+			},
+		},
+		{
+			name: "omitted keys are not managed, empty lists are",
+			data: `
+users:
+  - name: alice
+  - name: bob
+    groups: []
+    policies: []
+`,
+			want: &State{
+				Users: []UserState{{Name: "alice"}, {Name: "bob", Groups: list(), Policies: list()}},
+			},
+		},
+		{
+			name:   "JSON empty list is managed",
+			data:   `{"groups": [{"name": "ops", "policies": []}, {"name": "dev"}]}`,
+			isJSON: true,
+			want: &State{
+				Groups: []GroupState{{Name: "ops", Policies: list()}, {Name: "dev"}},
+			},
+		},
+		{
+			name: "empty YAML file",
+			data: "\n",
+			want: &State{},
+		},
+		{
+			name:    "unknown YAML key",
+			data:    "users:\n  - name: alice\n    group: [developers]\n",
+			wantErr: "invalid state file",
+		},
+		{
+			name:    "unknown JSON key",
+			data:    `{"users": [{"name": "alice", "polices": []}]}`,
+			isJSON:  true,
+			wantErr: "invalid state file",
+		},
+		{
+			name:    "malformed JSON",
+			data:    `{"users": [`,
+			isJSON:  true,
+			wantErr: "invalid state file",
+		},
+		{
+			name:    "validation runs after parsing",
+			data:    "users:\n  - name: alice\n  - name: alice\n",
+			wantErr: "user 'alice' is listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseState([]byte(tt.data), tt.isJSON)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseState() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseState() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseState() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   State
+		wantErr string
+	}{
+		{
+			name: "valid",
+			state: State{
+				Groups: []GroupState{{Name: "developers"}, {Name: "ops"}},
+				Users:  []UserState{{Name: "alice"}, {Name: "bob"}},
+			},
+		},
+		{
+			name:  "a user and a group may share a name",
+			state: State{Groups: []GroupState{{Name: "ops"}}, Users: []UserState{{Name: "ops"}}},
+		},
+		{
+			name:    "group without a name",
+			state:   State{Groups: []GroupState{{Name: "ops"}, {Name: " "}}},
+			wantErr: "group #2 has no name",
+		},
+		{
+			name:    "duplicate group",
+			state:   State{Groups: []GroupState{{Name: "ops"}, {Name: "ops"}}},
+			wantErr: "group 'ops' is listed more than once",
+		},
+		{
+			name:    "user without a name",
+			state:   State{Users: []UserState{{}}},
+			wantErr: "user #1 has no name",
+		},
+		{
+			name:    "duplicate user",
+			state:   State{Users: []UserState{{Name: "alice"}, {Name: "bob"}, {Name: "alice"}}},
+			wantErr: "user 'alice' is listed more than once",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.state.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// fetchWorkers bounds how many tag lookups a listing runs at once, to stay clear of IAM throttling
+const fetchWorkers = 5
+
+// Tag is a single tag of a user or role
+type Tag struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// TagTableHeaders are the column headers matching Tag.TableRow
+var TagTableHeaders = []string{"Key", "Value"}
+
+// TableRow returns the tag as a table row
+func (t Tag) TableRow() []string {
+	return []string{t.Key, t.Value}
+}
+
+// ValidateIdentityType checks that identityType is policy.IdentityUser or policy.IdentityRole.
+// IAM groups cannot be tagged.
+func ValidateIdentityType(identityType string) error {
+	switch identityType {
+	case policy.IdentityUser, policy.IdentityRole:
+		return nil
+	case policy.IdentityGroup:
+		return fmt.Errorf("IAM groups cannot be tagged")
+	}
+	return fmt.Errorf("invalid identity type '%s' (use user or role)", identityType)
+}
+
+// ListTags returns the tags of a user or role sorted by key
+func ListTags(identityType, name string) ([]Tag, error) {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return nil, err
+	}
+	tags, err := FetchTags(utils.GetIAMClient(), identityType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Tag, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		list = append(list, Tag{Key: key, Value: tags[key]})
+	}
+	return list, nil
+}
+
+// FetchTags returns the tags of a user or role as a map, using the given client so listings
+// across accounts can read tags from each account
+func FetchTags(client *iam.Client, identityType, name string) (map[string]string, error) {
+	ctx := context.TODO()
+	tags := map[string]string{}
+	var marker *string
+
+	for {
+		var page []types.Tag
+		var truncated bool
+		switch identityType {
+		case policy.IdentityRole:
+			result, err := client.ListRoleTags(ctx, &iam.ListRoleTagsInput{RoleName: aws.String(name), Marker: marker})
+			if err != nil {
+				return nil, err
+			}
+			page, truncated, marker = result.Tags, result.IsTruncated, result.Marker
+		default:
+			result, err := client.ListUserTags(ctx, &iam.ListUserTagsInput{UserName: aws.String(name), Marker: marker})
+			if err != nil {
+				return nil, err
+			}
+			page, truncated, marker = result.Tags, result.IsTruncated, result.Marker
+		}
+
+		for _, t := range page {
+			tags[aws.ToString(t.Key)] = aws.ToString(t.Value)
+		}
+		if !truncated {
+			return tags, nil
+		}
+	}
+}
+
+// FetchAllTags returns the tags of each named user or role, in the order of names, with the
+// error of each lookup that failed. Lookups run concurrently.
+func FetchAllTags(client *iam.Client, identityType string, names []string) ([]map[string]string, []error) {
+	all := make([]map[string]string, len(names))
+	errs := make([]error, len(names))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < fetchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				all[index], errs[index] = FetchTags(client, identityType, names[index])
+			}
+		}()
+	}
+	for i := range names {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return all, errs
+}
+
+// Fill fetches the tags of every listed user or role and stores them with set. Items deleted
+// since they were listed are dropped. Any other failed lookup leaves the item without tags and
+// is returned as a warning, or as an error when required is set, as when a tag filter has to
+// be applied to the listing.
+func Fill[T any](client *iam.Client, identityType string, items []T, name func(T) string, set func(*T, map[string]string), required bool) ([]T, []string, error) {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = name(item)
+	}
+
+	tags, errs := FetchAllTags(client, identityType, names)
+	kept := make([]T, 0, len(items))
+	warnings := []string{}
+	for i, item := range items {
+		var notFound *types.NoSuchEntityException
+		switch {
+		case errors.As(errs[i], &notFound):
+			continue
+		case errs[i] != nil && required:
+			return nil, nil, fmt.Errorf("tags of %s '%s': %w", identityType, names[i], errs[i])
+		case errs[i] != nil:
+			warnings = append(warnings, fmt.Sprintf("tags of %s '%s' could not be read: %s", identityType, names[i], errs[i]))
+		default:
+			set(&item, tags[i])
+		}
+		kept = append(kept, item)
+	}
+	return kept, warnings, nil
+}
+
+// AddTags adds tags to a user or role, replacing the values of keys it already has
+func AddTags(identityType, name string, tags map[string]string) error {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("no tags given")
+	}
+
+	var sdkTags []types.Tag
+	for _, key := range sortedKeys(tags) {
+		sdkTags = append(sdkTags, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+	var err error
+	if identityType == policy.IdentityRole {
+		_, err = client.TagRole(ctx, &iam.TagRoleInput{RoleName: aws.String(name), Tags: sdkTags})
+	} else {
+		_, err = client.TagUser(ctx, &iam.TagUserInput{UserName: aws.String(name), Tags: sdkTags})
+	}
+	return err
+}
+
+// RemoveTags removes the tags with the given keys from a user or role
+func RemoveTags(identityType, name string, keys []string) error {
+	if err := ValidateIdentityType(identityType); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no tag keys given")
+	}
+
+	client := utils.GetIAMClient()
+	ctx := context.TODO()
+	var err error
+	if identityType == policy.IdentityRole {
+		_, err = client.UntagRole(ctx, &iam.UntagRoleInput{RoleName: aws.String(name), TagKeys: keys})
+	} else {
+		_, err = client.UntagUser(ctx, &iam.UntagUserInput{UserName: aws.String(name), TagKeys: keys})
+	}
+	return err
+}
+
+// Parse parses tags written as key=value pairs separated by ';'. Values may contain ',' and '='.
+func Parse(value string) (map[string]string, error) {
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ';' })
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	tags := map[string]string{}
+	for _, field := range fields {
+		key, tagValue, found := strings.Cut(field, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid tag '%s', expected key=value", strings.TrimSpace(field))
+		}
+		tags[key] = strings.TrimSpace(tagValue)
+	}
+	return tags, nil
+}
+
+// Format writes tags as key=value pairs sorted by key
+func Format(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for _, key := range sortedKeys(tags) {
+		pairs = append(pairs, key+"="+tags[key])
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// Filter selects listing rows by tag: Key alone matches any value
+type Filter struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
+
+// ParseFilter parses a filter written as key=value, or key to match any value
+func ParseFilter(value string) (Filter, error) {
+	key, tagValue, found := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return Filter{}, fmt.Errorf("invalid tag filter '%s', expected key=value or key", value)
+	}
+	return Filter{Key: key, Value: strings.TrimSpace(tagValue), AnyValue: !found}, nil
+}
+
+// Matches reports whether tags satisfy the filter
+func (f Filter) Matches(tags map[string]string) bool {
+	value, ok := tags[f.Key]
+	return ok && (f.AnyValue || value == f.Value)
+}
+
+// Tagged is implemented by listing rows that carry tags
+type Tagged interface {
+	TagMap() map[string]string
+}
+
+// FilterByTag returns the items whose tags match the filter
+func FilterByTag[T Tagged](items []T, f Filter) []T {
+	matched := make([]T, 0, len(items))
+	for _, item := range items {
+		if f.Matches(item.TagMap()) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+func sortedKeys(tags map[string]string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package tag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "empty",
+			value: "",
+			want:  nil,
+		},
+		{
+			name:  "only separators",
+			value: ";;",
+			want:  nil,
+		},
+		{
+			name:  "single tag",
+			value: "team=web",
+			want:  map[string]string{"team": "web"},
+		},
+		{
+			name:  "several tags, trimmed",
+			value: " team = web ; env=prod;",
+			want:  map[string]string{"team": "web", "env": "prod"},
+		},
+		{
+			name:  "commas stay in the value",
+			value: "cost=a,b",
+			want:  map[string]string{"cost": "a,b"},
+		},
+		{
+			name:  "only the first '=' separates",
+			value: "query=a=b",
+			want:  map[string]string{"query": "a=b"},
+		},
+		{
+			name:  "empty value",
+			value: "owner=",
+			want:  map[string]string{"owner": ""},
+		},
+		{
+			name:  "last value wins",
+			value: "env=dev;env=prod",
+			want:  map[string]string{"env": "prod"},
+		},
+		{
+			name:    "missing '='",
+			value:   "team=web; env",
+			wantErr: "invalid tag 'env', expected key=value",
+		},
+		{
+			name:    "empty key",
+			value:   " =web",
+			wantErr: "invalid tag '=web', expected key=value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Parse(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.value, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Filter
+		wantErr string
+	}{
+		{
+			name:  "key and value",
+			value: "team=web",
+			want:  Filter{Key: "team", Value: "web"},
+		},
+		{
+			name:  "key only matches any value",
+			value: "team",
+			want:  Filter{Key: "team", AnyValue: true},
+		},
+		{
+			name:  "empty value is matched exactly",
+			value: "owner=",
+			want:  Filter{Key: "owner"},
+		},
+		{
+			name:  "trimmed, value keeps later '=' and ','",
+			value: " query = a=b,c ",
+			want:  Filter{Key: "query", Value: "a=b,c"},
+		},
+		{
+			name:    "empty",
+			value:   "",
+			wantErr: "invalid tag filter '', expected key=value or key",
+		},
+		{
+			name:    "empty key",
+			value:   "=web",
+			wantErr: "invalid tag filter '=web', expected key=value or key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilter(tt.value)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParseFilter(%q) error = %v, want %q", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) error = %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFilter(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewAccessKeyReportRow(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+	usedDaysAgo := func(days int) *time.Time {
+		used := daysAgo(days)
+		return &used
+	}
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name              string
+		key               AccessKey
+		opts              AccessKeyReportOptions
+		wantAgeDays       int
+		wantDaysSinceUsed *int
+		wantFlags         []string
+	}{
+		{
+			name:              "recent key in use",
+			key:               AccessKey{CreateDate: daysAgo(10), LastUsedDate: usedDaysAgo(1)},
+			opts:              AccessKeyReportOptions{MaxAgeDays: 90, MaxUnusedDays: 30},
+			wantAgeDays:       10,
+			wantDaysSinceUsed: intPtr(1),
+			wantFlags:         []string{},
+		},
+		{
+			name:              "age at the threshold is not old",
+			key:               AccessKey{CreateDate: daysAgo(90), LastUsedDate: usedDaysAgo(0)},
+			opts:              AccessKeyReportOptions{MaxAgeDays: 90},
+			wantAgeDays:       90,
+			wantDaysSinceUsed: intPtr(0),
+			wantFlags:         []string{},
+		},
+		{
+			name:              "old key",
+			key:               AccessKey{CreateDate: daysAgo(91), LastUsedDate: usedDaysAgo(2)},
+			opts:              AccessKeyReportOptions{MaxAgeDays: 90},
+			wantAgeDays:       91,
+			wantDaysSinceUsed: intPtr(2),
+			wantFlags:         []string{AccessKeyFlagOld},
+		},
+		{
+			name:        "never used",
+			key:         AccessKey{CreateDate: daysAgo(5)},
+			opts:        AccessKeyReportOptions{MaxAgeDays: 90, MaxUnusedDays: 30},
+			wantAgeDays: 5,
+			wantFlags:   []string{AccessKeyFlagNeverUsed},
+		},
+		{
+			name:        "old and never used",
+			key:         AccessKey{CreateDate: daysAgo(200)},
+			opts:        AccessKeyReportOptions{MaxAgeDays: 90},
+			wantAgeDays: 200,
+			wantFlags:   []string{AccessKeyFlagOld, AccessKeyFlagNeverUsed},
+		},
+		{
+			name:              "unused",
+			key:               AccessKey{CreateDate: daysAgo(60), LastUsedDate: usedDaysAgo(45)},
+			opts:              AccessKeyReportOptions{MaxAgeDays: 90, MaxUnusedDays: 30},
+			wantAgeDays:       60,
+			wantDaysSinceUsed: intPtr(45),
+			wantFlags:         []string{AccessKeyFlagUnused},
+		},
+		{
+			name:              "unused flag disabled",
+			key:               AccessKey{CreateDate: daysAgo(60), LastUsedDate: usedDaysAgo(45)},
+			opts:              AccessKeyReportOptions{MaxAgeDays: 90},
+			wantAgeDays:       60,
+			wantDaysSinceUsed: intPtr(45),
+			wantFlags:         []string{},
+		},
+		{
+			name:              "partial days are not counted",
+			key:               AccessKey{CreateDate: now.Add(-47 * time.Hour), LastUsedDate: usedDaysAgo(0)},
+			opts:              AccessKeyReportOptions{MaxAgeDays: 1},
+			wantAgeDays:       1,
+			wantDaysSinceUsed: intPtr(0),
+			wantFlags:         []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := newAccessKeyReportRow(tt.key, tt.opts, now)
+			if row.AgeDays != tt.wantAgeDays {
+				t.Errorf("AgeDays = %d, want %d", row.AgeDays, tt.wantAgeDays)
+			}
+			if !reflect.DeepEqual(row.DaysSinceUsed, tt.wantDaysSinceUsed) {
+				t.Errorf("DaysSinceUsed = %v, want %v", row.DaysSinceUsed, tt.wantDaysSinceUsed)
+			}
+			if !reflect.DeepEqual(row.Flags, tt.wantFlags) {
+				t.Errorf("Flags = %q, want %q", row.Flags, tt.wantFlags)
+			}
+			if row.Flagged() != (len(tt.wantFlags) > 0) {
+				t.Errorf("Flagged() = %v, want %v", row.Flagged(), len(tt.wantFlags) > 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// maxAccessKeysPerUser is the IAM limit on access keys per user
+const maxAccessKeysPerUser = 2
+
+// Errors returned by StartAccessKeyRotation when the rotation cannot start as asked
+var (
+	ErrAccessKeyNotOwned = errors.New("access key does not belong to the user")
+	ErrAccessKeyLimit    = errors.New("user already has two access keys")
+)
+
+// AccessKey is a single row of a user's access key listing
+type AccessKey struct {
+	UserName        string     `json:"username" yaml:"username"`
+	AccessKeyID     string     `json:"access_key_id" yaml:"access_key_id"`
+	Status          string     `json:"status" yaml:"status"`
+	CreateDate      time.Time  `json:"create_date" yaml:"create_date"`
+	LastUsedDate    *time.Time `json:"last_used_date" yaml:"last_used_date"`
+	LastUsedService string     `json:"last_used_service" yaml:"last_used_service"`
+	LastUsedRegion  string     `json:"last_used_region" yaml:"last_used_region"`
+}
+
+// AccessKeyTableHeaders are the column headers matching AccessKey.TableRow
+var AccessKeyTableHeaders = []string{"Access Key ID", "Status", "Created", "Last Used", "Last Service"}
+
+// TableRow returns the access key as a table row
+func (k AccessKey) TableRow() []string {
+	lastUsed := "Never"
+	if k.LastUsedDate != nil {
+		lastUsed = k.LastUsedDate.Local().Format("2006-01-02 15:04")
+	}
+	return []string{k.AccessKeyID, k.Status, k.CreateDate.Local().Format("2006-01-02 15:04"), lastUsed, k.LastUsedService}
+}
+
+// NewAccessKey is a freshly created access key, the only time the secret is available
+type NewAccessKey struct {
+	UserName        string `json:"username"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+}
+
+// FetchAccessKeys lists a user's access keys together with when they were last used
+func FetchAccessKeys(username string) ([]AccessKey, error) {
+	ctx := context.TODO()
+
+	result, err := utils.GetIAMClient().ListAccessKeys(ctx, &iam.ListAccessKeysInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]AccessKey, 0, len(result.AccessKeyMetadata))
+	for _, metadata := range result.AccessKeyMetadata {
+		key := AccessKey{
+			UserName:    aws.ToString(metadata.UserName),
+			AccessKeyID: aws.ToString(metadata.AccessKeyId),
+			Status:      string(metadata.Status),
+			CreateDate:  aws.ToTime(metadata.CreateDate),
+		}
+
+		lastUsed, err := utils.GetIAMClient().GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
+			AccessKeyId: metadata.AccessKeyId,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if used := lastUsed.AccessKeyLastUsed; used != nil && used.LastUsedDate != nil {
+			key.LastUsedDate = used.LastUsedDate
+			key.LastUsedService = aws.ToString(used.ServiceName)
+			key.LastUsedRegion = aws.ToString(used.Region)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// CreateAccessKey creates a new access key for a user
+func CreateAccessKey(username string) (*NewAccessKey, error) {
+	result, err := utils.GetIAMClient().CreateAccessKey(context.TODO(), &iam.CreateAccessKeyInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &NewAccessKey{
+		UserName:        aws.ToString(result.AccessKey.UserName),
+		AccessKeyID:     aws.ToString(result.AccessKey.AccessKeyId),
+		SecretAccessKey: aws.ToString(result.AccessKey.SecretAccessKey),
+	}, nil
+}
+
+// SetAccessKeyStatus activates or deactivates an access key
+func SetAccessKeyStatus(username, accessKeyID string, active bool) error {
+	status := types.StatusTypeInactive
+	if active {
+		status = types.StatusTypeActive
+	}
+
+	_, err := utils.GetIAMClient().UpdateAccessKey(context.TODO(), &iam.UpdateAccessKeyInput{
+		UserName:    aws.String(username),
+		AccessKeyId: aws.String(accessKeyID),
+		Status:      status,
+	})
+	return err
+}
+
+// DeleteAccessKey deletes an access key
+func DeleteAccessKey(username, accessKeyID string) error {
+	_, err := utils.GetIAMClient().DeleteAccessKey(context.TODO(), &iam.DeleteAccessKeyInput{
+		UserName:    aws.String(username),
+		AccessKeyId: aws.String(accessKeyID),
+	})
+	return err
+}
+
+// StartAccessKeyRotation checks that oldKeyID belongs to the user and creates the replacement key.
+// IAM allows two keys per user, so the user must not already have another key.
+func StartAccessKeyRotation(username, oldKeyID string) (*NewAccessKey, error) {
+	keys, err := FetchAccessKeys(username)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, key := range keys {
+		if key.AccessKeyID == oldKeyID {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%s of %s: %w", oldKeyID, username, ErrAccessKeyNotOwned)
+	}
+	if len(keys) >= maxAccessKeysPerUser {
+		return nil, fmt.Errorf("%w; delete the one not being rotated first", ErrAccessKeyLimit)
+	}
+
+	return CreateAccessKey(username)
+}
+
+// WaitForAccessKeyUse polls until the key's last-used date changes from what it was when the
+// wait started, meaning the key signed a request in the meantime, or ctx is done. It is used
+// during rotation to confirm the new key is deployed before the old one is removed.
+func WaitForAccessKeyUse(ctx context.Context, accessKeyID string, interval time.Duration) error {
+	var initial *time.Time
+	for first := true; ; first = false {
+		result, err := utils.GetIAMClient().GetAccessKeyLastUsed(ctx, &iam.GetAccessKeyLastUsedInput{
+			AccessKeyId: aws.String(accessKeyID),
+		})
+		if err != nil {
+			return err
+		}
+
+		var lastUsed *time.Time
+		if used := result.AccessKeyLastUsed; used != nil {
+			lastUsed = used.LastUsedDate
+		}
+		if first {
+			initial = lastUsed
+		} else if lastUsed != nil && (initial == nil || !lastUsed.Equal(*initial)) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("access key %s was not used yet", accessKeyID)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// FinishAccessKeyRotation deactivates the old key and, unless keepOld is set, deletes it.
+// Deletion is skipped when deactivation fails.
+func FinishAccessKeyRotation(username, oldKeyID string, keepOld bool) []UserStepResult {
+	err := SetAccessKeyStatus(username, oldKeyID, false)
+	steps := []UserStepResult{stepResult("deactivate access key", oldKeyID, err)}
+
+	if err == nil && !keepOld {
+		err = DeleteAccessKey(username, oldKeyID)
+		steps = append(steps, stepResult("delete access key", oldKeyID, err))
+	}
+
+	return steps
+}
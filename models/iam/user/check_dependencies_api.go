@@ -2,18 +2,26 @@ package user
 
 import (
 	"context"
+	"errors"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
 )
 
 type UserDependencies struct {
-	Groups          []string `json:"groups"`
-	ManagedPolicies []string `json:"managed_policies"`
-	InlinePolicies  []string `json:"inline_policies"`
-	AccessKeys      []string `json:"access_keys"`
-	HasLoginProfile bool     `json:"has_login_profile"`
+	Groups                     []string `json:"groups"`
+	ManagedPolicies            []string `json:"managed_policies"`
+	ManagedPolicyArns          []string `json:"managed_policy_arns"`
+	InlinePolicies             []string `json:"inline_policies"`
+	AccessKeys                 []string `json:"access_keys"`
+	HasLoginProfile            bool     `json:"has_login_profile"`
+	MFADevices                 []string `json:"mfa_devices"` // serial numbers, ARNs for virtual devices
+	SSHPublicKeys              []string `json:"ssh_public_keys"`
+	SigningCertificates        []string `json:"signing_certificates"`
+	ServiceSpecificCredentials []string `json:"service_specific_credentials"`
+	PermissionsBoundary        string   `json:"permissions_boundary"`
 }
 
 // CheckUserDependencies checks what dependencies a user has
@@ -22,51 +30,114 @@ func CheckUserDependencies(username string) (*UserDependencies, error) {
 	deps := &UserDependencies{}
 
 	// Check if user exists
-	_, err := utils.IAMClient.GetUser(ctx, &iam.GetUserInput{
+	userResult, err := utils.GetIAMClient().GetUser(ctx, &iam.GetUserInput{
 		UserName: aws.String(username),
 	})
 	if err != nil {
 		return nil, err
 	}
+	if boundary := userResult.User.PermissionsBoundary; boundary != nil {
+		deps.PermissionsBoundary = aws.ToString(boundary.PermissionsBoundaryArn)
+	}
 
 	// Get groups
-	groupsResult, _ := utils.IAMClient.ListGroupsForUser(ctx, &iam.ListGroupsForUserInput{
+	groupsResult, err := utils.GetIAMClient().ListGroupsForUser(ctx, &iam.ListGroupsForUserInput{
 		UserName: aws.String(username),
 	})
+	if err != nil {
+		return nil, err
+	}
 	for _, g := range groupsResult.Groups {
 		deps.Groups = append(deps.Groups, aws.ToString(g.GroupName))
 	}
 
 	// Get attached managed policies
-	policiesResult, _ := utils.IAMClient.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
+	policiesResult, err := utils.GetIAMClient().ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
 		UserName: aws.String(username),
 	})
+	if err != nil {
+		return nil, err
+	}
 	for _, p := range policiesResult.AttachedPolicies {
 		deps.ManagedPolicies = append(deps.ManagedPolicies, aws.ToString(p.PolicyName))
+		deps.ManagedPolicyArns = append(deps.ManagedPolicyArns, aws.ToString(p.PolicyArn))
 	}
 
 	// Get inline policies
-	inlineResult, _ := utils.IAMClient.ListUserPolicies(ctx, &iam.ListUserPoliciesInput{
+	inlineResult, err := utils.GetIAMClient().ListUserPolicies(ctx, &iam.ListUserPoliciesInput{
 		UserName: aws.String(username),
 	})
-	for _, p := range inlineResult.PolicyNames {
-		deps.InlinePolicies = append(deps.InlinePolicies, p)
+	if err != nil {
+		return nil, err
 	}
+	deps.InlinePolicies = append(deps.InlinePolicies, inlineResult.PolicyNames...)
 
 	// Get access keys
-	keysResult, _ := utils.IAMClient.ListAccessKeys(ctx, &iam.ListAccessKeysInput{
+	keysResult, err := utils.GetIAMClient().ListAccessKeys(ctx, &iam.ListAccessKeysInput{
 		UserName: aws.String(username),
 	})
+	if err != nil {
+		return nil, err
+	}
 	for _, k := range keysResult.AccessKeyMetadata {
 		deps.AccessKeys = append(deps.AccessKeys, aws.ToString(k.AccessKeyId))
 	}
 
 	// Check login profile
-	_, err = utils.IAMClient.GetLoginProfile(ctx, &iam.GetLoginProfileInput{
+	_, err = utils.GetIAMClient().GetLoginProfile(ctx, &iam.GetLoginProfileInput{
 		UserName: aws.String(username),
 	})
 	deps.HasLoginProfile = (err == nil)
 
+	// Get MFA devices
+	mfaResult, err := utils.GetIAMClient().ListMFADevices(ctx, &iam.ListMFADevicesInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range mfaResult.MFADevices {
+		deps.MFADevices = append(deps.MFADevices, aws.ToString(d.SerialNumber))
+	}
+
+	// Get SSH public keys
+	sshResult, err := utils.GetIAMClient().ListSSHPublicKeys(ctx, &iam.ListSSHPublicKeysInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range sshResult.SSHPublicKeys {
+		deps.SSHPublicKeys = append(deps.SSHPublicKeys, aws.ToString(k.SSHPublicKeyId))
+	}
+
+	// Get signing certificates
+	certsResult, err := utils.GetIAMClient().ListSigningCertificates(ctx, &iam.ListSigningCertificatesInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range certsResult.Certificates {
+		deps.SigningCertificates = append(deps.SigningCertificates, aws.ToString(c.CertificateId))
+	}
+
+	// Get service-specific credentials (CodeCommit, Keyspaces, ...)
+	credsResult, err := utils.GetIAMClient().ListServiceSpecificCredentials(ctx, &iam.ListServiceSpecificCredentialsInput{
+		UserName: aws.String(username),
+	})
+	if err != nil {
+		// Not every partition supports service-specific credentials
+		var unsupported *types.ServiceNotSupportedException
+		if !errors.As(err, &unsupported) {
+			return nil, err
+		}
+	} else {
+		for _, c := range credsResult.ServiceSpecificCredentials {
+			deps.ServiceSpecificCredentials = append(deps.ServiceSpecificCredentials, aws.ToString(c.ServiceSpecificCredentialId))
+		}
+	}
+
 	return deps, nil
 }
 
@@ -74,5 +145,7 @@ func CheckUserDependencies(username string) (*UserDependencies, error) {
 func (d *UserDependencies) HasDependencies() bool {
 	return len(d.Groups) > 0 || len(d.ManagedPolicies) > 0 ||
 		len(d.InlinePolicies) > 0 || len(d.AccessKeys) > 0 ||
-		d.HasLoginProfile
+		d.HasLoginProfile || len(d.MFADevices) > 0 ||
+		len(d.SSHPublicKeys) > 0 || len(d.SigningCertificates) > 0 ||
+		len(d.ServiceSpecificCredentials) > 0 || d.PermissionsBoundary != ""
 }
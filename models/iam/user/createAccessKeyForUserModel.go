@@ -25,7 +25,7 @@ func CreateAccessKeyForUserModel(username string) {
 
 	// Create access key using AWS SDK
 	ctx := context.TODO()
-	result, err := utils.IAMClient.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{
+	result, err := utils.GetIAMClient().CreateAccessKey(ctx, &iam.CreateAccessKeyInput{
 		UserName: aws.String(username),
 	})
 
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/DragonEmperor9480/aws_cli_manager/db_service"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
@@ -20,7 +21,7 @@ const (
 func CreateIAMUser(username string) (int, error) {
 	// Execute AWS SDK call
 	ctx := context.TODO()
-	_, err := utils.IAMClient.CreateUser(ctx, &iam.CreateUserInput{
+	_, err := utils.GetIAMClient().CreateUser(ctx, &iam.CreateUserInput{
 		UserName: aws.String(username),
 	})
 
@@ -50,26 +51,44 @@ func CreateIAMUserWithPassword(username, password string, requireReset bool) (in
 	return userStatus, passwordStatus, passwordErr
 }
 
-// UserCreationRequest represents a single user creation request
+// UserCreationRequest represents a single user creation request.
+// Without a Password one is generated, unless SkipPassword is set.
+// Generated passwords are saved to the credential store and emailed when Email is set.
 type UserCreationRequest struct {
 	Username     string
 	Password     string
 	RequireReset bool
+	SkipPassword bool
+	Email        string
 }
 
-// UserCreationResult represents the result of creating a single user
+// UserCreationResult represents the result of creating a single user.
+// Password is only returned when it was generated.
 type UserCreationResult struct {
-	Username       string
-	UserStatus     int
-	PasswordStatus int
-	Success        bool
-	Error          string
+	Username          string
+	UserStatus        int
+	PasswordStatus    int
+	Success           bool
+	Error             string
+	PasswordGenerated bool
+	Password          string           `json:",omitempty"`
+	Steps             []UserStepResult `json:",omitempty"`
 }
 
 // CreateMultipleIAMUsers creates multiple IAM users in parallel using goroutines
 func CreateMultipleIAMUsers(requests []UserCreationRequest) []UserCreationResult {
 	results := make([]UserCreationResult, len(requests))
 
+	// Read the password policy once, to reject weak passwords before their user is created and
+	// to generate missing ones. If it cannot be read, AWS validates each password instead.
+	var policy *PasswordPolicy
+	for _, req := range requests {
+		if !req.SkipPassword {
+			policy, _ = FetchPasswordPolicy()
+			break
+		}
+	}
+
 	// Use WaitGroup to wait for all goroutines to complete
 	var wg sync.WaitGroup
 
@@ -84,6 +103,26 @@ func CreateMultipleIAMUsers(requests []UserCreationRequest) []UserCreationResult
 				Username: request.Username,
 			}
 
+			if request.Password == "" && !request.SkipPassword {
+				password, err := generateBatchPassword(policy)
+				if err != nil {
+					result.Error = "Password generation failed: " + err.Error()
+					results[index] = result
+					return
+				}
+				request.Password = password
+				result.PasswordGenerated = true
+			}
+
+			if request.Password != "" && policy != nil {
+				if violations := policy.CheckPassword(request.Password); len(violations) > 0 {
+					result.PasswordStatus = PasswordPolicyViolation
+					result.Error = "Password policy violation: " + strings.Join(violations, "; ")
+					results[index] = result
+					return
+				}
+			}
+
 			// If password is provided, create user with password
 			if request.Password != "" {
 				userStatus, passwordStatus, err := CreateIAMUserWithPassword(
@@ -97,6 +136,10 @@ func CreateMultipleIAMUsers(requests []UserCreationRequest) []UserCreationResult
 
 				if userStatus == UserCreatedSuccess && passwordStatus == PasswordCreatedSuccess {
 					result.Success = true
+					if result.PasswordGenerated {
+						result.Password = request.Password
+						result.Steps = deliverPassword(request.Username, request.Password, request.Email)
+					}
 				} else {
 					result.Success = false
 					if err != nil {
@@ -134,6 +177,24 @@ func CreateMultipleIAMUsers(requests []UserCreationRequest) []UserCreationResult
 	return results
 }
 
+// generateBatchPassword generates a password for the policy read at the start of the batch
+func generateBatchPassword(policy *PasswordPolicy) (string, error) {
+	if policy == nil {
+		return GenerateCompliantPassword()
+	}
+	return GeneratePassword(*policy)
+}
+
+// deliverPassword saves a password set on a user to the credential store, so it can be looked
+// up later like any other, and emails it when an address is given
+func deliverPassword(username, password, email string) []UserStepResult {
+	steps := []UserStepResult{stepResult("save credentials", username, db_service.SaveUserCredential(username, password))}
+	if email != "" {
+		steps = append(steps, stepResult("email credentials", email, EmailUserCredentials(username, password, email)))
+	}
+	return steps
+}
+
 // Helper function to get error message from status codes
 func getErrorMessage(userStatus, passwordStatus int) string {
 	if userStatus != UserCreatedSuccess {
@@ -0,0 +1,295 @@
+package user
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// credentialReportTimeout bounds how long report generation is waited for
+const credentialReportTimeout = 2 * time.Minute
+
+// Sort keys accepted by SortCredentialReport
+const (
+	CredentialSortUser             = "user"
+	CredentialSortCreated          = "created"
+	CredentialSortPasswordLastUsed = "password-last-used"
+	CredentialSortMFA              = "mfa"
+	CredentialSortKeyAge           = "key-age"
+)
+
+// CredentialSortKeys lists the valid sort keys in display order
+var CredentialSortKeys = []string{CredentialSortUser, CredentialSortCreated, CredentialSortPasswordLastUsed, CredentialSortMFA, CredentialSortKeyAge}
+
+// CredentialReportKey is the access key part of a credential report row
+type CredentialReportKey struct {
+	Active          bool       `json:"active" yaml:"active"`
+	LastRotated     *time.Time `json:"last_rotated" yaml:"last_rotated"`
+	LastUsedDate    *time.Time `json:"last_used_date" yaml:"last_used_date"`
+	LastUsedRegion  string     `json:"last_used_region" yaml:"last_used_region"`
+	LastUsedService string     `json:"last_used_service" yaml:"last_used_service"`
+}
+
+// CredentialReportCert is the signing certificate part of a credential report row
+type CredentialReportCert struct {
+	Active      bool       `json:"active" yaml:"active"`
+	LastRotated *time.Time `json:"last_rotated" yaml:"last_rotated"`
+}
+
+// CredentialReportRow is one user of the IAM credential report.
+// Times are nil where the report says N/A, no_information or not_supported.
+type CredentialReportRow struct {
+	User                 string               `json:"user" yaml:"user"`
+	ARN                  string               `json:"arn" yaml:"arn"`
+	UserCreationTime     *time.Time           `json:"user_creation_time" yaml:"user_creation_time"`
+	PasswordEnabled      bool                 `json:"password_enabled" yaml:"password_enabled"`
+	PasswordLastUsed     *time.Time           `json:"password_last_used" yaml:"password_last_used"`
+	PasswordLastChanged  *time.Time           `json:"password_last_changed" yaml:"password_last_changed"`
+	PasswordNextRotation *time.Time           `json:"password_next_rotation" yaml:"password_next_rotation"`
+	MFAActive            bool                 `json:"mfa_active" yaml:"mfa_active"`
+	AccessKey1           CredentialReportKey  `json:"access_key_1" yaml:"access_key_1"`
+	AccessKey2           CredentialReportKey  `json:"access_key_2" yaml:"access_key_2"`
+	Cert1                CredentialReportCert `json:"cert_1" yaml:"cert_1"`
+	Cert2                CredentialReportCert `json:"cert_2" yaml:"cert_2"`
+}
+
+// CredentialReportTableHeaders are the column headers matching CredentialReportRow.TableRow
+var CredentialReportTableHeaders = []string{"User", "Created", "Console", "Password Last Used", "MFA", "Key 1", "Key 1 Last Used", "Key 2", "Key 2 Last Used", "Certificates"}
+
+// TableRow returns the report row as a table row
+func (r CredentialReportRow) TableRow() []string {
+	console := "No"
+	if r.PasswordEnabled {
+		console = "Yes"
+	}
+	mfa := "No"
+	if r.MFAActive {
+		mfa = "Yes"
+	}
+
+	certs := 0
+	for _, cert := range []CredentialReportCert{r.Cert1, r.Cert2} {
+		if cert.Active {
+			certs++
+		}
+	}
+
+	return []string{r.User, formatReportDate(r.UserCreationTime), console, formatReportDate(r.PasswordLastUsed), mfa,
+		formatReportKey(r.AccessKey1), formatReportDate(r.AccessKey1.LastUsedDate),
+		formatReportKey(r.AccessKey2), formatReportDate(r.AccessKey2.LastUsedDate),
+		fmt.Sprintf("%d active", certs)}
+}
+
+// ConsoleWithoutMFA reports whether the user can sign in to the console without MFA
+func (r CredentialReportRow) ConsoleWithoutMFA() bool {
+	return r.PasswordEnabled && !r.MFAActive
+}
+
+// OldestActiveKeyAgeDays returns the age in days of the user's oldest active key, or -1 without one
+func (r CredentialReportRow) OldestActiveKeyAgeDays() int {
+	oldest := -1
+	for _, key := range []CredentialReportKey{r.AccessKey1, r.AccessKey2} {
+		if key.Active && key.LastRotated != nil {
+			if age := daysBetween(*key.LastRotated, time.Now()); age > oldest {
+				oldest = age
+			}
+		}
+	}
+	return oldest
+}
+
+func formatReportDate(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+func formatReportKey(key CredentialReportKey) string {
+	if !key.Active {
+		return "-"
+	}
+	if key.LastRotated == nil {
+		return "active"
+	}
+	return fmt.Sprintf("active, %dd", daysBetween(*key.LastRotated, time.Now()))
+}
+
+// FetchCredentialReport generates a fresh credential report, waits for it and parses it
+func FetchCredentialReport() ([]CredentialReportRow, time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.TODO(), credentialReportTimeout)
+	defer cancel()
+
+	for {
+		result, err := utils.GetIAMClient().GenerateCredentialReport(ctx, &iam.GenerateCredentialReportInput{})
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		if result.State == types.ReportStateTypeComplete {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, time.Time{}, errors.New("timed out waiting for the credential report to be generated")
+		case <-time.After(2 * time.Second):
+		}
+	}
+
+	report, err := utils.GetIAMClient().GetCredentialReport(ctx, &iam.GetCredentialReportInput{})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	rows, err := ParseCredentialReport(string(report.Content))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	generated := time.Now()
+	if report.GeneratedTime != nil {
+		generated = *report.GeneratedTime
+	}
+	return rows, generated, nil
+}
+
+// ParseCredentialReport parses the CSV content of a credential report into typed rows.
+// Columns are looked up by header name, so column order changes are tolerated.
+func ParseCredentialReport(content string) ([]CredentialReportRow, error) {
+	records, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse credential report: %v", err)
+	}
+	if len(records) == 0 {
+		return []CredentialReportRow{}, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[name] = i
+	}
+	if _, ok := columns["user"]; !ok {
+		return nil, errors.New("credential report has no 'user' column")
+	}
+
+	rows := make([]CredentialReportRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		key := func(n string) CredentialReportKey {
+			prefix := "access_key_" + n + "_"
+			return CredentialReportKey{
+				Active:          field(prefix+"active") == "true",
+				LastRotated:     parseReportTime(field(prefix + "last_rotated")),
+				LastUsedDate:    parseReportTime(field(prefix + "last_used_date")),
+				LastUsedRegion:  parseReportText(field(prefix + "last_used_region")),
+				LastUsedService: parseReportText(field(prefix + "last_used_service")),
+			}
+		}
+		cert := func(n string) CredentialReportCert {
+			prefix := "cert_" + n + "_"
+			return CredentialReportCert{
+				Active:      field(prefix+"active") == "true",
+				LastRotated: parseReportTime(field(prefix + "last_rotated")),
+			}
+		}
+
+		rows = append(rows, CredentialReportRow{
+			User:                 field("user"),
+			ARN:                  field("arn"),
+			UserCreationTime:     parseReportTime(field("user_creation_time")),
+			PasswordEnabled:      field("password_enabled") == "true",
+			PasswordLastUsed:     parseReportTime(field("password_last_used")),
+			PasswordLastChanged:  parseReportTime(field("password_last_changed")),
+			PasswordNextRotation: parseReportTime(field("password_next_rotation")),
+			MFAActive:            field("mfa_active") == "true",
+			AccessKey1:           key("1"),
+			AccessKey2:           key("2"),
+			Cert1:                cert("1"),
+			Cert2:                cert("2"),
+		})
+	}
+
+	return rows, nil
+}
+
+// parseReportTime parses a report timestamp, returning nil for N/A and similar markers
+func parseReportTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// parseReportText returns value, or "" for the report's N/A marker
+func parseReportText(value string) string {
+	if value == "N/A" {
+		return ""
+	}
+	return value
+}
+
+// FilterConsoleWithoutMFA keeps the users that can sign in to the console without MFA
+func FilterConsoleWithoutMFA(rows []CredentialReportRow) []CredentialReportRow {
+	filtered := []CredentialReportRow{}
+	for _, row := range rows {
+		if row.ConsoleWithoutMFA() {
+			filtered = append(filtered, row)
+		}
+	}
+	return filtered
+}
+
+// SortCredentialReport sorts rows in place by one of CredentialSortKeys.
+// Dates and key ages sort most recent/oldest first, with missing values last.
+func SortCredentialReport(rows []CredentialReportRow, by string) error {
+	var less func(a, b CredentialReportRow) bool
+
+	switch by {
+	case CredentialSortUser:
+		less = func(a, b CredentialReportRow) bool { return a.User < b.User }
+	case CredentialSortCreated:
+		less = func(a, b CredentialReportRow) bool { return timeBefore(a.UserCreationTime, b.UserCreationTime) }
+	case CredentialSortPasswordLastUsed:
+		less = func(a, b CredentialReportRow) bool { return timeAfter(a.PasswordLastUsed, b.PasswordLastUsed) }
+	case CredentialSortMFA:
+		// Console users without MFA first
+		less = func(a, b CredentialReportRow) bool { return a.ConsoleWithoutMFA() && !b.ConsoleWithoutMFA() }
+	case CredentialSortKeyAge:
+		less = func(a, b CredentialReportRow) bool { return a.OldestActiveKeyAgeDays() > b.OldestActiveKeyAgeDays() }
+	default:
+		return fmt.Errorf("unknown sort key '%s' (use %s)", by, strings.Join(CredentialSortKeys, ", "))
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return less(rows[i], rows[j]) })
+	return nil
+}
+
+// timeBefore orders oldest first with nil last
+func timeBefore(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a != nil
+	}
+	return a.Before(*b)
+}
+
+// timeAfter orders most recent first with nil last
+func timeAfter(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a != nil
+	}
+	return a.After(*b)
+}
@@ -0,0 +1,110 @@
+package user
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCredentialReport(t *testing.T) {
+	at := func(value string) *time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &parsed
+	}
+
+	header := "user,arn,user_creation_time,password_enabled,password_last_used,password_last_changed,password_next_rotation,mfa_active," +
+		"access_key_1_active,access_key_1_last_rotated,access_key_1_last_used_date,access_key_1_last_used_region,access_key_1_last_used_service," +
+		"access_key_2_active,access_key_2_last_rotated,access_key_2_last_used_date,access_key_2_last_used_region,access_key_2_last_used_service," +
+		"cert_1_active,cert_1_last_rotated,cert_2_active,cert_2_last_rotated\n"
+
+	tests := []struct {
+		name    string
+		content string
+		want    []CredentialReportRow
+		wantErr string
+	}{
+		{
+			name: "full row",
+			content: header +
+				"alice,arn:aws:iam::123456789012:user/alice,2024-01-02T03:04:05+00:00,true,2024-06-01T10:00:00+00:00,2024-01-02T03:04:05+00:00,N/A,true," +
+				"true,2024-01-03T00:00:00+00:00,2024-06-02T00:00:00+00:00,us-east-1,s3," +
+				"false,N/A,N/A,N/A,N/A," +
+				"true,2024-02-01T00:00:00+00:00,false,N/A\n",
+			want: []CredentialReportRow{{
+				User:                "alice",
+				ARN:                 "arn:aws:iam::123456789012:user/alice",
+				UserCreationTime:    at("2024-01-02T03:04:05+00:00"),
+				PasswordEnabled:     true,
+				PasswordLastUsed:    at("2024-06-01T10:00:00+00:00"),
+				PasswordLastChanged: at("2024-01-02T03:04:05+00:00"),
+				MFAActive:           true,
+				AccessKey1: CredentialReportKey{
+					Active:          true,
+					LastRotated:     at("2024-01-03T00:00:00+00:00"),
+					LastUsedDate:    at("2024-06-02T00:00:00+00:00"),
+					LastUsedRegion:  "us-east-1",
+					LastUsedService: "s3",
+				},
+				Cert1: CredentialReportCert{Active: true, LastRotated: at("2024-02-01T00:00:00+00:00")},
+			}},
+		},
+		{
+			name: "root account markers are empty",
+			content: header +
+				"<root_account>,arn:aws:iam::123456789012:root,2020-01-01T00:00:00+00:00,not_supported,no_information,not_supported,not_supported,false," +
+				"false,N/A,N/A,N/A,N/A,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A\n",
+			want: []CredentialReportRow{{
+				User:             "<root_account>",
+				ARN:              "arn:aws:iam::123456789012:root",
+				UserCreationTime: at("2020-01-01T00:00:00+00:00"),
+			}},
+		},
+		{
+			name:    "columns are found by name",
+			content: "mfa_active,user,password_enabled\ntrue,bob,false\n",
+			want:    []CredentialReportRow{{User: "bob", MFAActive: true}},
+		},
+		{
+			name:    "empty report",
+			content: "",
+			want:    []CredentialReportRow{},
+		},
+		{
+			name:    "header only",
+			content: header,
+			want:    []CredentialReportRow{},
+		},
+		{
+			name:    "no user column",
+			content: "arn,mfa_active\narn:aws:iam::123456789012:user/alice,true\n",
+			wantErr: "credential report has no 'user' column",
+		},
+		{
+			name:    "malformed CSV",
+			content: "user,arn\nalice\n",
+			wantErr: "failed to parse credential report",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCredentialReport(tt.content)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseCredentialReport() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCredentialReport() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCredentialReport() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -9,89 +9,156 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
-// DeleteIAMUserAPI deletes an IAM user without interactive prompts (for API use)
-// It automatically removes all dependencies
-func DeleteIAMUserAPI(username string) error {
-	ctx := context.TODO()
-
-	// Check if user exists
-	_, err := utils.IAMClient.GetUser(ctx, &iam.GetUserInput{
-		UserName: aws.String(username),
-	})
+// UserStepResult is the outcome of one step of a multi-step user operation such as a teardown or key rotation
+type UserStepResult struct {
+	Action   string `json:"action"`
+	Resource string `json:"resource"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
 
+// DeleteIAMUserAPI deletes an IAM user without interactive prompts (for API use)
+// It automatically removes all dependencies and returns the result of every step
+func DeleteIAMUserAPI(username string) ([]UserStepResult, error) {
+	deps, err := CheckUserDependencies(username)
 	if err != nil {
-		if strings.Contains(err.Error(), "NoSuchEntity") {
-			return err
-		}
-		return err
+		return nil, err
 	}
 
-	// Get groups
-	groupsResult, _ := utils.IAMClient.ListGroupsForUser(ctx, &iam.ListGroupsForUserInput{
-		UserName: aws.String(username),
-	})
+	steps := RemoveUserDependencies(username, deps)
 
-	// Get attached managed policies
-	policiesResult, _ := utils.IAMClient.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
+	// Delete the IAM user
+	_, err = utils.GetIAMClient().DeleteUser(context.TODO(), &iam.DeleteUserInput{
 		UserName: aws.String(username),
 	})
+	steps = append(steps, stepResult("delete user", username, err))
 
-	// Get inline policies
-	inlineResult, _ := utils.IAMClient.ListUserPolicies(ctx, &iam.ListUserPoliciesInput{
-		UserName: aws.String(username),
-	})
+	return steps, err
+}
 
-	// Get access keys
-	keysResult, _ := utils.IAMClient.ListAccessKeys(ctx, &iam.ListAccessKeysInput{
-		UserName: aws.String(username),
-	})
+// RemoveUserDependencies removes every sub-resource that blocks DeleteUser.
+// Credentials go first so the user cannot act while the rest is cleaned up.
+// Failed steps are reported and do not stop the remaining ones.
+func RemoveUserDependencies(username string, deps *UserDependencies) []UserStepResult {
+	ctx := context.TODO()
+	user := aws.String(username)
+	steps := []UserStepResult{}
+
+	// Delete login profile
+	if deps.HasLoginProfile {
+		_, err := utils.GetIAMClient().DeleteLoginProfile(ctx, &iam.DeleteLoginProfileInput{UserName: user})
+		steps = append(steps, stepResult("delete login profile", username, err))
+	}
+
+	// Delete access keys
+	for _, k := range deps.AccessKeys {
+		_, err := utils.GetIAMClient().DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
+			UserName:    user,
+			AccessKeyId: aws.String(k),
+		})
+		steps = append(steps, stepResult("delete access key", k, err))
+	}
+
+	// Delete signing certificates
+	for _, c := range deps.SigningCertificates {
+		_, err := utils.GetIAMClient().DeleteSigningCertificate(ctx, &iam.DeleteSigningCertificateInput{
+			UserName:      user,
+			CertificateId: aws.String(c),
+		})
+		steps = append(steps, stepResult("delete signing certificate", c, err))
+	}
+
+	// Delete SSH public keys
+	for _, k := range deps.SSHPublicKeys {
+		_, err := utils.GetIAMClient().DeleteSSHPublicKey(ctx, &iam.DeleteSSHPublicKeyInput{
+			UserName:       user,
+			SSHPublicKeyId: aws.String(k),
+		})
+		steps = append(steps, stepResult("delete SSH public key", k, err))
+	}
 
-	// Remove user from all groups
-	for _, g := range groupsResult.Groups {
-		utils.IAMClient.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
-			UserName:  aws.String(username),
-			GroupName: g.GroupName,
+	// Delete service-specific credentials
+	for _, c := range deps.ServiceSpecificCredentials {
+		_, err := utils.GetIAMClient().DeleteServiceSpecificCredential(ctx, &iam.DeleteServiceSpecificCredentialInput{
+			UserName:                    user,
+			ServiceSpecificCredentialId: aws.String(c),
 		})
+		steps = append(steps, stepResult("delete service-specific credential", c, err))
 	}
 
-	// Detach all managed policies
-	for _, p := range policiesResult.AttachedPolicies {
-		utils.IAMClient.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
-			UserName:  aws.String(username),
-			PolicyArn: p.PolicyArn,
+	// Deactivate MFA devices, and delete the virtual ones
+	for _, serial := range deps.MFADevices {
+		_, err := utils.GetIAMClient().DeactivateMFADevice(ctx, &iam.DeactivateMFADeviceInput{
+			UserName:     user,
+			SerialNumber: aws.String(serial),
 		})
+		steps = append(steps, stepResult("deactivate MFA device", serial, err))
+
+		if err == nil && isVirtualMFADevice(serial) {
+			_, err = utils.GetIAMClient().DeleteVirtualMFADevice(ctx, &iam.DeleteVirtualMFADeviceInput{
+				SerialNumber: aws.String(serial),
+			})
+			steps = append(steps, stepResult("delete virtual MFA device", serial, err))
+		}
 	}
 
-	// Delete all inline policies
-	for _, p := range inlineResult.PolicyNames {
-		utils.IAMClient.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
-			UserName:   aws.String(username),
+	// Delete inline policies
+	for _, p := range deps.InlinePolicies {
+		_, err := utils.GetIAMClient().DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
+			UserName:   user,
 			PolicyName: aws.String(p),
 		})
+		steps = append(steps, stepResult("delete inline policy", p, err))
 	}
 
-	// Delete access keys
-	for _, k := range keysResult.AccessKeyMetadata {
-		utils.IAMClient.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
-			UserName:    aws.String(username),
-			AccessKeyId: k.AccessKeyId,
+	// Detach managed policies
+	for _, arn := range deps.ManagedPolicyArns {
+		_, err := utils.GetIAMClient().DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
+			UserName:  user,
+			PolicyArn: aws.String(arn),
 		})
+		steps = append(steps, stepResult("detach managed policy", arn, err))
 	}
 
-	// Delete login profile if exists
-	_, err = utils.IAMClient.GetLoginProfile(ctx, &iam.GetLoginProfileInput{
-		UserName: aws.String(username),
-	})
-	if err == nil {
-		utils.IAMClient.DeleteLoginProfile(ctx, &iam.DeleteLoginProfileInput{
-			UserName: aws.String(username),
+	// Remove permissions boundary
+	if deps.PermissionsBoundary != "" {
+		_, err := utils.GetIAMClient().DeleteUserPermissionsBoundary(ctx, &iam.DeleteUserPermissionsBoundaryInput{UserName: user})
+		steps = append(steps, stepResult("remove permissions boundary", deps.PermissionsBoundary, err))
+	}
+
+	// Remove user from groups
+	for _, g := range deps.Groups {
+		_, err := utils.GetIAMClient().RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
+			UserName:  user,
+			GroupName: aws.String(g),
 		})
+		steps = append(steps, stepResult("remove from group", g, err))
 	}
 
-	// Delete the IAM user
-	_, err = utils.IAMClient.DeleteUser(ctx, &iam.DeleteUserInput{
-		UserName: aws.String(username),
-	})
+	return steps
+}
+
+// FailedSteps returns the steps that did not succeed
+func FailedSteps(steps []UserStepResult) []UserStepResult {
+	failed := []UserStepResult{}
+	for _, step := range steps {
+		if !step.Success {
+			failed = append(failed, step)
+		}
+	}
+	return failed
+}
+
+// isVirtualMFADevice reports whether a serial number belongs to a virtual MFA device.
+// Hardware tokens use plain serials and security keys use u2f ARNs.
+func isVirtualMFADevice(serial string) bool {
+	return strings.HasPrefix(serial, "arn:") && strings.Contains(serial, ":mfa/")
+}
 
-	return err
+func stepResult(action, resource string, err error) UserStepResult {
+	step := UserStepResult{Action: action, Resource: resource, Success: err == nil}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	return step
 }
@@ -17,86 +17,22 @@ func DeleteIAMUser(username string) {
 	ctx := context.TODO()
 	reader := bufio.NewReader(os.Stdin)
 
-	utils.ShowProcessingAnimation("Processing")
-
-	// Check if user exists
-	_, err := utils.IAMClient.GetUser(ctx, &iam.GetUserInput{
-		UserName: aws.String(username),
-	})
-
+	utils.ShowProcessingAnimation("Checking IAM User dependencies...")
+	deps, err := CheckUserDependencies(username)
 	utils.StopAnimation()
 
-	if err != nil && strings.Contains(err.Error(), "NoSuchEntity") {
-		fmt.Println(utils.Bold + utils.Red + "Error: User '" + username + "' does not exist!" + utils.Reset)
+	if err != nil {
+		if strings.Contains(err.Error(), "NoSuchEntity") {
+			fmt.Println(utils.Bold + utils.Red + "Error: User '" + username + "' does not exist!" + utils.Reset)
+		} else {
+			fmt.Println(utils.Red + "Error checking dependencies: " + err.Error() + utils.Reset)
+		}
 		return
 	}
 
-	utils.ShowProcessingAnimation("Checking IAM User dependencies...")
-
-	// Get groups
-	groupsResult, _ := utils.IAMClient.ListGroupsForUser(ctx, &iam.ListGroupsForUserInput{
-		UserName: aws.String(username),
-	})
-	var groups []string
-	for _, g := range groupsResult.Groups {
-		groups = append(groups, aws.ToString(g.GroupName))
-	}
-
-	// Get attached managed policies
-	policiesResult, _ := utils.IAMClient.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
-		UserName: aws.String(username),
-	})
-	var policies []string
-	var policyArns []string
-	for _, p := range policiesResult.AttachedPolicies {
-		policies = append(policies, aws.ToString(p.PolicyName))
-		policyArns = append(policyArns, aws.ToString(p.PolicyArn))
-	}
-
-	// Get inline policies
-	inlineResult, _ := utils.IAMClient.ListUserPolicies(ctx, &iam.ListUserPoliciesInput{
-		UserName: aws.String(username),
-	})
-	inlinePolicies := inlineResult.PolicyNames
-
-	// Get access keys
-	keysResult, _ := utils.IAMClient.ListAccessKeys(ctx, &iam.ListAccessKeysInput{
-		UserName: aws.String(username),
-	})
-	var accessKeys []string
-	for _, k := range keysResult.AccessKeyMetadata {
-		accessKeys = append(accessKeys, aws.ToString(k.AccessKeyId))
-	}
-
-	utils.StopAnimation()
-
-	if len(groups) > 0 || len(policies) > 0 || len(inlinePolicies) > 0 || len(accessKeys) > 0 {
+	if deps.HasDependencies() {
 		fmt.Println(utils.Yellow + "User '" + username + "' has the following dependencies:" + utils.Reset)
-
-		if len(groups) > 0 {
-			fmt.Println(utils.Bold + "Groups:" + utils.Reset)
-			for _, g := range groups {
-				fmt.Println("  - " + g)
-			}
-		}
-		if len(policies) > 0 {
-			fmt.Println(utils.Bold + "Managed Policies:" + utils.Reset)
-			for _, p := range policies {
-				fmt.Println("  - " + p)
-			}
-		}
-		if len(inlinePolicies) > 0 {
-			fmt.Println(utils.Bold + "Inline Policies:" + utils.Reset)
-			for _, p := range inlinePolicies {
-				fmt.Println("  - " + p)
-			}
-		}
-		if len(accessKeys) > 0 {
-			fmt.Println(utils.Bold + "Access Keys:" + utils.Reset)
-			for _, k := range accessKeys {
-				fmt.Println("  - " + k)
-			}
-		}
+		printUserDependencies(deps)
 
 		fmt.Print(utils.Red + "Do you want to remove all dependencies and delete the user? (y/N): " + utils.Reset)
 		answer, _ := reader.ReadString('\n')
@@ -108,60 +44,15 @@ func DeleteIAMUser(username string) {
 		}
 
 		utils.ShowProcessingAnimation("Cleaning up IAM User dependencies")
+		steps := RemoveUserDependencies(username, deps)
+		utils.StopAnimation()
 
-		// Remove user from all groups
-		for _, g := range groups {
-			utils.IAMClient.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
-				UserName:  aws.String(username),
-				GroupName: aws.String(g),
-			})
-		}
-
-		// Detach all managed policies
-		for _, arn := range policyArns {
-			utils.IAMClient.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
-				UserName:  aws.String(username),
-				PolicyArn: aws.String(arn),
-			})
-		}
-
-		// Delete all inline policies
-		for _, p := range inlinePolicies {
-			utils.IAMClient.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
-				UserName:   aws.String(username),
-				PolicyName: aws.String(p),
-			})
-		}
-
-		// Delete access keys
-		for _, k := range accessKeys {
-			utils.IAMClient.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
-				UserName:    aws.String(username),
-				AccessKeyId: aws.String(k),
-			})
-		}
-	}
-
-	utils.StopAnimation()
-
-	// Delete login profile if exists
-	_, err = utils.IAMClient.GetLoginProfile(ctx, &iam.GetLoginProfileInput{
-		UserName: aws.String(username),
-	})
-	if err == nil {
-		fmt.Print("Would you like to delete the login profile for the user? (y/N): ")
-		deleteLoginProfileChoice, _ := reader.ReadString('\n')
-		deleteLoginProfileChoice = strings.ToLower(strings.TrimSpace(deleteLoginProfileChoice))
-		if deleteLoginProfileChoice == "y" || deleteLoginProfileChoice == "yes" {
-			utils.IAMClient.DeleteLoginProfile(ctx, &iam.DeleteLoginProfileInput{
-				UserName: aws.String(username),
-			})
-		}
+		printTeardownSteps(steps)
 	}
 
 	// Delete the IAM user
 	utils.ShowProcessingAnimation("Deleting IAM User")
-	_, err = utils.IAMClient.DeleteUser(ctx, &iam.DeleteUserInput{
+	_, err = utils.GetIAMClient().DeleteUser(ctx, &iam.DeleteUserInput{
 		UserName: aws.String(username),
 	})
 	utils.StopAnimation()
@@ -180,6 +71,52 @@ func DeleteIAMUser(username string) {
 	fmt.Println(utils.Bold + utils.Green + "User '" + username + "' deleted successfully!" + utils.Reset)
 }
 
+// printUserDependencies lists every sub-resource that blocks deleting a user
+func printUserDependencies(deps *UserDependencies) {
+	sections := []struct {
+		title string
+		items []string
+	}{
+		{"Groups:", deps.Groups},
+		{"Managed Policies:", deps.ManagedPolicies},
+		{"Inline Policies:", deps.InlinePolicies},
+		{"Access Keys:", deps.AccessKeys},
+		{"MFA Devices:", deps.MFADevices},
+		{"SSH Public Keys:", deps.SSHPublicKeys},
+		{"Signing Certificates:", deps.SigningCertificates},
+		{"Service-Specific Credentials:", deps.ServiceSpecificCredentials},
+	}
+
+	for _, section := range sections {
+		if len(section.items) == 0 {
+			continue
+		}
+		fmt.Println(utils.Bold + section.title + utils.Reset)
+		for _, item := range section.items {
+			fmt.Println("  - " + item)
+		}
+	}
+
+	if deps.PermissionsBoundary != "" {
+		fmt.Println(utils.Bold + "Permissions Boundary:" + utils.Reset)
+		fmt.Println("  - " + deps.PermissionsBoundary)
+	}
+	if deps.HasLoginProfile {
+		fmt.Println(utils.Bold + "Login Profile:" + utils.Reset + " console password set")
+	}
+}
+
+// printTeardownSteps prints the result of every cleanup step
+func printTeardownSteps(steps []UserStepResult) {
+	for _, step := range steps {
+		if step.Success {
+			fmt.Println(utils.Green + "  ✓ " + step.Action + ": " + step.Resource + utils.Reset)
+		} else {
+			fmt.Println(utils.Red + "  ✗ " + step.Action + ": " + step.Resource + " (" + step.Error + ")" + utils.Reset)
+		}
+	}
+}
+
 // UserDependenciesResult represents dependencies check result for a single user
 type UserDependenciesResult struct {
 	Username     string            `json:"username"`
@@ -196,9 +133,10 @@ type UserDeletionRequest struct {
 
 // UserDeletionResult represents the result of deleting a user
 type UserDeletionResult struct {
-	Username string `json:"Username"`
-	Success  bool   `json:"Success"`
-	Error    string `json:"Error"`
+	Username string           `json:"Username"`
+	Success  bool             `json:"Success"`
+	Error    string           `json:"Error"`
+	Steps    []UserStepResult `json:"Steps"`
 }
 
 // CheckMultipleUserDependencies checks dependencies for multiple users in parallel
@@ -226,14 +164,7 @@ func CheckMultipleUserDependencies(usernames []string) []UserDependenciesResult
 					result.Error = err.Error()
 				}
 			} else {
-				// Get policy ARNs for managed policies
-				ctx := context.TODO()
-				policiesResult, _ := utils.IAMClient.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
-					UserName: aws.String(user),
-				})
-				for _, p := range policiesResult.AttachedPolicies {
-					result.PolicyArns = append(result.PolicyArns, aws.ToString(p.PolicyArn))
-				}
+				result.PolicyArns = deps.ManagedPolicyArns
 			}
 
 			results[index] = result
@@ -262,55 +193,14 @@ func DeleteMultipleIAMUsers(requests []UserDeletionRequest) []UserDeletionResult
 
 			// If force is true, remove all dependencies first
 			if request.Force {
-				// Get dependencies
 				deps, err := CheckUserDependencies(request.Username)
-				if err == nil && deps != nil {
-					// Remove from groups
-					for _, g := range deps.Groups {
-						utils.IAMClient.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{
-							UserName:  aws.String(request.Username),
-							GroupName: aws.String(g),
-						})
-					}
-
-					// Get policy ARNs and detach
-					policiesResult, _ := utils.IAMClient.ListAttachedUserPolicies(ctx, &iam.ListAttachedUserPoliciesInput{
-						UserName: aws.String(request.Username),
-					})
-					for _, p := range policiesResult.AttachedPolicies {
-						utils.IAMClient.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{
-							UserName:  aws.String(request.Username),
-							PolicyArn: p.PolicyArn,
-						})
-					}
-
-					// Delete inline policies
-					for _, p := range deps.InlinePolicies {
-						utils.IAMClient.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
-							UserName:   aws.String(request.Username),
-							PolicyName: aws.String(p),
-						})
-					}
-
-					// Delete access keys
-					for _, k := range deps.AccessKeys {
-						utils.IAMClient.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
-							UserName:    aws.String(request.Username),
-							AccessKeyId: aws.String(k),
-						})
-					}
-
-					// Delete login profile
-					if deps.HasLoginProfile {
-						utils.IAMClient.DeleteLoginProfile(ctx, &iam.DeleteLoginProfileInput{
-							UserName: aws.String(request.Username),
-						})
-					}
+				if err == nil {
+					result.Steps = RemoveUserDependencies(request.Username, deps)
 				}
 			}
 
 			// Delete the user
-			_, err := utils.IAMClient.DeleteUser(ctx, &iam.DeleteUserInput{
+			_, err := utils.GetIAMClient().DeleteUser(ctx, &iam.DeleteUserInput{
 				UserName: aws.String(request.Username),
 			})
 
@@ -326,6 +216,7 @@ func DeleteMultipleIAMUsers(requests []UserDeletionRequest) []UserDeletionResult
 			} else {
 				result.Success = true
 			}
+			result.Steps = append(result.Steps, stepResult("delete user", request.Username, err))
 
 			results[index] = result
 		}(i, req)
@@ -0,0 +1,409 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/group"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/tag"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// importWorkers bounds how many users are onboarded at once, to stay clear of IAM throttling
+const importWorkers = 5
+
+// ErrInvalidImport is returned by ImportUsers when the rows themselves are at fault: they fail
+// validation, name unknown policies or give passwords the account policy rejects
+var ErrInvalidImport = errors.New("invalid import")
+
+// UserImportColumns are the CSV columns understood by ParseUserImportCSV. Only username is
+// required. Groups, policies and tags hold several values separated by ';', tags as key=value.
+var UserImportColumns = []string{
+	"username", "email", "groups", "policies", "tags",
+	"password", "generate_password", "require_reset", "email_credentials", "save_password",
+}
+
+// UserImportRow describes one user to onboard. Policies are managed policy ARNs or names.
+// A user gets console access when Password is given or GeneratePassword is set, and the
+// password is emailed to Email when EmailCredentials is set. Generated passwords are always
+// saved to the credential store, given ones only when SavePassword is set.
+type UserImportRow struct {
+	Username         string            `json:"username" yaml:"username"`
+	Email            string            `json:"email,omitempty" yaml:"email,omitempty"`
+	Groups           []string          `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Policies         []string          `json:"policies,omitempty" yaml:"policies,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Password         string            `json:"password,omitempty" yaml:"-"`
+	GeneratePassword bool              `json:"generate_password" yaml:"generate_password"`
+	RequireReset     bool              `json:"require_reset" yaml:"require_reset"`
+	EmailCredentials bool              `json:"email_credentials" yaml:"email_credentials"`
+	SavePassword     bool              `json:"save_password" yaml:"save_password"`
+}
+
+// UserImportRowTableHeaders are the column headers matching UserImportRow.TableRow
+var UserImportRowTableHeaders = []string{"Username", "Email", "Groups", "Policies", "Tags", "Password"}
+
+// TableRow returns the row as a table row, without the password itself
+func (r UserImportRow) TableRow() []string {
+	password := "none"
+	switch {
+	case r.GeneratePassword:
+		password = "generate"
+	case r.Password != "":
+		password = "given"
+	}
+	if r.RequireReset && password != "none" {
+		password += ", reset"
+	}
+	if r.EmailCredentials {
+		password += ", email"
+	}
+	if r.SavePassword && r.Password != "" {
+		password += ", save"
+	}
+	return []string{r.Username, r.Email, strings.Join(r.Groups, ", "), strings.Join(r.Policies, ", "), tag.Format(r.Tags), password}
+}
+
+// UserImportResult is the outcome of onboarding one user. Password is only returned when it
+// was generated.
+type UserImportResult struct {
+	Username          string           `json:"username" yaml:"username"`
+	Success           bool             `json:"success" yaml:"success"`
+	PasswordGenerated bool             `json:"password_generated" yaml:"password_generated"`
+	Password          string           `json:"password,omitempty" yaml:"-"`
+	Steps             []UserStepResult `json:"steps" yaml:"steps"`
+}
+
+// UserImportResultTableHeaders are the column headers matching UserImportResult.TableRow
+var UserImportResultTableHeaders = []string{"Username", "Result", "Steps", "Failed Steps"}
+
+// TableRow returns the result as a table row
+func (r UserImportResult) TableRow() []string {
+	status := "OK"
+	if !r.Success {
+		status = "FAILED"
+	}
+
+	failed := FailedSteps(r.Steps)
+	var failures []string
+	for _, step := range failed {
+		failures = append(failures, step.Action+" "+step.Resource+": "+step.Error)
+	}
+	return []string{r.Username, status, fmt.Sprintf("%d/%d", len(r.Steps)-len(failed), len(r.Steps)), strings.Join(failures, "; ")}
+}
+
+// LoadUserImportFile reads users to import. Files ending in .json are parsed as JSON, anything
+// else as CSV with a header row.
+func LoadUserImportFile(path string) ([]UserImportRow, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return ParseUserImportJSON(data)
+	}
+	return ParseUserImportCSV(bytes.NewReader(data))
+}
+
+// ParseUserImportJSON parses either an array of rows or an object with a "users" array
+func ParseUserImportJSON(data []byte) ([]UserImportRow, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var rows []UserImportRow
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = decoder.Decode(&rows)
+	} else {
+		var wrapped struct {
+			Users []UserImportRow `json:"users"`
+		}
+		err = decoder.Decode(&wrapped)
+		rows = wrapped.Users
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: %w", err)
+	}
+	return rows, nil
+}
+
+// ParseUserImportCSV parses CSV with a header row naming some of UserImportColumns
+func ParseUserImportCSV(r io.Reader) ([]UserImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, column := range UserImportColumns {
+		known[column] = true
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown column '%s' (expected %s)", name, strings.Join(UserImportColumns, ", "))
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["username"]; !ok {
+		return nil, errors.New("import file has no username column")
+	}
+
+	rows := []UserImportRow{}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid import file: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+
+		get := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row := UserImportRow{
+			Username: get("username"),
+			Email:    get("email"),
+			Groups:   splitValues(get("groups")),
+			Policies: splitValues(get("policies")),
+			Password: get("password"),
+		}
+		if row.Tags, err = tag.Parse(get("tags")); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		for column, field := range map[string]*bool{
+			"generate_password": &row.GeneratePassword,
+			"require_reset":     &row.RequireReset,
+			"email_credentials": &row.EmailCredentials,
+			"save_password":     &row.SavePassword,
+		} {
+			if *field, err = parseYesNo(get(column)); err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", line, column, err)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ValidateUserImport checks the rows before anything is created
+func ValidateUserImport(rows []UserImportRow) error {
+	if len(rows) == 0 {
+		return errors.New("at least one user is required")
+	}
+
+	seen := map[string]bool{}
+	for i, row := range rows {
+		if row.Username == "" {
+			return fmt.Errorf("row %d has no username", i+1)
+		}
+		if seen[row.Username] {
+			return fmt.Errorf("user '%s' is listed more than once", row.Username)
+		}
+		seen[row.Username] = true
+
+		if row.Password != "" && row.GeneratePassword {
+			return fmt.Errorf("user '%s': give a password or set generate_password, not both", row.Username)
+		}
+		if row.EmailCredentials && row.Email == "" {
+			return fmt.Errorf("user '%s': email_credentials needs an email address", row.Username)
+		}
+		if row.EmailCredentials && row.Password == "" && !row.GeneratePassword {
+			return fmt.Errorf("user '%s': email_credentials needs a password or generate_password", row.Username)
+		}
+		if row.SavePassword && row.Password == "" && !row.GeneratePassword {
+			return fmt.Errorf("user '%s': save_password needs a password or generate_password", row.Username)
+		}
+	}
+	return nil
+}
+
+// ImportUsers onboards each row: it creates the user with its tags, sets or generates a
+// password, adds the groups, attaches the policies and delivers generated credentials.
+// Rows are validated and policy names resolved before anything is created; after that a
+// failed step is recorded and the user's remaining steps still run, unless the user itself
+// or its password could not be created.
+func ImportUsers(rows []UserImportRow) ([]UserImportResult, error) {
+	if err := ValidateUserImport(rows); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+
+	var refs []string
+	for _, row := range rows {
+		refs = append(refs, row.Policies...)
+	}
+	policyArns, err := policy.ResolvePolicyArns(refs)
+	if errors.Is(err, policy.ErrPolicyNotFound) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Read the password policy once, as CreateMultipleIAMUsers does
+	var passwordPolicy *PasswordPolicy
+	for _, row := range rows {
+		if row.Password != "" || row.GeneratePassword {
+			passwordPolicy, _ = FetchPasswordPolicy()
+			break
+		}
+	}
+	if passwordPolicy != nil {
+		for _, row := range rows {
+			if row.Password == "" {
+				continue
+			}
+			if violations := passwordPolicy.CheckPassword(row.Password); len(violations) > 0 {
+				return nil, fmt.Errorf("%w: user '%s': password does not meet the account password policy: %s", ErrInvalidImport, row.Username, strings.Join(violations, "; "))
+			}
+		}
+	}
+
+	results := make([]UserImportResult, len(rows))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < importWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				results[index] = importUser(rows[index], policyArns, passwordPolicy)
+			}
+		}()
+	}
+	for i := range rows {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, nil
+}
+
+func importUser(row UserImportRow, policyArns map[string]string, passwordPolicy *PasswordPolicy) UserImportResult {
+	result := UserImportResult{Username: row.Username, Steps: []UserStepResult{}}
+	finish := func() UserImportResult {
+		result.Success = len(FailedSteps(result.Steps)) == 0
+		return result
+	}
+
+	err := createUserWithTags(row.Username, row.Tags)
+	result.Steps = append(result.Steps, stepResult("create user", row.Username, err))
+	if err != nil {
+		return finish()
+	}
+
+	password := row.Password
+	if row.GeneratePassword {
+		if password, err = generateBatchPassword(passwordPolicy); err != nil {
+			result.Steps = append(result.Steps, stepResult("generate password", row.Username, err))
+			return finish()
+		}
+		result.PasswordGenerated = true
+	}
+	if password != "" {
+		status, err := SetInitialUserPasswordModel(row.Username, password, row.RequireReset)
+		if status != PasswordCreatedSuccess && err == nil {
+			err = errors.New(getPasswordErrorMessage(status))
+		}
+		result.Steps = append(result.Steps, stepResult("set password", row.Username, err))
+		if err != nil {
+			return finish()
+		}
+	}
+
+	for _, name := range row.Groups {
+		result.Steps = append(result.Steps, stepResult("add to group", name, group.AddUserToGroup(row.Username, name)))
+	}
+	for _, ref := range row.Policies {
+		result.Steps = append(result.Steps, stepResult("attach policy", policyArns[ref], policy.AttachUserPolicy(row.Username, policyArns[ref])))
+	}
+
+	email := ""
+	if row.EmailCredentials {
+		email = row.Email
+	}
+	// Generated passwords are known nowhere else, so they are always saved; given ones only
+	// when the row asks for it
+	switch {
+	case result.PasswordGenerated:
+		result.Password = password
+		result.Steps = append(result.Steps, deliverPassword(row.Username, password, email)...)
+	case row.SavePassword:
+		result.Steps = append(result.Steps, deliverPassword(row.Username, password, email)...)
+	case email != "":
+		result.Steps = append(result.Steps, stepResult("email credentials", email, EmailUserCredentials(row.Username, password, email)))
+	}
+
+	return finish()
+}
+
+// createUserWithTags creates a user and its tags in one call. An existing user is an error,
+// so an import never changes users it did not create.
+func createUserWithTags(username string, tags map[string]string) error {
+	input := &iam.CreateUserInput{UserName: aws.String(username)}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		input.Tags = append(input.Tags, types.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+
+	_, err := utils.GetIAMClient().CreateUser(context.TODO(), input)
+	if err != nil && strings.Contains(err.Error(), "EntityAlreadyExists") {
+		return errors.New("user already exists")
+	}
+	return err
+}
+
+// splitValues splits a multi-value CSV cell on ';' into its non-empty trimmed values
+func splitValues(value string) []string {
+	var values []string
+	for _, item := range strings.Split(value, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func parseYesNo(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "", "false", "no", "n", "0":
+		return false, nil
+	case "true", "yes", "y", "1":
+		return true, nil
+	}
+	return false, fmt.Errorf("expected yes or no, got '%s'", value)
+}
@@ -0,0 +1,169 @@
+package user
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUserImportCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []UserImportRow
+		wantErr string
+	}{
+		{
+			name: "all columns",
+			csv: "username,email,groups,policies,tags,password,generate_password,require_reset,email_credentials,save_password\n" +
+				"alice,alice@example.com,developers;ops,ReadOnlyAccess,team=web;cost=low,,yes,true,y,no\n",
+			want: []UserImportRow{{
+				Username:         "alice",
+				Email:            "alice@example.com",
+				Groups:           []string{"developers", "ops"},
+				Policies:         []string{"ReadOnlyAccess"},
+				Tags:             map[string]string{"team": "web", "cost": "low"},
+				GeneratePassword: true,
+				RequireReset:     true,
+				EmailCredentials: true,
+			}},
+		},
+		{
+			name: "quoted tag value keeps its commas",
+			csv:  "username,tags\nalice,\"cost=a,b; team = web\"\n",
+			want: []UserImportRow{{Username: "alice", Tags: map[string]string{"cost": "a,b", "team": "web"}}},
+		},
+		{
+			name: "columns in any order and case, values trimmed",
+			csv:  "Groups, USERNAME ,password,Save_Password\n ops ; dev ;, bob , Secret-Pass-1 ,yes\n",
+			want: []UserImportRow{{Username: "bob", Groups: []string{"ops", "dev"}, Password: "Secret-Pass-1", SavePassword: true}},
+		},
+		{
+			name: "empty cells are left unset",
+			csv:  "username,email,groups,generate_password\ncarol,,,\n",
+			want: []UserImportRow{{Username: "carol"}},
+		},
+		{
+			name: "header only",
+			csv:  "username\n",
+			want: []UserImportRow{},
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: "import file is empty",
+		},
+		{
+			name:    "unknown column",
+			csv:     "username,mail\nalice,alice@example.com\n",
+			wantErr: "unknown column 'mail'",
+		},
+		{
+			name:    "no username column",
+			csv:     "email\nalice@example.com\n",
+			wantErr: "import file has no username column",
+		},
+		{
+			name:    "bad yes or no value names the line",
+			csv:     "username,require_reset\nalice,yes\nbob,maybe\n",
+			wantErr: "line 3: require_reset: expected yes or no, got 'maybe'",
+		},
+		{
+			name:    "bad tag names the line",
+			csv:     "username,tags\nalice,team\n",
+			wantErr: "line 2: invalid tag 'team', expected key=value",
+		},
+		{
+			name:    "wrong number of fields",
+			csv:     "username,email\nalice\n",
+			wantErr: "invalid import file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUserImportCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseUserImportCSV() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseUserImportCSV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseUserImportCSV() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUserImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    []UserImportRow
+		wantErr string
+	}{
+		{
+			name: "valid",
+			rows: []UserImportRow{
+				{Username: "alice"},
+				{Username: "bob", Password: "Secret-Pass-1"},
+				{Username: "carol", Email: "carol@example.com", GeneratePassword: true, EmailCredentials: true},
+				{Username: "dave", Email: "dave@example.com", Password: "Secret-Pass-2", EmailCredentials: true},
+				{Username: "erin", Password: "Secret-Pass-3", SavePassword: true},
+				{Username: "frank", GeneratePassword: true, SavePassword: true},
+			},
+		},
+		{
+			name:    "no rows",
+			wantErr: "at least one user is required",
+		},
+		{
+			name:    "missing username",
+			rows:    []UserImportRow{{Username: "alice"}, {}},
+			wantErr: "row 2 has no username",
+		},
+		{
+			name:    "duplicate username",
+			rows:    []UserImportRow{{Username: "alice"}, {Username: "alice"}},
+			wantErr: "user 'alice' is listed more than once",
+		},
+		{
+			name:    "password and generate_password",
+			rows:    []UserImportRow{{Username: "alice", Password: "Secret-Pass-1", GeneratePassword: true}},
+			wantErr: "user 'alice': give a password or set generate_password, not both",
+		},
+		{
+			name:    "email_credentials without an email",
+			rows:    []UserImportRow{{Username: "alice", GeneratePassword: true, EmailCredentials: true}},
+			wantErr: "user 'alice': email_credentials needs an email address",
+		},
+		{
+			name:    "email_credentials without a password",
+			rows:    []UserImportRow{{Username: "alice", Email: "alice@example.com", EmailCredentials: true}},
+			wantErr: "user 'alice': email_credentials needs a password or generate_password",
+		},
+		{
+			name:    "save_password without a password",
+			rows:    []UserImportRow{{Username: "alice", SavePassword: true}},
+			wantErr: "user 'alice': save_password needs a password or generate_password",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUserImport(tt.rows)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateUserImport() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("ValidateUserImport() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,38 +2,149 @@ package user
 
 import (
 	"context"
-	"fmt"
-	"strings"
+	"sort"
+	"sync"
 
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/policy"
+	"github.com/DragonEmperor9480/aws_cli_manager/models/iam/tag"
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 )
 
-func FetchIAMUsers() (string, error) {
-	ctx := context.TODO()
-	result, err := utils.IAMClient.ListUsers(ctx, &iam.ListUsersInput{})
+// IAMUser is a single row of the IAM user listing
+type IAMUser struct {
+	UserName   string            `json:"username" yaml:"username"`
+	UserID     string            `json:"user_id" yaml:"user_id"`
+	Arn        string            `json:"arn" yaml:"arn"`
+	CreateDate string            `json:"create_date" yaml:"create_date"`
+	Tags       map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"` // Only filled by listings that fetch tags
+}
+
+// IAMUserTableHeaders are the column headers matching IAMUser.TableRow
+var IAMUserTableHeaders = []string{"Username", "User ID", "Created At", "Tags"}
+
+// TableRow returns the user as a table row
+func (u IAMUser) TableRow() []string {
+	return []string{u.UserName, u.UserID, u.CreateDate, tag.Format(u.Tags)}
+}
+
+// TagMap returns the tags of the user, for tag.FilterByTag
+func (u IAMUser) TagMap() map[string]string {
+	return u.Tags
+}
+
+// AccountIAMUser is an IAMUser tagged with the account it was listed from
+type AccountIAMUser struct {
+	AccountID string `json:"account_id" yaml:"account_id"`
+	Account   string `json:"account" yaml:"account"`
+	IAMUser   `yaml:",inline"`
+}
+
+// AccountIAMUserTableHeaders are the column headers matching AccountIAMUser.TableRow
+var AccountIAMUserTableHeaders = append([]string{"Account ID", "Account"}, IAMUserTableHeaders...)
+
+// TableRow returns the user as a table row prefixed with its account
+func (u AccountIAMUser) TableRow() []string {
+	return append([]string{u.AccountID, u.Account}, u.IAMUser.TableRow()...)
+}
+
+// FetchIAMUsers lists all IAM users in the account, without their tags
+func FetchIAMUsers() ([]IAMUser, error) {
+	return fetchIAMUsers(utils.GetIAMClient())
+}
+
+// FetchIAMUsersWithTags lists the IAM users in the account together with their tags, which
+// takes one extra call per user, keeping only those matching filter when it is set. Users
+// whose tags could not be read are listed without them and reported in the warnings, unless
+// the filter needs their tags.
+func FetchIAMUsersWithTags(filter *tag.Filter) ([]IAMUser, []string, error) {
+	client := utils.GetIAMClient()
+	users, err := fetchIAMUsers(client)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
+	users, warnings, err := addUserTags(client, users, filter != nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if filter != nil {
+		users = tag.FilterByTag(users, *filter)
+	}
+	return users, warnings, nil
+}
 
-	// Format output to match the old text format: UserName UserId CreateDate
-	var output strings.Builder
-	for _, user := range result.Users {
-		line := fmt.Sprintf("%s\t%s\t%s\n",
-			aws.ToString(user.UserName),
-			aws.ToString(user.UserId),
-			user.CreateDate.Format("2006-01-02T15:04:05Z"),
-		)
-		output.WriteString(line)
+// FetchIAMUsersAcrossAccounts lists the IAM users of several accounts concurrently, with
+// their tags and filtered like FetchIAMUsersWithTags. Users are returned in account order,
+// together with tag warnings and the accounts that failed.
+func FetchIAMUsersAcrossAccounts(accounts []string, filter *tag.Filter) ([]AccountIAMUser, []string, []utils.AccountError) {
+	var mu sync.Mutex
+	warnings := []string{}
+
+	users, failures := utils.CollectAcrossAccounts(accounts, func(clients *utils.AccountClients) ([]AccountIAMUser, error) {
+		users, err := fetchIAMUsers(clients.IAM)
+		if err != nil {
+			return nil, err
+		}
+		users, tagWarnings, err := addUserTags(clients.IAM, users, filter != nil)
+		if err != nil {
+			return nil, err
+		}
+		if filter != nil {
+			users = tag.FilterByTag(users, *filter)
+		}
+
+		mu.Lock()
+		for _, warning := range tagWarnings {
+			warnings = append(warnings, "account '"+clients.Account+"': "+warning)
+		}
+		mu.Unlock()
+
+		tagged := make([]AccountIAMUser, 0, len(users))
+		for _, user := range users {
+			tagged = append(tagged, AccountIAMUser{AccountID: clients.AccountID, Account: clients.Account, IAMUser: user})
+		}
+		return tagged, nil
+	})
+
+	sort.Strings(warnings)
+	return users, warnings, failures
+}
+
+func fetchIAMUsers(client *iam.Client) ([]IAMUser, error) {
+	ctx := context.TODO()
+	users := []IAMUser{}
+
+	paginator := iam.NewListUsersPaginator(client, &iam.ListUsersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, user := range page.Users {
+			users = append(users, IAMUser{
+				UserName:   aws.ToString(user.UserName),
+				UserID:     aws.ToString(user.UserId),
+				Arn:        aws.ToString(user.Arn),
+				CreateDate: user.CreateDate.Format("2006-01-02T15:04:05Z"),
+			})
+		}
 	}
 
-	return output.String(), nil
+	return users, nil
+}
+
+func addUserTags(client *iam.Client, users []IAMUser, required bool) ([]IAMUser, []string, error) {
+	return tag.Fill(client, policy.IdentityUser, users,
+		func(u IAMUser) string { return u.UserName },
+		func(u *IAMUser, tags map[string]string) { u.Tags = tags },
+		required)
 }
 
 func FetchOnlyUsernames() []string {
 	ctx := context.TODO()
-	result, err := utils.IAMClient.ListUsers(ctx, &iam.ListUsersInput{})
+	result, err := utils.GetIAMClient().ListUsers(ctx, &iam.ListUsersInput{})
 	if err != nil {
 		return []string{}
 	}
@@ -0,0 +1,173 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+)
+
+// mfaCodePattern matches a six digit TOTP code
+var mfaCodePattern = regexp.MustCompile(`^[0-9]{6}$`)
+
+// MFADevice is an MFA device assigned to a user
+type MFADevice struct {
+	UserName     string    `json:"username" yaml:"username"`
+	SerialNumber string    `json:"serial_number" yaml:"serial_number"`
+	EnableDate   time.Time `json:"enable_date" yaml:"enable_date"`
+	Virtual      bool      `json:"virtual" yaml:"virtual"`
+}
+
+// MFADeviceTableHeaders are the column headers matching MFADevice.TableRow
+var MFADeviceTableHeaders = []string{"Serial Number", "Type", "Enabled"}
+
+// TableRow returns the device as a table row
+func (d MFADevice) TableRow() []string {
+	deviceType := "Hardware"
+	if d.Virtual {
+		deviceType = "Virtual"
+	}
+	return []string{d.SerialNumber, deviceType, d.EnableDate.Local().Format("2006-01-02 15:04")}
+}
+
+// Name returns the device name, the last part of a virtual device ARN
+func (d MFADevice) Name() string {
+	if i := strings.LastIndex(d.SerialNumber, "/"); i >= 0 {
+		return d.SerialNumber[i+1:]
+	}
+	return d.SerialNumber
+}
+
+// VirtualMFAEnrollment is a newly created virtual MFA device. The seed and QR code
+// are only returned at creation, so they must be shown to the user right away.
+type VirtualMFAEnrollment struct {
+	UserName     string `json:"username"`
+	SerialNumber string `json:"serial_number"`
+	Base32Seed   string `json:"base32_seed"`
+	QRCodePNG    []byte `json:"qr_code_png"`
+	OTPAuthURI   string `json:"otpauth_uri"`
+}
+
+// FetchMFADevices lists the MFA devices of a user, or of the calling identity when username is empty
+func FetchMFADevices(username string) ([]MFADevice, error) {
+	input := &iam.ListMFADevicesInput{}
+	if username != "" {
+		input.UserName = aws.String(username)
+	}
+
+	devices := []MFADevice{}
+	paginator := iam.NewListMFADevicesPaginator(utils.GetIAMClient(), input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range page.MFADevices {
+			serial := aws.ToString(d.SerialNumber)
+			devices = append(devices, MFADevice{
+				UserName:     aws.ToString(d.UserName),
+				SerialNumber: serial,
+				EnableDate:   aws.ToTime(d.EnableDate),
+				Virtual:      isVirtualMFADevice(serial),
+			})
+		}
+	}
+
+	return devices, nil
+}
+
+// CreateVirtualMFADevice creates a virtual MFA device for a user. The device is not
+// usable until it is enabled with EnableMFADevice. deviceName defaults to the username.
+func CreateVirtualMFADevice(username, deviceName string) (*VirtualMFAEnrollment, error) {
+	if deviceName == "" {
+		deviceName = username
+	}
+
+	result, err := utils.GetIAMClient().CreateVirtualMFADevice(context.TODO(), &iam.CreateVirtualMFADeviceInput{
+		VirtualMFADeviceName: aws.String(deviceName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	device := result.VirtualMFADevice
+	enrollment := &VirtualMFAEnrollment{
+		UserName:     username,
+		SerialNumber: aws.ToString(device.SerialNumber),
+		Base32Seed:   string(device.Base32StringSeed),
+		QRCodePNG:    device.QRCodePNG,
+	}
+
+	// The label matches what the AWS console uses, so authenticator apps show the same entry
+	label := username
+	if accountID, err := utils.GetAWSAccountID(); err == nil {
+		label = username + "@" + accountID
+	}
+	enrollment.OTPAuthURI = "otpauth://totp/" + url.PathEscape("Amazon Web Services:"+label) +
+		"?secret=" + enrollment.Base32Seed + "&issuer=" + url.QueryEscape("Amazon Web Services")
+
+	return enrollment, nil
+}
+
+// EnableMFADevice assigns an MFA device to a user using two consecutive codes from the device
+func EnableMFADevice(username, serialNumber, code1, code2 string) error {
+	if !mfaCodePattern.MatchString(code1) || !mfaCodePattern.MatchString(code2) {
+		return fmt.Errorf("MFA codes must be six digits")
+	}
+	if code1 == code2 {
+		return fmt.Errorf("the two MFA codes must be consecutive, not the same code twice")
+	}
+
+	_, err := utils.GetIAMClient().EnableMFADevice(context.TODO(), &iam.EnableMFADeviceInput{
+		UserName:            aws.String(username),
+		SerialNumber:        aws.String(serialNumber),
+		AuthenticationCode1: aws.String(code1),
+		AuthenticationCode2: aws.String(code2),
+	})
+	return err
+}
+
+// DeactivateMFADevice removes an MFA device from a user. A virtual device still exists afterwards.
+func DeactivateMFADevice(username, serialNumber string) error {
+	_, err := utils.GetIAMClient().DeactivateMFADevice(context.TODO(), &iam.DeactivateMFADeviceInput{
+		UserName:     aws.String(username),
+		SerialNumber: aws.String(serialNumber),
+	})
+	return err
+}
+
+// DeleteMFADevice deactivates a user's virtual MFA device and deletes it.
+// Hardware devices can only be deactivated.
+func DeleteMFADevice(username, serialNumber string) []UserStepResult {
+	if !isVirtualMFADevice(serialNumber) {
+		err := fmt.Errorf("%s is not a virtual MFA device; deactivate it instead", serialNumber)
+		return []UserStepResult{stepResult("delete MFA device", serialNumber, err)}
+	}
+
+	steps := []UserStepResult{}
+	if username != "" {
+		err := DeactivateMFADevice(username, serialNumber)
+		steps = append(steps, stepResult("deactivate MFA device", serialNumber, err))
+		if err != nil {
+			return steps
+		}
+	}
+
+	err := DeleteVirtualMFADevice(serialNumber)
+	return append(steps, stepResult("delete MFA device", serialNumber, err))
+}
+
+// DeleteVirtualMFADevice deletes a virtual MFA device that is not assigned to any user,
+// such as one whose enrollment was abandoned
+func DeleteVirtualMFADevice(serialNumber string) error {
+	_, err := utils.GetIAMClient().DeleteVirtualMFADevice(context.TODO(), &iam.DeleteVirtualMFADeviceInput{
+		SerialNumber: aws.String(serialNumber),
+	})
+	return err
+}
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/service"
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+)
+
+// generatedPasswordLength is the length of generated passwords unless the policy asks for more
+const generatedPasswordLength = 20
+
+// Character classes used by GeneratePassword
+const (
+	passwordUppercase = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordLowercase = "abcdefghijkmnopqrstuvwxyz"
+	passwordDigits    = "23456789"
+)
+
+// GeneratePassword returns a random password that satisfies the policy. It always contains
+// every character class, so it also meets rules added to the policy later. Easily confused
+// characters such as 0/O and 1/l are left out because the password is often typed by hand.
+func GeneratePassword(policy PasswordPolicy) (string, error) {
+	length := generatedPasswordLength
+	if policy.MinimumPasswordLength > length {
+		length = policy.MinimumPasswordLength
+	}
+
+	classes := []string{passwordUppercase, passwordLowercase, passwordDigits, PasswordSymbols}
+	alphabet := passwordUppercase + passwordLowercase + passwordDigits + PasswordSymbols
+
+	password := make([]byte, 0, length)
+	for _, class := range classes {
+		char, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char)
+	}
+	for len(password) < length {
+		char, err := randomChar(alphabet)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, char)
+	}
+
+	// Shuffle so the guaranteed characters are not always at the start
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomIndex(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+
+	if violations := policy.CheckPassword(string(password)); len(violations) > 0 {
+		return "", fmt.Errorf("generated password does not meet the policy: %s", violations[0])
+	}
+	return string(password), nil
+}
+
+// GenerateCompliantPassword generates a password for the account password policy.
+// If the policy cannot be read, the AWS default policy is used.
+func GenerateCompliantPassword() (string, error) {
+	policy, err := FetchPasswordPolicy()
+	if err != nil {
+		defaultPolicy := DefaultPasswordPolicy()
+		policy = &defaultPolicy
+	}
+	return GeneratePassword(*policy)
+}
+
+// EmailUserCredentials sends a user's console credentials using the saved email configuration
+func EmailUserCredentials(username, password, email string) error {
+	emailConfig, err := service.LoadEmailConfig()
+	if err != nil {
+		return err
+	}
+
+	consoleURL, err := utils.GetConsoleSignInURL()
+	if err != nil {
+		consoleURL = "https://console.aws.amazon.com/"
+	}
+
+	return service.SendIAMCredentialsEmail(emailConfig, username, password, email, consoleURL)
+}
+
+func randomChar(charset string) (byte, error) {
+	i, err := randomIndex(len(charset))
+	if err != nil {
+		return 0, err
+	}
+	return charset[i], nil
+}
+
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
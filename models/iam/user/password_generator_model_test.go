@@ -0,0 +1,63 @@
+package user
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassword(t *testing.T) {
+	allClasses := PasswordPolicy{
+		RequireUppercaseCharacters: true,
+		RequireLowercaseCharacters: true,
+		RequireNumbers:             true,
+		RequireSymbols:             true,
+	}
+	long := allClasses
+	long.MinimumPasswordLength = 64
+
+	tests := []struct {
+		name       string
+		policy     PasswordPolicy
+		wantLength int
+	}{
+		{"empty policy", PasswordPolicy{}, generatedPasswordLength},
+		{"default policy", DefaultPasswordPolicy(), generatedPasswordLength},
+		{"all classes required", allClasses, generatedPasswordLength},
+		{"minimum below the generated length", PasswordPolicy{MinimumPasswordLength: 8}, generatedPasswordLength},
+		{"minimum above the generated length", long, 64},
+		{"longest allowed minimum", PasswordPolicy{MinimumPasswordLength: maxPolicyPasswordLength}, maxPolicyPasswordLength},
+	}
+
+	classes := map[string]string{
+		"uppercase": passwordUppercase,
+		"lowercase": passwordLowercase,
+		"digit":     passwordDigits,
+		"symbol":    PasswordSymbols,
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Generation is random, so each policy is tried several times
+			for i := 0; i < 50; i++ {
+				password, err := GeneratePassword(tt.policy)
+				if err != nil {
+					t.Fatalf("GeneratePassword() error = %v", err)
+				}
+				if len(password) != tt.wantLength {
+					t.Fatalf("GeneratePassword() length = %d, want %d", len(password), tt.wantLength)
+				}
+				for name, class := range classes {
+					if !strings.ContainsAny(password, class) {
+						t.Fatalf("GeneratePassword() = %q has no %s character", password, name)
+					}
+				}
+				if strings.ContainsAny(password, "0O1lI") {
+					t.Fatalf("GeneratePassword() = %q contains an easily confused character", password)
+				}
+				if violations := tt.policy.CheckPassword(password); len(violations) > 0 {
+					t.Fatalf("GeneratePassword() = %q breaks the policy: %v", password, violations)
+				}
+			}
+		})
+	}
+}
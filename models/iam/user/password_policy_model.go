@@ -0,0 +1,215 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// PasswordSymbols are the non-alphanumeric characters IAM counts as symbols
+const PasswordSymbols = "!@#$%^&*()_+-=[]{}|'"
+
+// Limits IAM enforces on the password policy settings
+const (
+	minPolicyPasswordLength  = 6
+	maxPolicyPasswordLength  = 128
+	maxPolicyPasswordAge     = 1095
+	maxPolicyReusePrevention = 24
+	defaultPasswordLength    = 8
+)
+
+// PasswordPolicy is the account password policy. Default is set when the account has no
+// custom policy, in which case AWS requires eight characters of at least three character types.
+type PasswordPolicy struct {
+	Default                    bool `json:"default" yaml:"default"`
+	MinimumPasswordLength      int  `json:"minimum_password_length" yaml:"minimum_password_length"`
+	RequireUppercaseCharacters bool `json:"require_uppercase_characters" yaml:"require_uppercase_characters"`
+	RequireLowercaseCharacters bool `json:"require_lowercase_characters" yaml:"require_lowercase_characters"`
+	RequireNumbers             bool `json:"require_numbers" yaml:"require_numbers"`
+	RequireSymbols             bool `json:"require_symbols" yaml:"require_symbols"`
+	AllowUsersToChangePassword bool `json:"allow_users_to_change_password" yaml:"allow_users_to_change_password"`
+	MaxPasswordAge             int  `json:"max_password_age" yaml:"max_password_age"`                   // days, 0 never expires
+	PasswordReusePrevention    int  `json:"password_reuse_prevention" yaml:"password_reuse_prevention"` // 0 allows reuse
+	HardExpiry                 bool `json:"hard_expiry" yaml:"hard_expiry"`
+}
+
+// DefaultPasswordPolicy returns the policy AWS applies when no custom policy is set
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{
+		Default:                    true,
+		MinimumPasswordLength:      defaultPasswordLength,
+		AllowUsersToChangePassword: true,
+	}
+}
+
+// FetchPasswordPolicy returns the account password policy, or the AWS default when none is set
+func FetchPasswordPolicy() (*PasswordPolicy, error) {
+	result, err := utils.GetIAMClient().GetAccountPasswordPolicy(context.TODO(), &iam.GetAccountPasswordPolicyInput{})
+	if err != nil {
+		var notFound *types.NoSuchEntityException
+		if errors.As(err, &notFound) {
+			policy := DefaultPasswordPolicy()
+			return &policy, nil
+		}
+		return nil, err
+	}
+
+	p := result.PasswordPolicy
+	return &PasswordPolicy{
+		MinimumPasswordLength:      int(aws.ToInt32(p.MinimumPasswordLength)),
+		RequireUppercaseCharacters: p.RequireUppercaseCharacters,
+		RequireLowercaseCharacters: p.RequireLowercaseCharacters,
+		RequireNumbers:             p.RequireNumbers,
+		RequireSymbols:             p.RequireSymbols,
+		AllowUsersToChangePassword: p.AllowUsersToChangePassword,
+		MaxPasswordAge:             int(aws.ToInt32(p.MaxPasswordAge)),
+		PasswordReusePrevention:    int(aws.ToInt32(p.PasswordReusePrevention)),
+		HardExpiry:                 aws.ToBool(p.HardExpiry),
+	}, nil
+}
+
+// UpdatePasswordPolicy replaces the account password policy after checking IAM's limits locally
+func UpdatePasswordPolicy(policy PasswordPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	input := &iam.UpdateAccountPasswordPolicyInput{
+		MinimumPasswordLength:      aws.Int32(int32(policy.MinimumPasswordLength)),
+		RequireUppercaseCharacters: policy.RequireUppercaseCharacters,
+		RequireLowercaseCharacters: policy.RequireLowercaseCharacters,
+		RequireNumbers:             policy.RequireNumbers,
+		RequireSymbols:             policy.RequireSymbols,
+		AllowUsersToChangePassword: policy.AllowUsersToChangePassword,
+		HardExpiry:                 aws.Bool(policy.HardExpiry),
+	}
+	if policy.MaxPasswordAge > 0 {
+		input.MaxPasswordAge = aws.Int32(int32(policy.MaxPasswordAge))
+	}
+	if policy.PasswordReusePrevention > 0 {
+		input.PasswordReusePrevention = aws.Int32(int32(policy.PasswordReusePrevention))
+	}
+
+	_, err := utils.GetIAMClient().UpdateAccountPasswordPolicy(context.TODO(), input)
+	return err
+}
+
+// ResetPasswordPolicy deletes the custom password policy so the AWS default applies again
+func ResetPasswordPolicy() error {
+	_, err := utils.GetIAMClient().DeleteAccountPasswordPolicy(context.TODO(), &iam.DeleteAccountPasswordPolicyInput{})
+	var notFound *types.NoSuchEntityException
+	if errors.As(err, &notFound) {
+		return nil
+	}
+	return err
+}
+
+// Validate checks the policy settings against the limits IAM accepts
+func (p PasswordPolicy) Validate() error {
+	if p.MinimumPasswordLength < minPolicyPasswordLength || p.MinimumPasswordLength > maxPolicyPasswordLength {
+		return fmt.Errorf("minimum password length must be between %d and %d", minPolicyPasswordLength, maxPolicyPasswordLength)
+	}
+	if p.MaxPasswordAge < 0 || p.MaxPasswordAge > maxPolicyPasswordAge {
+		return fmt.Errorf("maximum password age must be between 0 (never expires) and %d days", maxPolicyPasswordAge)
+	}
+	if p.PasswordReusePrevention < 0 || p.PasswordReusePrevention > maxPolicyReusePrevention {
+		return fmt.Errorf("password reuse prevention must be between 0 (off) and %d", maxPolicyReusePrevention)
+	}
+	return nil
+}
+
+// Requirements describes the rules a password must meet under the policy
+func (p PasswordPolicy) Requirements() []string {
+	requirements := []string{fmt.Sprintf("At least %d characters long", p.MinimumPasswordLength)}
+	if p.Default {
+		return append(requirements, "At least three of: uppercase letters, lowercase letters, numbers, symbols ("+PasswordSymbols+")")
+	}
+
+	if p.RequireUppercaseCharacters {
+		requirements = append(requirements, "At least one uppercase letter")
+	}
+	if p.RequireLowercaseCharacters {
+		requirements = append(requirements, "At least one lowercase letter")
+	}
+	if p.RequireNumbers {
+		requirements = append(requirements, "At least one number")
+	}
+	if p.RequireSymbols {
+		requirements = append(requirements, "At least one symbol ("+PasswordSymbols+")")
+	}
+	if p.PasswordReusePrevention > 0 {
+		requirements = append(requirements, fmt.Sprintf("Not one of the user's last %d passwords", p.PasswordReusePrevention))
+	}
+	return requirements
+}
+
+// CheckPassword returns the policy rules the password breaks; an empty result means it passes.
+// Password reuse cannot be checked locally and is left to AWS.
+func (p PasswordPolicy) CheckPassword(password string) []string {
+	violations := []string{}
+
+	length := len([]rune(password))
+	if length < p.MinimumPasswordLength {
+		violations = append(violations, fmt.Sprintf("Must be at least %d characters long (has %d)", p.MinimumPasswordLength, length))
+	}
+	if length > maxPolicyPasswordLength {
+		violations = append(violations, fmt.Sprintf("Must be at most %d characters long", maxPolicyPasswordLength))
+	}
+
+	// IAM only counts Latin letters and ASCII digits, so "É" or "ß" meet no requirement
+	var hasUpper, hasLower, hasNumber, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case 'A' <= char && char <= 'Z':
+			hasUpper = true
+		case 'a' <= char && char <= 'z':
+			hasLower = true
+		case '0' <= char && char <= '9':
+			hasNumber = true
+		case strings.ContainsRune(PasswordSymbols, char):
+			hasSymbol = true
+		}
+	}
+
+	if p.Default {
+		kinds := 0
+		for _, has := range []bool{hasUpper, hasLower, hasNumber, hasSymbol} {
+			if has {
+				kinds++
+			}
+		}
+		if kinds < 3 {
+			violations = append(violations, "Must contain at least three of: uppercase letters, lowercase letters, numbers, symbols")
+		}
+		return violations
+	}
+
+	if p.RequireUppercaseCharacters && !hasUpper {
+		violations = append(violations, "Must contain at least one uppercase letter")
+	}
+	if p.RequireLowercaseCharacters && !hasLower {
+		violations = append(violations, "Must contain at least one lowercase letter")
+	}
+	if p.RequireNumbers && !hasNumber {
+		violations = append(violations, "Must contain at least one number")
+	}
+	if p.RequireSymbols && !hasSymbol {
+		violations = append(violations, "Must contain at least one symbol ("+PasswordSymbols+")")
+	}
+	return violations
+}
+
+// CheckPasswordAgainstPolicy fetches the account password policy and checks the password against it
+func CheckPasswordAgainstPolicy(password string) (*PasswordPolicy, []string, error) {
+	policy, err := FetchPasswordPolicy()
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, policy.CheckPassword(password), nil
+}
@@ -0,0 +1,129 @@
+package user
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckPassword(t *testing.T) {
+	strict := PasswordPolicy{
+		MinimumPasswordLength:      12,
+		RequireUppercaseCharacters: true,
+		RequireLowercaseCharacters: true,
+		RequireNumbers:             true,
+		RequireSymbols:             true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   PasswordPolicy
+		password string
+		want     []string
+	}{
+		{
+			name:     "strict policy met",
+			policy:   strict,
+			password: "Correct-Horse-42",
+			want:     []string{},
+		},
+		{
+			name:     "too short",
+			policy:   strict,
+			password: "Short-42",
+			want:     []string{"Must be at least 12 characters long (has 8)"},
+		},
+		{
+			name:     "length counts characters, not bytes",
+			policy:   PasswordPolicy{MinimumPasswordLength: 4},
+			password: "äöü",
+			want:     []string{"Must be at least 4 characters long (has 3)"},
+		},
+		{
+			name:     "too long",
+			policy:   PasswordPolicy{},
+			password: strings.Repeat("a", maxPolicyPasswordLength+1),
+			want:     []string{"Must be at most 128 characters long"},
+		},
+		{
+			name:     "every class missing",
+			policy:   strict,
+			password: "............",
+			want: []string{
+				"Must contain at least one uppercase letter",
+				"Must contain at least one lowercase letter",
+				"Must contain at least one number",
+				"Must contain at least one symbol (" + PasswordSymbols + ")",
+			},
+		},
+		{
+			name:     "characters outside the IAM symbol set are not symbols",
+			policy:   PasswordPolicy{RequireSymbols: true},
+			password: "abc~`<>",
+			want:     []string{"Must contain at least one symbol (" + PasswordSymbols + ")"},
+		},
+		{
+			name:     "non-Latin letters are neither uppercase nor lowercase",
+			policy:   PasswordPolicy{RequireUppercaseCharacters: true, RequireLowercaseCharacters: true},
+			password: "ÉÀÑßéøü",
+			want: []string{
+				"Must contain at least one uppercase letter",
+				"Must contain at least one lowercase letter",
+			},
+		},
+		{
+			name:     "non-ASCII digits are not numbers",
+			policy:   PasswordPolicy{RequireNumbers: true},
+			password: "abc٣४",
+			want:     []string{"Must contain at least one number"},
+		},
+		{
+			name:     "Latin letters count next to non-Latin ones",
+			policy:   PasswordPolicy{RequireUppercaseCharacters: true, RequireLowercaseCharacters: true},
+			password: "ÉaßZ",
+			want:     []string{},
+		},
+		{
+			name:     "default policy does not count non-Latin letters as a type",
+			policy:   DefaultPasswordPolicy(),
+			password: "abcdefgÉ1",
+			want:     []string{"Must contain at least three of: uppercase letters, lowercase letters, numbers, symbols"},
+		},
+		{
+			name:     "unrequired classes are ignored",
+			policy:   PasswordPolicy{MinimumPasswordLength: 6, RequireNumbers: true},
+			password: "abcdef1",
+			want:     []string{},
+		},
+		{
+			name:     "default policy accepts three character types",
+			policy:   DefaultPasswordPolicy(),
+			password: "abcdEFG1",
+			want:     []string{},
+		},
+		{
+			name:     "default policy rejects two character types",
+			policy:   DefaultPasswordPolicy(),
+			password: "abcdefg1",
+			want:     []string{"Must contain at least three of: uppercase letters, lowercase letters, numbers, symbols"},
+		},
+		{
+			name:     "default policy reports length and types together",
+			policy:   DefaultPasswordPolicy(),
+			password: "abc",
+			want: []string{
+				"Must be at least 8 characters long (has 3)",
+				"Must contain at least three of: uppercase letters, lowercase letters, numbers, symbols",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.policy.CheckPassword(tt.password)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckPassword(%q) = %q, want %q", tt.password, got, tt.want)
+			}
+		})
+	}
+}
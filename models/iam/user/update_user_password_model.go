@@ -17,7 +17,7 @@ func UpdateUserPasswordModel(username, password string) {
 	reader := bufio.NewReader(os.Stdin)
 
 	ctx := context.TODO()
-	_, err := utils.IAMClient.UpdateLoginProfile(ctx, &iam.UpdateLoginProfileInput{
+	_, err := utils.GetIAMClient().UpdateLoginProfile(ctx, &iam.UpdateLoginProfileInput{
 		UserName: aws.String(username),
 		Password: aws.String(password),
 	})
@@ -27,12 +27,12 @@ func UpdateUserPasswordModel(username, password string) {
 			fmt.Println(utils.Red + utils.Bold + "Error: The user '" + username + "' does not exist!" + utils.Reset)
 		} else if strings.Contains(err.Error(), "PasswordPolicyViolation") {
 			fmt.Println(utils.Red + utils.Bold + "Error: Password does not meet AWS policy requirements!" + utils.Reset)
-			fmt.Println(utils.Yellow + "Password should include at least:\n" +
-				"- One uppercase letter\n" +
-				"- One lowercase letter\n" +
-				"- One symbol (e.g., !@#$%^&*)\n" +
-				"- One number (if required by your policy)\n" +
-				"- Minimum length as per your account policy" + utils.Reset)
+			if policy, err := FetchPasswordPolicy(); err == nil {
+				fmt.Println(utils.Yellow + "Password requirements:" + utils.Reset)
+				for _, requirement := range policy.Requirements() {
+					fmt.Println(utils.Yellow + "- " + requirement + utils.Reset)
+				}
+			}
 		} else {
 			fmt.Println(utils.Red + utils.Bold + "Error occurred while updating password:" + utils.Reset)
 			fmt.Println(err.Error())
@@ -23,7 +23,7 @@ const (
 func SetInitialUserPasswordModel(username, password string, requireReset bool) (int, error) {
 	ctx := context.TODO()
 
-	_, err := utils.IAMClient.CreateLoginProfile(ctx, &iam.CreateLoginProfileInput{
+	_, err := utils.GetIAMClient().CreateLoginProfile(ctx, &iam.CreateLoginProfileInput{
 		UserName:              aws.String(username),
 		Password:              aws.String(password),
 		PasswordResetRequired: requireReset,
@@ -2,42 +2,90 @@ package s3
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-func ListS3BucketsModel() string {
+// S3Bucket is a single row of the bucket listing
+type S3Bucket struct {
+	Name         string `json:"name" yaml:"name"`
+	CreationDate string `json:"creation_date" yaml:"creation_date"`
+}
+
+// S3BucketTableHeaders are the column headers matching S3Bucket.TableRow
+var S3BucketTableHeaders = []string{"Bucket Name", "Created At"}
+
+// TableRow returns the bucket as a table row
+func (b S3Bucket) TableRow() []string {
+	return []string{b.Name, b.CreationDate}
+}
+
+func ListS3BucketsModel() ([]S3Bucket, error) {
 	utils.ShowProcessingAnimation("Listing S3 Buckets")
+	buckets, err := FetchS3Buckets()
+	utils.StopAnimation()
 
-	client := utils.GetS3Client()
+	return buckets, err
+}
+
+// AccountS3Bucket is an S3Bucket tagged with the account it was listed from
+type AccountS3Bucket struct {
+	AccountID string `json:"account_id" yaml:"account_id"`
+	Account   string `json:"account" yaml:"account"`
+	S3Bucket  `yaml:",inline"`
+}
+
+// AccountS3BucketTableHeaders are the column headers matching AccountS3Bucket.TableRow
+var AccountS3BucketTableHeaders = append([]string{"Account ID", "Account"}, S3BucketTableHeaders...)
+
+// TableRow returns the bucket as a table row prefixed with its account
+func (b AccountS3Bucket) TableRow() []string {
+	return append([]string{b.AccountID, b.Account}, b.S3Bucket.TableRow()...)
+}
+
+// FetchS3Buckets lists all buckets without any animation
+func FetchS3Buckets() ([]S3Bucket, error) {
+	return fetchS3Buckets(utils.GetS3Client())
+}
+
+// FetchS3BucketsAcrossAccounts lists the buckets of several accounts concurrently.
+// Buckets are returned in account order, together with the accounts that failed.
+func FetchS3BucketsAcrossAccounts(accounts []string) ([]AccountS3Bucket, []utils.AccountError) {
+	return utils.CollectAcrossAccounts(accounts, func(clients *utils.AccountClients) ([]AccountS3Bucket, error) {
+		buckets, err := fetchS3Buckets(clients.S3)
+		if err != nil {
+			return nil, err
+		}
+
+		tagged := make([]AccountS3Bucket, 0, len(buckets))
+		for _, bucket := range buckets {
+			tagged = append(tagged, AccountS3Bucket{AccountID: clients.AccountID, Account: clients.Account, S3Bucket: bucket})
+		}
+		return tagged, nil
+	})
+}
+
+func fetchS3Buckets(client *s3.Client) ([]S3Bucket, error) {
 	ctx := context.TODO()
 
 	input := &s3.ListBucketsInput{}
 	result, err := client.ListBuckets(ctx, input)
 	if err != nil {
-		utils.StopAnimation()
-		println("Error listing S3 buckets:", err.Error())
-		return err.Error()
+		return nil, err
 	}
-	utils.StopAnimation()
 
-	var output strings.Builder
+	buckets := []S3Bucket{}
 	for _, bucket := range result.Buckets {
-		bucketName := ""
-		creationDate := ""
-
+		item := S3Bucket{}
 		if bucket.Name != nil {
-			bucketName = *bucket.Name
+			item.Name = *bucket.Name
 		}
 		if bucket.CreationDate != nil {
-			creationDate = bucket.CreationDate.Format("2006-01-02 15:04:05")
+			item.CreationDate = bucket.CreationDate.Format("2006-01-02 15:04:05")
 		}
-
-		output.WriteString(fmt.Sprintf("%s %s\n", creationDate, bucketName))
+		buckets = append(buckets, item)
 	}
 
-	return output.String()
+	return buckets, nil
 }
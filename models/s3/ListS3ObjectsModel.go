@@ -3,13 +3,30 @@ package s3
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-func S3ListBucketObjects(bucketName string) (string, error) {
+// S3Object is a single row of the object listing
+type S3Object struct {
+	Key          string `json:"key" yaml:"key"`
+	Size         int64  `json:"size" yaml:"size"`
+	LastModified string `json:"last_modified" yaml:"last_modified"`
+}
+
+// S3ObjectTableHeaders are the column headers matching S3Object.TableRow
+var S3ObjectTableHeaders = []string{"Object Name", "Size (Bytes)", "Last Modified"}
+
+// TableRow returns the object as a table row
+func (o S3Object) TableRow() []string {
+	return []string{o.Key, strconv.FormatInt(o.Size, 10), o.LastModified}
+}
+
+// S3ListBucketObjects lists every object in a bucket
+func S3ListBucketObjects(bucketName string) ([]S3Object, error) {
 	client := utils.GetS3Client()
 	ctx := context.TODO()
 
@@ -17,43 +34,33 @@ func S3ListBucketObjects(bucketName string) (string, error) {
 		Bucket: &bucketName,
 	}
 
-	result, err := client.ListObjectsV2(ctx, input)
+	objects := []S3Object{}
+	paginator := s3.NewListObjectsV2Paginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 
-	// Handle errors
-	if err != nil {
-		if strings.Contains(err.Error(), "NoSuchBucket") {
-			return "", fmt.Errorf("the specified bucket '%s' does not exist", bucketName)
-		} else {
-			return "", fmt.Errorf("error fetching objects: %v", err)
+		// Handle errors
+		if err != nil {
+			if strings.Contains(err.Error(), "NoSuchBucket") {
+				return nil, fmt.Errorf("the specified bucket '%s' does not exist", bucketName)
+			}
+			return nil, fmt.Errorf("error fetching objects: %v", err)
 		}
-	}
 
-	// Empty bucket
-	if len(result.Contents) == 0 {
-		return "No objects found in bucket '" + bucketName + "'.", nil
-	}
-
-	// Format output to match AWS CLI format
-	var lines []string
-	for _, obj := range result.Contents {
-		lastModified := ""
-		size := int64(0)
-		key := ""
-
-		if obj.LastModified != nil {
-			lastModified = obj.LastModified.Format("2006-01-02 15:04:05")
+		for _, obj := range page.Contents {
+			item := S3Object{}
+			if obj.LastModified != nil {
+				item.LastModified = obj.LastModified.Format("2006-01-02 15:04:05")
+			}
+			if obj.Size != nil {
+				item.Size = *obj.Size
+			}
+			if obj.Key != nil {
+				item.Key = *obj.Key
+			}
+			objects = append(objects, item)
 		}
-		if obj.Size != nil {
-			size = *obj.Size
-		}
-		if obj.Key != nil {
-			key = *obj.Key
-		}
-
-		line := fmt.Sprintf("%s %10d %s", lastModified, size, key)
-		lines = append(lines, line)
 	}
 
-	// Return the data
-	return strings.Join(lines, "\n"), nil
+	return objects, nil
 }
@@ -3,6 +3,7 @@ package s3
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/DragonEmperor9480/aws_cli_manager/utils"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -10,10 +11,21 @@ import (
 
 // S3Item represents a file or folder in S3
 type S3Item struct {
-	Key          string
-	Size         int64
-	LastModified string
-	IsFolder     bool
+	Key          string `json:"key" yaml:"key"`
+	Size         int64  `json:"size" yaml:"size"`
+	LastModified string `json:"last_modified" yaml:"last_modified"`
+	IsFolder     bool   `json:"is_folder" yaml:"is_folder"`
+}
+
+// S3ItemTableHeaders are the column headers matching S3Item.TableRow
+var S3ItemTableHeaders = []string{"Name", "Size (Bytes)", "Last Modified"}
+
+// TableRow returns the item as a table row, folders have no size
+func (i S3Item) TableRow() []string {
+	if i.IsFolder {
+		return []string{i.Key, "PRE", ""}
+	}
+	return []string{i.Key, strconv.FormatInt(i.Size, 10), i.LastModified}
 }
 
 // ListS3ItemsWithPrefix lists objects in a bucket with a specific prefix (for folder navigation)
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AccountsConfig is the list of accounts used by multi-account listings.
+// Each entry is a named profile or a role ARN to assume.
+type AccountsConfig struct {
+	Accounts []string `json:"accounts"`
+}
+
+// LoadAccounts loads the configured accounts, returning an empty list when none are saved
+func LoadAccounts() ([]string, error) {
+	configDir, err := getConfigDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %v", err)
+	}
+
+	accountsFile := filepath.Join(configDir, "accounts.json")
+
+	data, err := os.ReadFile(accountsFile)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read accounts config: %v", err)
+	}
+
+	var config AccountsConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse accounts config: %v", err)
+	}
+
+	return config.Accounts, nil
+}
+
+// SaveAccounts saves the list of accounts used by multi-account listings
+func SaveAccounts(accounts []string) error {
+	configDir, err := getConfigDirectory()
+	if err != nil {
+		return fmt.Errorf("failed to get config directory: %v", err)
+	}
+
+	accountsFile := filepath.Join(configDir, "accounts.json")
+
+	data, err := json.MarshalIndent(AccountsConfig{Accounts: uniqueAccounts(accounts)}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal accounts: %v", err)
+	}
+
+	if err := os.WriteFile(accountsFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write accounts config file: %v", err)
+	}
+
+	return nil
+}
+
+// ResolveAccounts turns an accounts selector into a list of accounts without duplicates.
+// "all" selects every configured account, anything else is a comma-separated
+// list of profile names and role ARNs. An empty selector returns nil.
+func ResolveAccounts(selector string) ([]string, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return nil, nil
+	}
+
+	if selector == "all" {
+		accounts, err := LoadAccounts()
+		if err != nil {
+			return nil, err
+		}
+		accounts = uniqueAccounts(accounts)
+		if len(accounts) == 0 {
+			return nil, fmt.Errorf("no accounts configured")
+		}
+		return accounts, nil
+	}
+
+	accounts := uniqueAccounts(strings.Split(selector, ","))
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	return accounts, nil
+}
+
+// uniqueAccounts trims the accounts and drops empty and repeated entries, keeping the first
+// occurrence of each
+func uniqueAccounts(accounts []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, account := range accounts {
+		if account = strings.TrimSpace(account); account != "" && !seen[account] {
+			seen[account] = true
+			unique = append(unique, account)
+		}
+	}
+	return unique
+}
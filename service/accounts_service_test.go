@@ -0,0 +1,52 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUniqueAccounts(t *testing.T) {
+	tests := []struct {
+		name     string
+		accounts []string
+		want     []string
+	}{
+		{
+			name: "nil",
+			want: []string{},
+		},
+		{
+			name:     "already unique",
+			accounts: []string{"prod", "dev"},
+			want:     []string{"prod", "dev"},
+		},
+		{
+			name:     "repeats keep the first occurrence",
+			accounts: []string{"dev", "prod", "dev", "staging", "prod"},
+			want:     []string{"dev", "prod", "staging"},
+		},
+		{
+			name:     "entries are trimmed before comparing",
+			accounts: []string{" prod", "prod ", "dev"},
+			want:     []string{"prod", "dev"},
+		},
+		{
+			name:     "empty entries are dropped",
+			accounts: []string{"", "  ", "prod", ""},
+			want:     []string{"prod"},
+		},
+		{
+			name:     "only empty entries",
+			accounts: []string{"", " "},
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := uniqueAccounts(tt.accounts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("uniqueAccounts(%q) = %q, want %q", tt.accounts, got, tt.want)
+			}
+		})
+	}
+}
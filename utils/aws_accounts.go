@@ -0,0 +1,140 @@
+package utils
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// AccountClients holds service clients for one account of a multi-account operation
+type AccountClients struct {
+	Account   string // profile name or role ARN as given by the user
+	AccountID string
+	IAM       *iam.Client
+	S3        *s3.Client
+	Lambda    *lambda.Client
+	STS       *sts.Client
+}
+
+// AccountError reports an account a multi-account operation failed for
+type AccountError struct {
+	Account string `json:"account" yaml:"account"`
+	Error   string `json:"error" yaml:"error"`
+}
+
+var (
+	accountClientsMu sync.Mutex
+	accountClients   = map[string]*AccountClients{}
+)
+
+// IsRoleARN reports whether an account entry is a role ARN rather than a profile name
+func IsRoleARN(account string) bool {
+	return strings.HasPrefix(account, "arn:")
+}
+
+// GetAccountClients returns clients for a profile name or a role ARN.
+// Role ARNs are assumed with the current credentials. Clients are reused until
+// the current profile, region or session changes.
+func GetAccountClients(account string) (*AccountClients, error) {
+	accountClientsMu.Lock()
+	clients, ok := accountClients[account]
+	accountClientsMu.Unlock()
+	if ok {
+		return clients, nil
+	}
+
+	current := currentClients()
+	var cfg aws.Config
+	if IsRoleARN(account) {
+		cfg = current.config.Copy()
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(current.config), account, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = "awsmgr-accounts"
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	} else {
+		var err error
+		cfg, err = LoadAWSConfig(account, current.config.Region)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clients = &AccountClients{
+		Account: account,
+		IAM:     iam.NewFromConfig(cfg),
+		S3:      s3.NewFromConfig(cfg),
+		Lambda:  lambda.NewFromConfig(cfg),
+		STS:     sts.NewFromConfig(cfg),
+	}
+
+	accountID, err := getAccountID(clients.STS)
+	if err != nil {
+		return nil, err
+	}
+	clients.AccountID = accountID
+
+	// Clients built from settings that were switched away meanwhile are not cached
+	accountClientsMu.Lock()
+	if activeClients.Load() == current {
+		accountClients[account] = clients
+	}
+	accountClientsMu.Unlock()
+
+	return clients, nil
+}
+
+// resetAccountClients forgets the clients built for other accounts
+func resetAccountClients() {
+	accountClientsMu.Lock()
+	accountClients = map[string]*AccountClients{}
+	accountClientsMu.Unlock()
+}
+
+// CollectAcrossAccounts runs fetch concurrently for every account and returns the rows of all
+// accounts in account order, together with the accounts it failed for
+func CollectAcrossAccounts[T any](accounts []string, fetch func(clients *AccountClients) ([]T, error)) ([]T, []AccountError) {
+	var wg sync.WaitGroup
+	perAccount := make([][]T, len(accounts))
+	errs := make([]error, len(accounts))
+
+	for i, account := range accounts {
+		wg.Add(1)
+		go func(i int, account string) {
+			defer wg.Done()
+
+			clients, err := GetAccountClients(account)
+			if err == nil {
+				perAccount[i], err = fetch(clients)
+			}
+			errs[i] = err
+		}(i, account)
+	}
+	wg.Wait()
+
+	rows := []T{}
+	failures := []AccountError{}
+	for i, account := range accounts {
+		if errs[i] != nil {
+			failures = append(failures, AccountError{Account: account, Error: errs[i].Error()})
+			continue
+		}
+		rows = append(rows, perAccount[i]...)
+	}
+	return rows, failures
+}
+
+// getAccountID returns the account ID the STS client's credentials belong to
+func getAccountID(client *sts.Client) (string, error) {
+	result, err := client.GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(result.Account), nil
+}
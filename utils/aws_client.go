@@ -2,77 +2,211 @@ package utils
 
 import (
 	"context"
+	"os"
+	"sync"
+	"sync/atomic"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
+// awsClients is one consistent set of service clients together with the settings they were
+// built from. A set is never modified after it is published; switching profile, region or
+// session publishes a new one, so concurrent readers always see matching clients.
+type awsClients struct {
+	// profile and region are the user's selection and override the SDK defaults when set
+	profile string
+	region  string
+
+	config aws.Config
+	ec2    *ec2.Client
+	iam    *iam.Client
+	logs   *cloudwatchlogs.Client
+	lambda *lambda.Client
+	s3     *s3.Client
+	ssm    *ssm.Client
+	sts    *sts.Client
+}
+
 var (
-	IAMClient    *iam.Client
-	LogsClient   *cloudwatchlogs.Client
-	LambdaClient *lambda.Client
-	S3Client     *s3.Client
-	STSClient    *sts.Client
+	activeClients atomic.Pointer[awsClients]
+
+	// switchMu serialises the operations that replace the active clients, so a switch
+	// always starts from the settings the previous one left
+	switchMu sync.Mutex
 )
 
+// currentClients returns the active client set, an empty one before InitAWSClients
+func currentClients() *awsClients {
+	if clients := activeClients.Load(); clients != nil {
+		return clients
+	}
+	return &awsClients{}
+}
+
+// SetAWSTarget selects the named profile and region used by the next InitAWSClients call.
+// Empty values fall back to the SDK defaults (AWS_PROFILE, AWS_REGION, shared config).
+func SetAWSTarget(profile, region string) {
+	switchMu.Lock()
+	defer switchMu.Unlock()
+
+	clients := *currentClients()
+	clients.profile = profile
+	clients.region = region
+	activeClients.Store(&clients)
+}
+
 // InitAWSClients initializes AWS SDK clients
 func InitAWSClients() error {
-	cfg, err := config.LoadDefaultConfig(context.TODO())
+	switchMu.Lock()
+	defer switchMu.Unlock()
+
+	current := currentClients()
+	cfg, err := LoadAWSConfig(current.profile, current.region)
 	if err != nil {
 		return err
 	}
 
-	IAMClient = iam.NewFromConfig(cfg)
-	LogsClient = cloudwatchlogs.NewFromConfig(cfg)
-	LambdaClient = lambda.NewFromConfig(cfg)
-	S3Client = s3.NewFromConfig(cfg)
-	STSClient = sts.NewFromConfig(cfg)
+	setClients(current.profile, current.region, cfg)
 	return nil
 }
 
+// LoadAWSConfig loads the SDK configuration for a profile and region
+func LoadAWSConfig(profile, region string) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+
+	return config.LoadDefaultConfig(context.TODO(), opts...)
+}
+
+// SwitchAWSProfile re-creates all service clients for another profile and region.
+// The current clients are kept if the new configuration cannot be loaded.
+// Any active STS session belongs to the old profile and is dropped.
+func SwitchAWSProfile(profile, region string) error {
+	switchMu.Lock()
+	defer switchMu.Unlock()
+
+	cfg, err := LoadAWSConfig(profile, region)
+	if err != nil {
+		return err
+	}
+
+	clearActiveSession()
+	setClients(profile, region, cfg)
+	return nil
+}
+
+// SwitchAWSRegion re-creates all service clients in another region, keeping the
+// profile and any active STS session
+func SwitchAWSRegion(region string) error {
+	switchMu.Lock()
+	defer switchMu.Unlock()
+
+	profile := currentClients().profile
+	cfg, err := LoadAWSConfig(profile, region)
+	if err != nil {
+		return err
+	}
+
+	setClients(profile, region, sessionConfig(cfg))
+	return nil
+}
+
+// setClients publishes service clients built from cfg for the selected profile and region.
+// Callers hold switchMu.
+func setClients(profile, region string, cfg aws.Config) {
+	activeClients.Store(&awsClients{
+		profile: profile,
+		region:  region,
+		config:  cfg,
+		ec2:     ec2.NewFromConfig(cfg),
+		iam:     iam.NewFromConfig(cfg),
+		logs:    cloudwatchlogs.NewFromConfig(cfg),
+		lambda:  lambda.NewFromConfig(cfg),
+		s3:      s3.NewFromConfig(cfg),
+		ssm:     ssm.NewFromConfig(cfg),
+		sts:     sts.NewFromConfig(cfg),
+	})
+	resetAccountClients()
+}
+
+// GetCurrentProfile returns the selected profile name ("default" when none was chosen)
+func GetCurrentProfile() string {
+	if profile := currentClients().profile; profile != "" {
+		return profile
+	}
+	if envProfile := os.Getenv("AWS_PROFILE"); envProfile != "" {
+		return envProfile
+	}
+	return "default"
+}
+
+// GetCurrentRegion returns the region the service clients are using
+func GetCurrentRegion() string {
+	return currentClients().config.Region
+}
+
+// GetEC2Client returns the EC2 client
+func GetEC2Client() *ec2.Client {
+	return currentClients().ec2
+}
+
 // GetIAMClient returns the IAM client
 func GetIAMClient() *iam.Client {
-	return IAMClient
+	return currentClients().iam
 }
 
 // GetLogsClient returns the CloudWatch Logs client
 func GetLogsClient() *cloudwatchlogs.Client {
-	return LogsClient
+	return currentClients().logs
 }
 
 // GetLambdaClient returns the Lambda client
 func GetLambdaClient() *lambda.Client {
-	return LambdaClient
+	return currentClients().lambda
 }
 
 // GetS3Client returns the S3 client
 func GetS3Client() *s3.Client {
-	return S3Client
+	return currentClients().s3
+}
+
+// GetSSMClient returns the SSM client
+func GetSSMClient() *ssm.Client {
+	return currentClients().ssm
+}
+
+// GetAWSConfig returns the configuration the current service clients were built from
+func GetAWSConfig() aws.Config {
+	return currentClients().config
 }
 
 // GetSTSClient returns the STS client
 func GetSTSClient() *sts.Client {
-	return STSClient
+	return currentClients().sts
 }
 
 // GetAWSAccountID returns the AWS account ID
 func GetAWSAccountID() (string, error) {
-	ctx := context.TODO()
-	result, err := STSClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
-	if err != nil {
-		return "", err
-	}
-	return *result.Account, nil
+	return getAccountID(GetSTSClient())
 }
 
 // GetAWSAccountAlias returns the first account alias if available, otherwise empty string
 func GetAWSAccountAlias() (string, error) {
 	ctx := context.TODO()
-	result, err := IAMClient.ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+	result, err := GetIAMClient().ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
 	if err != nil {
 		return "", err
 	}
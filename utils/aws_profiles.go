@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// AWSProfile is a named profile found in the shared config or credentials file
+type AWSProfile struct {
+	Name   string `json:"name" yaml:"name"`
+	Region string `json:"region" yaml:"region"`
+}
+
+// AWSProfileTableHeaders are the column headers matching AWSProfile.TableRow
+var AWSProfileTableHeaders = []string{"Profile", "Region"}
+
+// TableRow returns the profile as a table row
+func (p AWSProfile) TableRow() []string {
+	return []string{p.Name, p.Region}
+}
+
+// ListAWSProfiles reads the profile sections of ~/.aws/config and ~/.aws/credentials
+// (or the files named by AWS_CONFIG_FILE and AWS_SHARED_CREDENTIALS_FILE)
+func ListAWSProfiles() ([]AWSProfile, error) {
+	regions := map[string]string{}
+
+	configPath := os.Getenv("AWS_CONFIG_FILE")
+	if configPath == "" {
+		configPath = config.DefaultSharedConfigFilename()
+	}
+	configSections, err := readINISections(configPath)
+	if err != nil {
+		return nil, err
+	}
+	for section, values := range configSections {
+		name := section
+		if section != "default" {
+			// Only "profile <name>" sections describe profiles in the config file
+			if !strings.HasPrefix(section, "profile ") {
+				continue
+			}
+			name = strings.TrimSpace(strings.TrimPrefix(section, "profile "))
+		}
+		regions[name] = values["region"]
+	}
+
+	credentialsPath := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if credentialsPath == "" {
+		credentialsPath = config.DefaultSharedCredentialsFilename()
+	}
+	credentialSections, err := readINISections(credentialsPath)
+	if err != nil {
+		return nil, err
+	}
+	for section := range credentialSections {
+		if _, ok := regions[section]; !ok {
+			regions[section] = ""
+		}
+	}
+
+	profiles := make([]AWSProfile, 0, len(regions))
+	for name, region := range regions {
+		profiles = append(profiles, AWSProfile{Name: name, Region: region})
+	}
+
+	// Keep "default" first, the rest alphabetically
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].Name == "default" || profiles[j].Name == "default" {
+			return profiles[i].Name == "default"
+		}
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles, nil
+}
+
+// readINISections parses an AWS style INI file into section -> key -> value.
+// A missing file is not an error.
+func readINISections(path string) (map[string]map[string]string, error) {
+	sections := map[string]map[string]string{}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sections, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	current := ""
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			current = strings.TrimSpace(line[1 : len(line)-1])
+			if _, ok := sections[current]; !ok {
+				sections[current] = map[string]string{}
+			}
+			continue
+		}
+
+		if current == "" {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			sections[current][strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return sections, scanner.Err()
+}
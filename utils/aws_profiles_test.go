@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadINISections(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]map[string]string
+	}{
+		{
+			name: "credentials file",
+			content: `[default]
+aws_access_key_id = AKIAEXAMPLE
+aws_secret_access_key = secret
+
+[work]
+aws_access_key_id=AKIAWORK
+`,
+			want: map[string]map[string]string{
+				"default": {"aws_access_key_id": "AKIAEXAMPLE", "aws_secret_access_key": "secret"},
+				"work":    {"aws_access_key_id": "AKIAWORK"},
+			},
+		},
+		{
+			name: "config file with profile prefix kept",
+			content: `[profile dev]
+region = eu-west-1
+output = json
+`,
+			want: map[string]map[string]string{
+				"profile dev": {"region": "eu-west-1", "output": "json"},
+			},
+		},
+		{
+			name: "comments, blank lines and keys before a section are skipped",
+			content: `# top comment
+orphan = value
+
+  [ default ]
+; another comment
+region = us-east-1
+not a key
+`,
+			want: map[string]map[string]string{
+				"default": {"region": "us-east-1"},
+			},
+		},
+		{
+			name: "only the first '=' separates",
+			content: `[default]
+role_arn = arn:aws:iam::123456789012:role/a=b
+`,
+			want: map[string]map[string]string{
+				"default": {"role_arn": "arn:aws:iam::123456789012:role/a=b"},
+			},
+		},
+		{
+			name: "repeated section is merged, later keys win",
+			content: `[default]
+region = us-east-1
+output = json
+[default]
+region = eu-central-1
+`,
+			want: map[string]map[string]string{
+				"default": {"region": "eu-central-1", "output": "json"},
+			},
+		},
+		{
+			name:    "empty section",
+			content: "[empty]\n",
+			want:    map[string]map[string]string{"empty": {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := readINISections(path)
+			if err != nil {
+				t.Fatalf("readINISections() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readINISections() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("missing file", func(t *testing.T) {
+		got, err := readINISections(filepath.Join(t.TempDir(), "missing"))
+		if err != nil {
+			t.Fatalf("readINISections() error = %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("readINISections() = %v, want no sections", got)
+		}
+	})
+}
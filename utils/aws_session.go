@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Session types reported in AWSSession.Type
+const (
+	SessionTypeMFA        = "mfa"
+	SessionTypeAssumeRole = "assume-role"
+)
+
+// sessionRefreshWindow is how long before expiry cached credentials stop being reused
+const sessionRefreshWindow = 2 * time.Minute
+
+// SessionOptions describes how to obtain temporary STS credentials.
+// With an empty RoleARN GetSessionToken is used, which requires MFASerial and TokenCode.
+type SessionOptions struct {
+	RoleARN         string
+	ExternalID      string
+	SessionName     string
+	MFASerial       string
+	TokenCode       string
+	DurationSeconds int32
+}
+
+// AWSSession describes the STS session the service clients are currently using
+type AWSSession struct {
+	Type       string    `json:"type" yaml:"type"`
+	Profile    string    `json:"profile" yaml:"profile"`
+	RoleARN    string    `json:"role_arn,omitempty" yaml:"role_arn,omitempty"`
+	MFASerial  string    `json:"mfa_serial,omitempty" yaml:"mfa_serial,omitempty"`
+	Expiration time.Time `json:"expiration" yaml:"expiration"`
+}
+
+// Expired reports whether the session credentials are no longer valid
+func (s *AWSSession) Expired() bool {
+	return !time.Now().Before(s.Expiration)
+}
+
+var (
+	sessionMu    sync.Mutex
+	activeSess   *AWSSession
+	activeCreds  aws.Credentials
+	sessionCache = map[string]aws.Credentials{}
+)
+
+// sessionCredentialsProvider serves fixed STS credentials until they expire
+type sessionCredentialsProvider struct {
+	creds aws.Credentials
+}
+
+func (p sessionCredentialsProvider) Retrieve(ctx context.Context) (aws.Credentials, error) {
+	if p.creds.Expired() {
+		return aws.Credentials{}, fmt.Errorf("STS session credentials expired at %s; start a new session",
+			p.creds.Expires.Local().Format(time.RFC1123))
+	}
+	return p.creds, nil
+}
+
+// sessionCacheKey identifies credentials obtained for the same profile and options
+func sessionCacheKey(profile string, opts SessionOptions) string {
+	if opts.RoleARN == "" {
+		return profile + "|" + SessionTypeMFA + "|" + opts.MFASerial
+	}
+	return profile + "|" + SessionTypeAssumeRole + "|" + opts.RoleARN + "|" + opts.ExternalID + "|" + opts.MFASerial
+}
+
+// HasCachedAWSSession reports whether StartAWSSession can reuse cached credentials
+// for opts, so no new MFA code is needed
+func HasCachedAWSSession(opts SessionOptions) bool {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	creds, ok := sessionCache[sessionCacheKey(currentClients().profile, opts)]
+	return ok && creds.Expires.After(time.Now().Add(sessionRefreshWindow))
+}
+
+// StartAWSSession obtains STS session credentials (GetSessionToken or AssumeRole)
+// for the selected profile and rebuilds every service client with them.
+// Credentials are cached per profile and options and reused until they expire.
+func StartAWSSession(opts SessionOptions) (*AWSSession, error) {
+	if opts.RoleARN == "" && opts.MFASerial == "" {
+		return nil, errors.New("a role ARN or an MFA device is required")
+	}
+
+	switchMu.Lock()
+	defer switchMu.Unlock()
+
+	current := currentClients()
+	base, err := LoadAWSConfig(current.profile, current.region)
+	if err != nil {
+		return nil, err
+	}
+
+	key := sessionCacheKey(current.profile, opts)
+
+	sessionMu.Lock()
+	creds, ok := sessionCache[key]
+	sessionMu.Unlock()
+
+	if !ok || !creds.Expires.After(time.Now().Add(sessionRefreshWindow)) {
+		if opts.MFASerial != "" && opts.TokenCode == "" {
+			return nil, errors.New("MFA code is required")
+		}
+
+		creds, err = requestSessionCredentials(sts.NewFromConfig(base), opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sess := &AWSSession{
+		Type:       SessionTypeMFA,
+		Profile:    GetCurrentProfile(),
+		RoleARN:    opts.RoleARN,
+		MFASerial:  opts.MFASerial,
+		Expiration: creds.Expires,
+	}
+	if opts.RoleARN != "" {
+		sess.Type = SessionTypeAssumeRole
+	}
+
+	sessionMu.Lock()
+	sessionCache[key] = creds
+	activeSess = sess
+	activeCreds = creds
+	sessionMu.Unlock()
+
+	setClients(current.profile, current.region, withSessionCredentials(base, creds))
+	return sess, nil
+}
+
+// requestSessionCredentials calls STS for a new set of temporary credentials
+func requestSessionCredentials(client *sts.Client, opts SessionOptions) (aws.Credentials, error) {
+	ctx := context.TODO()
+
+	if opts.RoleARN == "" {
+		input := &sts.GetSessionTokenInput{
+			SerialNumber: aws.String(opts.MFASerial),
+			TokenCode:    aws.String(opts.TokenCode),
+		}
+		if opts.DurationSeconds > 0 {
+			input.DurationSeconds = aws.Int32(opts.DurationSeconds)
+		}
+
+		result, err := client.GetSessionToken(ctx, input)
+		if err != nil {
+			return aws.Credentials{}, err
+		}
+		return toAWSCredentials(result.Credentials.AccessKeyId, result.Credentials.SecretAccessKey,
+			result.Credentials.SessionToken, result.Credentials.Expiration), nil
+	}
+
+	sessionName := opts.SessionName
+	if sessionName == "" {
+		sessionName = "awsmgr-" + strconv.FormatInt(time.Now().Unix(), 10)
+	}
+
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(opts.RoleARN),
+		RoleSessionName: aws.String(sessionName),
+	}
+	if opts.ExternalID != "" {
+		input.ExternalId = aws.String(opts.ExternalID)
+	}
+	if opts.MFASerial != "" {
+		input.SerialNumber = aws.String(opts.MFASerial)
+		input.TokenCode = aws.String(opts.TokenCode)
+	}
+	if opts.DurationSeconds > 0 {
+		input.DurationSeconds = aws.Int32(opts.DurationSeconds)
+	}
+
+	result, err := client.AssumeRole(ctx, input)
+	if err != nil {
+		return aws.Credentials{}, err
+	}
+	return toAWSCredentials(result.Credentials.AccessKeyId, result.Credentials.SecretAccessKey,
+		result.Credentials.SessionToken, result.Credentials.Expiration), nil
+}
+
+func toAWSCredentials(accessKeyID, secretAccessKey, sessionToken *string, expiration *time.Time) aws.Credentials {
+	return aws.Credentials{
+		AccessKeyID:     aws.ToString(accessKeyID),
+		SecretAccessKey: aws.ToString(secretAccessKey),
+		SessionToken:    aws.ToString(sessionToken),
+		Source:          "awsmgr-sts-session",
+		CanExpire:       true,
+		Expires:         aws.ToTime(expiration),
+	}
+}
+
+// withSessionCredentials returns a copy of cfg that signs requests with creds
+func withSessionCredentials(cfg aws.Config, creds aws.Credentials) aws.Config {
+	cfg.Credentials = aws.NewCredentialsCache(sessionCredentialsProvider{creds: creds})
+	return cfg
+}
+
+// EndAWSSession drops the active STS session and its cached credentials and
+// rebuilds the service clients with the profile's own credentials
+func EndAWSSession() error {
+	switchMu.Lock()
+	defer switchMu.Unlock()
+
+	current := currentClients()
+	cfg, err := LoadAWSConfig(current.profile, current.region)
+	if err != nil {
+		return err
+	}
+
+	sessionMu.Lock()
+	activeSess = nil
+	activeCreds = aws.Credentials{}
+	sessionCache = map[string]aws.Credentials{}
+	sessionMu.Unlock()
+
+	setClients(current.profile, current.region, cfg)
+	return nil
+}
+
+// GetAWSSession returns the active STS session, or nil when the profile's own credentials are used
+func GetAWSSession() *AWSSession {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	if activeSess == nil {
+		return nil
+	}
+	sess := *activeSess
+	return &sess
+}
+
+// clearActiveSession forgets the active session without touching the cache
+func clearActiveSession() {
+	sessionMu.Lock()
+	activeSess = nil
+	activeCreds = aws.Credentials{}
+	sessionMu.Unlock()
+}
+
+// sessionConfig applies the active session credentials to cfg, if there is a session
+func sessionConfig(cfg aws.Config) aws.Config {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	if activeSess == nil {
+		return cfg
+	}
+	return withSessionCredentials(cfg, activeCreds)
+}
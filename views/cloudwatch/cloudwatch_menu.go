@@ -11,6 +11,7 @@ func ShowCloudWatchMenu() {
 	fmt.Println(utils.Bold + utils.Cyan + "CloudWatch Management" + utils.Reset)
 	fmt.Println("────────────────────────────────────")
 	fmt.Println(utils.Bold + utils.Blue + "[1]" + utils.Reset + " Live Tail Lambda Logs")
-	fmt.Println(utils.Bold + utils.Red + "[2]" + utils.Reset + " Back to Main Menu")
+	fmt.Println(utils.Bold + utils.Blue + "[2]" + utils.Reset + " List Lambda Functions")
+	fmt.Println(utils.Bold + utils.Red + "[3]" + utils.Reset + " Back to Main Menu")
 	fmt.Println("────────────────────────────────────")
 }
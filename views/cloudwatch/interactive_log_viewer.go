@@ -22,6 +22,7 @@ type LogViewer struct {
 	scrollPos      int
 	paused         bool
 	functionName   string
+	title          string // replaces the "Live Tail" header when set
 	ctx            context.Context
 	cancel         context.CancelFunc
 	searchMode     bool
@@ -66,6 +67,11 @@ func (lv *LogViewer) Close() {
 	lv.screen.Fini()
 }
 
+// SetTitle replaces the "Live Tail" header, for viewers that show other streams
+func (lv *LogViewer) SetTitle(title string) {
+	lv.title = title
+}
+
 func (lv *LogViewer) AddLog(log LogEntry) {
 	if !lv.paused {
 		lv.logsMutex.Lock()
@@ -259,6 +265,9 @@ func (lv *LogViewer) Render() {
 	// Header
 	headerStyle := tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite).Bold(true)
 	header := fmt.Sprintf(" Live Tail: %s ", lv.functionName)
+	if lv.title != "" {
+		header = " " + lv.title + " "
+	}
 	for i, ch := range header {
 		lv.screen.SetContent(i, 0, ch, nil, headerStyle)
 	}
@@ -427,6 +436,83 @@ func (lv *LogViewer) AutoScroll() {
 	}()
 }
 
+// Run handles key and resize events until the user quits the viewer
+func (lv *LogViewer) Run() {
+	// Event loop
+	searchMode := false
+	for {
+		ev := lv.screen.PollEvent()
+		switch ev := ev.(type) {
+		case *tcell.EventResize:
+			lv.screen.Sync()
+			lv.Render()
+		case *tcell.EventKey:
+			if searchMode {
+				// Handle search mode keys
+				switch ev.Key() {
+				case tcell.KeyEscape:
+					lv.ToggleSearchMode()
+					searchMode = false
+					lv.Render()
+				case tcell.KeyBackspace, tcell.KeyBackspace2:
+					lv.RemoveFromSearchQuery()
+					lv.Render()
+				case tcell.KeyEnter:
+					lv.NextMatch()
+					lv.Render()
+				case tcell.KeyRune:
+					switch ev.Rune() {
+					case 'w', 'W':
+						lv.PrevMatch()
+						lv.Render()
+					case 's', 'S':
+						lv.NextMatch()
+						lv.Render()
+					default:
+						lv.AddToSearchQuery(ev.Rune())
+						lv.Render()
+					}
+				}
+			} else {
+				// Handle normal mode keys
+				switch ev.Key() {
+				case tcell.KeyEscape, tcell.KeyCtrlC:
+					return
+				case tcell.KeyRune:
+					switch ev.Rune() {
+					case 'q', 'Q':
+						return
+					case ' ':
+						lv.TogglePause()
+						lv.Render()
+					case 'r', 'R':
+						lv.ResetLogs()
+						lv.Render()
+					case 'f', 'F', '/':
+						lv.ToggleSearchMode()
+						searchMode = true
+						lv.Render()
+					}
+				case tcell.KeyUp:
+					lv.ScrollUp()
+					lv.Render()
+				case tcell.KeyDown:
+					lv.ScrollDown()
+					lv.Render()
+				case tcell.KeyPgUp:
+					lv.PageUp()
+					lv.Render()
+				case tcell.KeyPgDn:
+					lv.PageDown()
+					lv.Render()
+				}
+			}
+		case *tcell.EventInterrupt:
+			lv.Render()
+		}
+	}
+}
+
 func (lv *LogViewer) GetContext() context.Context {
 	return lv.ctx
 }
@@ -12,14 +12,41 @@ func ShowEC2Menu() {
 	fmt.Println(utils.Green + "└──────────────────────────────────────────────┘" + utils.Reset)
 
 	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Instance Management:" + utils.Reset)
 	fmt.Println("  " + utils.Bold + "1)" + utils.Reset + " Launch EC2 Instance")
-	fmt.Println("  " + utils.Bold + "2)" + utils.Reset + " Stop EC2 Instance")
-	fmt.Println("  " + utils.Bold + "3)" + utils.Reset + " Terminate EC2 Instance")
-	fmt.Println("  " + utils.Bold + "4)" + utils.Reset + " List all EC2 Instances")
-	fmt.Println("  " + utils.Bold + "5)" + utils.Reset + " List All AMI")
-	fmt.Println("  " + utils.Bold + "6)" + utils.Reset + " Attach Volume")
-	fmt.Println("  " + utils.Bold + "7)" + utils.Reset + " Detach Volume")
-	fmt.Println("  " + utils.Bold + "8)" + utils.Reset + " Create Snapshot")
-	fmt.Println("  " + utils.Bold + "9)" + utils.Reset + " Delete Snapshot")
+	fmt.Println("  " + utils.Bold + "2)" + utils.Reset + " List all EC2 Instances")
+	fmt.Println("  " + utils.Bold + "3)" + utils.Reset + " Start EC2 Instance")
+	fmt.Println("  " + utils.Bold + "4)" + utils.Reset + " Stop EC2 Instance")
+	fmt.Println("  " + utils.Bold + "5)" + utils.Reset + " Reboot EC2 Instance")
+	fmt.Println("  " + utils.Bold + "6)" + utils.Reset + " Terminate EC2 Instance")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Volume & Snapshot Management:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "7)" + utils.Reset + "  List EBS Volumes")
+	fmt.Println("  " + utils.Bold + "8)" + utils.Reset + "  Create Volume")
+	fmt.Println("  " + utils.Bold + "9)" + utils.Reset + "  Attach Volume")
+	fmt.Println("  " + utils.Bold + "10)" + utils.Reset + " Detach Volume")
+	fmt.Println("  " + utils.Bold + "11)" + utils.Reset + " Delete Volume")
+	fmt.Println("  " + utils.Bold + "12)" + utils.Reset + " List Snapshots")
+	fmt.Println("  " + utils.Bold + "13)" + utils.Reset + " Create Snapshot")
+	fmt.Println("  " + utils.Bold + "14)" + utils.Reset + " Copy Snapshot to Region")
+	fmt.Println("  " + utils.Bold + "15)" + utils.Reset + " Delete Snapshot")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "AMI Management:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "16)" + utils.Reset + " List All AMI")
+	fmt.Println("  " + utils.Bold + "17)" + utils.Reset + " Deregister AMI(s) and Snapshots")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Network Security:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "18)" + utils.Reset + " VPC Security Groups")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Remote Access (SSM):" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "19)" + utils.Reset + " List SSM Managed Instances")
+	fmt.Println("  " + utils.Bold + "20)" + utils.Reset + " Start Shell Session")
+	fmt.Println("  " + utils.Bold + "21)" + utils.Reset + " Run Shell Command on Instance(s)")
+	fmt.Println()
+	fmt.Println("  " + utils.Bold + "22)" + utils.Reset + " Back to Main Menu")
 	fmt.Println()
 }
@@ -0,0 +1,21 @@
+package iamview
+
+import (
+	"fmt"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+)
+
+func ShowSecurityGroupMenu() {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Security Group Management" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+	fmt.Println(utils.Bold + utils.Blue + "[1]" + utils.Reset + " List VPCs")
+	fmt.Println(utils.Bold + utils.Blue + "[2]" + utils.Reset + " List Security Groups by VPC")
+	fmt.Println(utils.Bold + utils.Blue + "[3]" + utils.Reset + " View Security Group Rules")
+	fmt.Println(utils.Bold + utils.Blue + "[4]" + utils.Reset + " Add Rule")
+	fmt.Println(utils.Bold + utils.Blue + "[5]" + utils.Reset + " Revoke Rule")
+	fmt.Println(utils.Bold + utils.Blue + "[6]" + utils.Reset + " Find Risky Rules (SSH/RDP open to the internet)")
+	fmt.Println(utils.Bold + utils.Red + "[7]" + utils.Reset + " Back to EC2 Menu")
+	fmt.Println("────────────────────────────────────")
+}
@@ -0,0 +1,24 @@
+package iamview
+
+import (
+	"fmt"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+)
+
+func ShowPoliciesMenu() {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "Customer-Managed Policies" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+	fmt.Println(utils.Bold + utils.Blue + "[1]" + utils.Reset + " List Policies")
+	fmt.Println(utils.Bold + utils.Blue + "[2]" + utils.Reset + " View Policy Document")
+	fmt.Println(utils.Bold + utils.Blue + "[3]" + utils.Reset + " Create Policy (file or editor)")
+	fmt.Println(utils.Bold + utils.Blue + "[4]" + utils.Reset + " Update Policy (new default version)")
+	fmt.Println(utils.Bold + utils.Blue + "[5]" + utils.Reset + " List Policy Versions")
+	fmt.Println(utils.Bold + utils.Blue + "[6]" + utils.Reset + " Diff Two Versions")
+	fmt.Println(utils.Bold + utils.Blue + "[7]" + utils.Reset + " Roll Back Default Version")
+	fmt.Println(utils.Bold + utils.Blue + "[8]" + utils.Reset + " Delete Policy Version")
+	fmt.Println(utils.Bold + utils.Blue + "[9]" + utils.Reset + " Delete Policy")
+	fmt.Println(utils.Bold + utils.Red + "[10]" + utils.Reset + " Back to IAM Menu")
+	fmt.Println("────────────────────────────────────")
+}
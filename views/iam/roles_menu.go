@@ -0,0 +1,29 @@
+package iamview
+
+import (
+	"fmt"
+
+	"github.com/DragonEmperor9480/aws_cli_manager/utils"
+)
+
+func ShowRolesMenu() {
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Cyan + "IAM Roles" + utils.Reset)
+	fmt.Println("────────────────────────────────────")
+	fmt.Println(utils.Bold + utils.Blue + "[1]" + utils.Reset + " List Roles")
+	fmt.Println(utils.Bold + utils.Blue + "[2]" + utils.Reset + " Create Role")
+	fmt.Println(utils.Bold + utils.Blue + "[3]" + utils.Reset + " View/Edit Trust Policy")
+	fmt.Println(utils.Bold + utils.Blue + "[4]" + utils.Reset + " List Attached Policies")
+	fmt.Println(utils.Bold + utils.Blue + "[5]" + utils.Reset + " Attach Managed Policy")
+	fmt.Println(utils.Bold + utils.Blue + "[6]" + utils.Reset + " Detach Managed Policy")
+	fmt.Println(utils.Bold + utils.Blue + "[7]" + utils.Reset + " Delete Role")
+	fmt.Println()
+	fmt.Println(utils.Bold + "Instance Profiles" + utils.Reset)
+	fmt.Println(utils.Bold + utils.Blue + "[8]" + utils.Reset + " List Instance Profiles")
+	fmt.Println(utils.Bold + utils.Blue + "[9]" + utils.Reset + " Create Instance Profile")
+	fmt.Println(utils.Bold + utils.Blue + "[10]" + utils.Reset + " Link Role to Instance Profile")
+	fmt.Println(utils.Bold + utils.Blue + "[11]" + utils.Reset + " Unlink Role from Instance Profile")
+	fmt.Println(utils.Bold + utils.Blue + "[12]" + utils.Reset + " Delete Instance Profile")
+	fmt.Println(utils.Bold + utils.Red + "[13]" + utils.Reset + " Back to IAM Menu")
+	fmt.Println("────────────────────────────────────")
+}
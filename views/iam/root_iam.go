@@ -13,25 +13,66 @@ func ShowIAMMenu() {
 
 	fmt.Println()
 	fmt.Println(utils.Bold + utils.Yellow + "User Management:" + utils.Reset)
-	fmt.Println("  " + utils.Bold + "1)" + utils.Reset + " Create IAM User")
-	fmt.Println("  " + utils.Bold + "2)" + utils.Reset + " List IAM Users")
-	fmt.Println("  " + utils.Bold + "3)" + utils.Reset + " Add User to a Group")
-	fmt.Println("  " + utils.Bold + "4)" + utils.Reset + " Delete IAM User")
-	fmt.Println("  " + utils.Bold + "5)" + utils.Reset + " Set Initial password for IAM user")
-	fmt.Println("  " + utils.Bold + "6)" + utils.Reset + " Change password for IAM user")
-	fmt.Println("  " + utils.Bold + "7)" + utils.Reset + " Create access key (BETA)")
-	fmt.Println("  " + utils.Bold + "8)" + utils.Reset + " list access key(s) (WIP)")
-	fmt.Println("  " + utils.Bold + "9)" + utils.Reset + " delete access key (TO DO)")
+	fmt.Println("  " + utils.Bold + "1)" + utils.Reset + "  Create IAM User")
+	fmt.Println("  " + utils.Bold + "2)" + utils.Reset + "  List IAM Users")
+	fmt.Println("  " + utils.Bold + "3)" + utils.Reset + "  Add User to a Group")
+	fmt.Println("  " + utils.Bold + "4)" + utils.Reset + "  Delete IAM User")
+	fmt.Println("  " + utils.Bold + "5)" + utils.Reset + "  Set Initial password for IAM user")
+	fmt.Println("  " + utils.Bold + "6)" + utils.Reset + "  Change password for IAM user")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Access Keys:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "7)" + utils.Reset + "  Create access key")
+	fmt.Println("  " + utils.Bold + "8)" + utils.Reset + "  List access keys")
+	fmt.Println("  " + utils.Bold + "9)" + utils.Reset + "  Activate/deactivate access key")
+	fmt.Println("  " + utils.Bold + "10)" + utils.Reset + " Delete access key")
+	fmt.Println("  " + utils.Bold + "11)" + utils.Reset + " Rotate access key")
+	fmt.Println("  " + utils.Bold + "12)" + utils.Reset + " Access key age & last-used report")
 
 	fmt.Println()
 	fmt.Println(utils.Bold + utils.Yellow + "Group Management:" + utils.Reset)
-	fmt.Println("  " + utils.Bold + "9)" + utils.Reset + "  Create IAM Group")
-	fmt.Println("  " + utils.Bold + "10)" + utils.Reset + " List IAM Groups")
-	fmt.Println("  " + utils.Bold + "11)" + utils.Reset + " Check Total Users in a Group")
-	fmt.Println("  " + utils.Bold + "12)" + utils.Reset + " List Groups a User Belongs To")
-	fmt.Println("  " + utils.Bold + "13)" + utils.Reset + " Delete IAM Group")
-	fmt.Println("  " + utils.Bold + "14)" + utils.Reset + " Remove User from Group")
-	fmt.Println()
-	fmt.Println("  " + utils.Bold + "15)" + utils.Reset + " Back to Main Menu")
+	fmt.Println("  " + utils.Bold + "13)" + utils.Reset + " Create IAM Group")
+	fmt.Println("  " + utils.Bold + "14)" + utils.Reset + " List IAM Groups")
+	fmt.Println("  " + utils.Bold + "15)" + utils.Reset + " Check Total Users in a Group")
+	fmt.Println("  " + utils.Bold + "16)" + utils.Reset + " List Groups a User Belongs To")
+	fmt.Println("  " + utils.Bold + "17)" + utils.Reset + " Delete IAM Group")
+	fmt.Println("  " + utils.Bold + "18)" + utils.Reset + " Remove User from Group")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Role Management:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "19)" + utils.Reset + " Roles, trust policies and instance profiles")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "MFA Devices:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "20)" + utils.Reset + " Enroll virtual MFA device")
+	fmt.Println("  " + utils.Bold + "21)" + utils.Reset + " List MFA devices")
+	fmt.Println("  " + utils.Bold + "22)" + utils.Reset + " Deactivate MFA device")
+	fmt.Println("  " + utils.Bold + "23)" + utils.Reset + " Delete virtual MFA device")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Policies:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "24)" + utils.Reset + " Customer-managed policies (versions, diff, rollback)")
+	fmt.Println("  " + utils.Bold + "25)" + utils.Reset + " Inline policies of a user, group or role")
+	fmt.Println("  " + utils.Bold + "26)" + utils.Reset + " Simulate permissions (can X do Y on Z?)")
+	fmt.Println("  " + utils.Bold + "27)" + utils.Reset + " Permissions boundary of a user")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Account:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "28)" + utils.Reset + " View/edit password policy")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Reports:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "29)" + utils.Reset + " Credential report (console access, MFA, keys)")
+	fmt.Println("  " + utils.Bold + "30)" + utils.Reset + " Hygiene report (inactive users, empty groups, unused policies)")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Onboarding:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "31)" + utils.Reset + " Import users from CSV/JSON (groups, policies, tags, emailed passwords)")
+
+	fmt.Println()
+	fmt.Println(utils.Bold + utils.Yellow + "Tags:" + utils.Reset)
+	fmt.Println("  " + utils.Bold + "32)" + utils.Reset + " List, add or remove tags of a user or role")
+	fmt.Println()
+	fmt.Println("  " + utils.Bold + "33)" + utils.Reset + " Back to Main Menu")
 	fmt.Println()
 }
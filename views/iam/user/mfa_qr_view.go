@@ -0,0 +1,18 @@
+package userview
+
+import (
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// PrintQRCode prints content as a QR code made of half-block characters,
+// small enough to be scanned from a standard terminal window
+func PrintQRCode(content string) error {
+	code, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return err
+	}
+	fmt.Println(code.ToSmallString(false))
+	return nil
+}
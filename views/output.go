@@ -0,0 +1,79 @@
+package views
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Supported output formats for listings
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputCSV   = "csv"
+)
+
+// TableRower is implemented by model types that can be shown as a table row
+type TableRower interface {
+	TableRow() []string
+}
+
+// RowsOf converts typed model items into table rows
+func RowsOf[T TableRower](items []T) [][]string {
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, item.TableRow())
+	}
+	return rows
+}
+
+// ParseOutputFormat validates an output format name, defaulting to table
+func ParseOutputFormat(format string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", OutputTable:
+		return OutputTable, nil
+	case OutputJSON:
+		return OutputJSON, nil
+	case OutputYAML, "yml":
+		return OutputYAML, nil
+	case OutputCSV:
+		return OutputCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported output format '%s' (use json, yaml, csv or table)", format)
+	}
+}
+
+// RenderOutput writes data in the requested format.
+// JSON and YAML marshal data directly, CSV and table use the headers and rows of config.
+func RenderOutput(w io.Writer, format string, data interface{}, config TableConfig) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(data)
+	case OutputYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case OutputCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(config.Headers); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(config.Rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	default:
+		_, err := fmt.Fprintln(w, buildTable(config).Render())
+		return err
+	}
+}
@@ -16,6 +16,13 @@ type TableConfig struct {
 
 // RenderTable renders a beautiful, consistent table across the application
 func RenderTable(config TableConfig) {
+	fmt.Println()
+	fmt.Println(buildTable(config).Render())
+	fmt.Println()
+}
+
+// buildTable prepares the styled table writer used by RenderTable
+func buildTable(config TableConfig) table.Writer {
 	t := table.NewWriter()
 
 	// Add serial number to headers
@@ -82,9 +89,7 @@ func RenderTable(config TableConfig) {
 
 	t.SetColumnConfigs(columnConfigs)
 
-	fmt.Println()
-	fmt.Println(t.Render())
-	fmt.Println()
+	return t
 }
 
 // interfaceSlice converts a string slice to interface slice